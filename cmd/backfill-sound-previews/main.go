@@ -0,0 +1,117 @@
+// backfill-sound-previews は試聴用のファイルや波形のプレビューが無い (または波形の点の数が古い) サウンドについて、
+// 保存されている加工後の音声から波形と試聴用のファイルを作り直して保存する。
+//
+//	go run ./cmd/backfill-sound-previews -dry-run
+//
+// 試聴用のファイルの形式はこのコマンドのビルドに従う (-tags opus,nolibopusfile を付けると Ogg Opus になる)。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+)
+
+// maxProcessedBytes は読み込む加工後のファイルのサイズの上限 (48kHz モノラル 16bit の 20秒は約 1.9MB)
+const maxProcessedBytes = 64 << 20
+
+func main() {
+	batch := flag.Int("batch", 100, "1回に DB から取得するサウンドの数")
+	dryRun := flag.Bool("dry-run", false, "対象のサウンドを表示するだけで、ファイルや DB を変更しない")
+	verbose := flag.Bool("v", false, "処理したサウンドを1件ずつ標準エラー出力に表示する")
+	flag.Parse()
+	if *batch <= 0 {
+		log.Fatal("-batch must be positive")
+	}
+
+	db, err := sqlx.Connect("mysql", config.MySQL().FormatDSN())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	repo := repository.New(db, config.LoadLivekitConfig())
+	fileService, err := repository.NewFileService(config.NewStorageConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	updated, failed := 0, 0
+	for afterID := ""; ; {
+		sounds, err := repo.GetSoundsWithoutPreview(afterID, *batch)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(sounds) == 0 {
+			break
+		}
+		afterID = sounds[len(sounds)-1].SoundID
+
+		for _, sound := range sounds {
+			if *dryRun {
+				fmt.Fprintf(os.Stderr, "would backfill %s (%s)\n", sound.SoundID, sound.SoundName)
+				continue
+			}
+			if err := backfill(repo, fileService, sound); err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "failed %s (%s): %v\n", sound.SoundID, sound.SoundName, err)
+				continue
+			}
+			updated++
+			if *verbose {
+				fmt.Fprintf(os.Stderr, "backfilled %s (%s)\n", sound.SoundID, sound.SoundName)
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "backfilled %d sounds (%d failed)\n", updated, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// backfill は加工後のファイル (キーは SoundID) をデコードし、波形と試聴用のファイルを作って保存する
+func backfill(repo *repository.Repository, fileService *repository.FileService, sound repository.Sound) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	data, err := fileService.ReadFile(ctx, sound.SoundID, maxProcessedBytes)
+	if err != nil {
+		return fmt.Errorf("read processed file: %w", err)
+	}
+	clip, err := audio.Decode(data)
+	if err != nil {
+		return fmt.Errorf("decode processed file: %w", err)
+	}
+	preview, ext, err := audio.EncodePreview(clip)
+	if err != nil {
+		return fmt.Errorf("encode preview: %w", err)
+	}
+	previewKey := repository.SoundPreviewKey(sound.SoundID, ext)
+	if err := fileService.UploadFile(ctx, preview, previewKey); err != nil {
+		return fmt.Errorf("upload preview: %w", err)
+	}
+	// 試聴用のファイルのサイズが変わるので、上限の計算に使う合計サイズも数え直す
+	storedBytes, err := fileService.StoredBytes(ctx, sound.SoundID, sound.OriginalKey, previewKey)
+	if err != nil {
+		return fmt.Errorf("count stored bytes: %w", err)
+	}
+	waveform := repository.EncodeWaveform(audio.Waveform(clip, repository.SoundWaveformPoints))
+	if err := repo.UpdateSoundPreview(sound.SoundID, waveform, previewKey, storedBytes); err != nil {
+		return err
+	}
+	// ビルドが変わって拡張子が変わった場合は古い試聴用のファイルを消す
+	if sound.PreviewKey != "" && sound.PreviewKey != previewKey {
+		if err := fileService.DeleteFile(ctx, sound.PreviewKey); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to delete old preview %s: %v\n", sound.PreviewKey, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// backfill-sound-stored-bytes は保存しているファイルの合計サイズを記録する前に登録されたサウンドについて、
+// 加工後・元・試聴用のファイルのサイズを保存先から取得して記録する。記録するまでは上限の計算で見積もりを使う。
+//
+//	go run ./cmd/backfill-sound-stored-bytes -dry-run
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+)
+
+func main() {
+	batch := flag.Int("batch", 100, "1回に DB から取得するサウンドの数")
+	dryRun := flag.Bool("dry-run", false, "対象のサウンドを表示するだけで、DB を変更しない")
+	verbose := flag.Bool("v", false, "処理したサウンドを1件ずつ標準エラー出力に表示する")
+	flag.Parse()
+	if *batch <= 0 {
+		log.Fatal("-batch must be positive")
+	}
+
+	db, err := sqlx.Connect("mysql", config.MySQL().FormatDSN())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	repo := repository.New(db, config.LoadLivekitConfig())
+	fileService, err := repository.NewFileService(config.NewStorageConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	updated, failed := 0, 0
+	for afterID := ""; ; {
+		sounds, err := repo.GetSoundsWithoutStoredBytes(afterID, *batch)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(sounds) == 0 {
+			break
+		}
+		afterID = sounds[len(sounds)-1].SoundID
+
+		for _, sound := range sounds {
+			if *dryRun {
+				fmt.Fprintf(os.Stderr, "would backfill %s (%s)\n", sound.SoundID, sound.SoundName)
+				continue
+			}
+			storedBytes, err := backfill(repo, fileService, sound)
+			if err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "failed %s (%s): %v\n", sound.SoundID, sound.SoundName, err)
+				continue
+			}
+			updated++
+			if *verbose {
+				fmt.Fprintf(os.Stderr, "backfilled %s (%s): %d bytes\n", sound.SoundID, sound.SoundName, storedBytes)
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "backfilled %d sounds (%d failed)\n", updated, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// backfill は加工後 (キーは SoundID)・元・試聴用のファイルのサイズを合計して記録する
+func backfill(repo *repository.Repository, fileService *repository.FileService, sound repository.Sound) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	storedBytes, err := fileService.StoredBytes(ctx, sound.SoundID, sound.OriginalKey, sound.PreviewKey)
+	if err != nil {
+		return 0, err
+	}
+	if storedBytes == 0 {
+		// 0 は未記録を表すので、ファイルが1つも無いサウンドは記録せずに報告する
+		return 0, fmt.Errorf("no files found")
+	}
+	if err := repo.UpdateSoundStoredBytes(sound.SoundID, storedBytes); err != nil {
+		return 0, err
+	}
+	return storedBytes, nil
+}
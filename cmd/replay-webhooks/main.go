@@ -0,0 +1,76 @@
+// replay-webhooks は webhook_events に記録された Webhook イベントを空のルーム状態に順に適用し、
+// 結果のルーム状態を JSON で出力する。ルーム状態のズレの調査や、状態の復元に使う。
+//
+//	go run ./cmd/replay-webhooks -from 2025-01-01T00:00:00+09:00 -to 2025-01-02T00:00:00+09:00
+//
+// traQ への通知や通話履歴の記録は行わない。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+)
+
+func main() {
+	now := time.Now()
+	from := flag.String("from", now.Add(-24*time.Hour).Format(time.RFC3339), "再生を開始する時刻 (RFC3339)")
+	to := flag.String("to", now.Format(time.RFC3339), "再生を終了する時刻 (RFC3339, この時刻は含まない)")
+	room := flag.String("room", "", "指定した場合はこのルームのイベントだけを再生する")
+	verbose := flag.Bool("v", false, "適用したイベントを1件ずつ標準エラー出力に表示する")
+	flag.Parse()
+
+	fromTime, err := time.Parse(time.RFC3339, *from)
+	if err != nil {
+		log.Fatalf("invalid -from: %v", err)
+	}
+	toTime, err := time.Parse(time.RFC3339, *to)
+	if err != nil {
+		log.Fatalf("invalid -to: %v", err)
+	}
+
+	db, err := sqlx.Connect("mysql", config.MySQL().FormatDSN())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	// 空のルーム状態から始める (LiveKit には問い合わせない)
+	repo := repository.New(db, config.LoadLivekitConfig())
+	events, err := repo.GetWebhookEvents(fromTime, toTime, *room)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	applied := 0
+	for _, event := range events {
+		result := repo.ApplyWebhookEvent(event)
+		if result.Applied {
+			applied++
+		}
+		if *verbose {
+			status := "applied"
+			if result.Unhandled {
+				status = "unhandled"
+			} else if !result.Applied {
+				status = "skipped"
+			}
+			fmt.Fprintf(os.Stderr, "%s %s %s room=%s id=%s\n",
+				time.Unix(event.CreatedAt, 0).Format(time.RFC3339), status, event.Event, repository.WebhookRoomName(event), event.Id)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "replayed %d events (%d applied, %d skipped)\n", len(events), applied, len(events)-applied)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(repo.RoomState.Snapshot()); err != nil {
+		log.Fatal(err)
+	}
+}
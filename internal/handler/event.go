@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// wsProtocolVersion はWebSocket / SSEで送るイベントのプロトコルバージョン (asyncapi.yaml 参照)
+const wsProtocolVersion = 1
+
+// publishRoomStarted はルーム状態にあるルームの情報を room_started として送る
+func (h *Handler) publishRoomStarted(roomID uuid.UUID) {
+	room, ok := h.repo.RoomState.Get(roomID)
+	if !ok {
+		return
+	}
+	h.events.publish(models.RoomStarted, roomID, room)
+}
+
+func (h *Handler) publishRoomFinished(roomID uuid.UUID) {
+	h.events.publish(models.RoomFinished, roomID, models.WsRoomFinishedPayload{
+		RoomId: roomID,
+	})
+}
+
+// publishParticipantJoined はルーム状態にある参加者の情報を participant_joined として送る
+func (h *Handler) publishParticipantJoined(roomID uuid.UUID, identity string) {
+	room, ok := h.repo.RoomState.Get(roomID)
+	if !ok {
+		return
+	}
+	for _, p := range room.Participants {
+		if p.Identity != nil && *p.Identity == identity {
+			h.events.publish(models.ParticipantJoined, roomID, models.WsParticipantJoinedPayload{
+				RoomId:      roomID,
+				Participant: p,
+			})
+			return
+		}
+	}
+}
+
+func (h *Handler) publishParticipantLeft(roomID uuid.UUID, identity string) {
+	h.events.publish(models.ParticipantLeft, roomID, models.WsParticipantLeftPayload{
+		RoomId:   roomID,
+		Identity: identity,
+	})
+}
+
+// publishMetadataChanged はルーム状態にあるメタデータを metadata_changed として送る
+func (h *Handler) publishMetadataChanged(roomID uuid.UUID) {
+	room, ok := h.repo.RoomState.Get(roomID)
+	if !ok {
+		return
+	}
+	metadata := ""
+	if room.Metadata != nil {
+		metadata = *room.Metadata
+	}
+	h.events.publish(models.MetadataChanged, roomID, models.WsMetadataChangedPayload{
+		RoomId:    roomID,
+		Metadata:  metadata,
+		IsWebinar: room.IsWebinar,
+	})
+}
+
+func (h *Handler) publishPermissionChanged(roomID uuid.UUID, identity string, canPublish bool) {
+	h.events.publish(models.PermissionChanged, roomID, models.WsPermissionChangedPayload{
+		RoomId:     roomID,
+		Identity:   identity,
+		CanPublish: canPublish,
+	})
+}
+
+func (h *Handler) publishTrackPublished(roomID uuid.UUID, identity string, track models.Track) {
+	h.events.publish(models.TrackPublished, roomID, models.WsTrackPayload{
+		RoomId:   roomID,
+		Identity: identity,
+		Track:    track,
+	})
+}
+
+func (h *Handler) publishTrackUnpublished(roomID uuid.UUID, identity string, track models.Track) {
+	h.events.publish(models.TrackUnpublished, roomID, models.WsTrackPayload{
+		RoomId:   roomID,
+		Identity: identity,
+		Track:    track,
+	})
+}
+
+// publishEgress は egress の状態を egress_updated / egress_ended として送る
+func (h *Handler) publishEgress(eventType models.WsEventType, roomID uuid.UUID, egress models.Egress) {
+	h.events.publish(eventType, roomID, models.WsEgressPayload{
+		RoomId: roomID,
+		Egress: egress,
+	})
+}
+
+// publishIngress は ingress の状態を ingress_updated / ingress_ended として送る
+func (h *Handler) publishIngress(eventType models.WsEventType, roomID uuid.UUID, ingress models.Ingress) {
+	h.events.publish(eventType, roomID, models.WsIngressPayload{
+		RoomId:  roomID,
+		Ingress: ingress,
+	})
+}
+
+// broadcastSnapshot は全クライアントへ購読中のルームの snapshot を送り直す
+func (h *Handler) broadcastSnapshot() {
+	h.events.broadcastSnapshot()
+}
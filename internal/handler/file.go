@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// GetFile GET /files/{key}
+// ローカルディスク / メモリに保存したファイルを署名付き URL で配信する。
+func (h *Handler) GetFile(c echo.Context, key string, params models.GetFileParams) error {
+	f, info, err := h.FileService.OpenSignedFile(c.Request().Context(), key, params.Expires, params.Signature)
+	if errors.Is(err, repository.ErrBlobNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "file not found",
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to open file",
+		})
+	}
+	defer f.Close()
+
+	c.Response().Header().Set(echo.HeaderContentType, info.ContentType)
+	http.ServeContent(c.Response(), c.Request(), key, info.ModTime, f)
+	return nil
+}
+
+// PutFile PUT /files/{key}
+// ローカルディスク / メモリへ署名付き URL でアップロードされたファイルを保存する。
+func (h *Handler) PutFile(c echo.Context, key string, params models.PutFileParams) error {
+	// 署名と期限を確認してからボディを読む
+	if err := h.FileService.VerifyUploadURL(key, params.Expires, params.Signature); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "invalid or expired URL",
+		})
+	}
+
+	maxBytes := config.SoundUploadMaxBytes()
+	if c.Request().ContentLength > maxBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("file is too large. Must be <= %d bytes", maxBytes),
+		})
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxBytes+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to read request body",
+		})
+	}
+	if int64(len(data)) > maxBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("file is too large. Must be <= %d bytes", maxBytes),
+		})
+	}
+
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	err = h.FileService.PutSignedFile(c.Request().Context(), key, params.Expires, params.Signature, bytes.NewReader(data), int64(len(data)), contentType)
+	if errors.Is(err, repository.ErrBlobNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "invalid or expired URL",
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to save file",
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
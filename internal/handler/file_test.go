@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// unreadableBody は読まれたらテストを失敗させるリクエストボディ
+type unreadableBody struct {
+	t *testing.T
+}
+
+func (b unreadableBody) Read([]byte) (int, error) {
+	b.t.Error("request body was read before the signature was verified")
+	return 0, http.ErrBodyReadAfterClose
+}
+
+func (unreadableBody) Close() error { return nil }
+
+// TestPutFileVerifiesSignatureFirst は署名が正しくないアップロードをボディを読まずに断ることを確認する
+func TestPutFileVerifiesSignatureFirst(t *testing.T) {
+	fs, err := repository.NewFileService(&config.StorageConfig{Backend: "memory", PublicBaseURL: "http://localhost:8080/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{FileService: fs}
+
+	uploadURL, err := fs.GenerateUploadURL(context.Background(), "upload.mp3", "audio/mpeg", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(uploadURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := u.Query().Get("signature")
+
+	tests := []struct {
+		name   string
+		params models.PutFileParams
+	}{
+		{"tampered signature", models.PutFileParams{Expires: expires, Signature: strings.Repeat("0", len(signature))}},
+		{"expired", models.PutFileParams{Expires: time.Now().Add(-time.Minute).Unix(), Signature: signature}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/files/upload.mp3", nil)
+			req.Body = unreadableBody{t: t}
+			req.ContentLength = 1 << 20
+			rec := httptest.NewRecorder()
+			if err := h.PutFile(echo.New().NewContext(req, rec), "upload.mp3", tt.params); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/files/upload.mp3", strings.NewReader("mp3"))
+	req.Header.Set(echo.HeaderContentType, "audio/mpeg")
+	rec := httptest.NewRecorder()
+	if err := h.PutFile(echo.New().NewContext(req, rec), "upload.mp3", models.PutFileParams{Expires: expires, Signature: signature}); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d (%s)", rec.Code, http.StatusNoContent, rec.Body)
+	}
+}
@@ -1,22 +1,69 @@
 package handler
 
 import (
-	"github.com/gorilla/websocket"
+	"context"
+	"fmt"
+
+	lksdk "github.com/livekit/server-sdk-go/v2"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/pkg/ratelimit"
 	"github.com/pikachu0310/livekit-server/internal/repository"
-	"sync"
+	"github.com/pikachu0310/livekit-server/openapi/models"
 )
 
 type Handler struct {
-	repo        *repository.Repository
-	Clients     map[*websocket.Conn]bool
-	Mutex       sync.Mutex
-	FileService *repository.FileService
+	repo   *repository.Repository
+	events *eventHub
+	player *soundboardPlayer
+	// userPlayLimit / roomPlayLimit はユーザごと・ルームごとのサウンドの再生回数の制限
+	userPlayLimit *ratelimit.Limiter
+	roomPlayLimit *ratelimit.Limiter
+	FileService   *repository.FileService
 }
 
 func New(repo *repository.Repository, f *repository.FileService) *Handler {
+	policy := models.SoundboardQueuePolicy(config.SoundboardQueuePolicy())
+	if !isValidQueuePolicy(policy) {
+		fmt.Printf("Invalid SOUNDBOARD_QUEUE_POLICY, using default overlap: %s\n", policy)
+		policy = models.Overlap
+	}
+
 	return &Handler{
 		repo:        repo,
-		Clients:     make(map[*websocket.Conn]bool),
+		events:      newEventHub(repo.RoomState.Snapshot),
+		player:      newSoundboardPlayer(newPlaybackBackend(repo, f), policy),
 		FileService: f,
+
+		userPlayLimit: newRateLimiter(config.SoundboardUserRateLimit()),
+		roomPlayLimit: newRateLimiter(config.SoundboardRoomRateLimit()),
 	}
 }
+
+func newRateLimiter(limit config.RateLimit) *ratelimit.Limiter {
+	return ratelimit.New(limit.Burst, limit.Cooldown)
+}
+
+// newPlaybackBackend は SOUNDBOARD_PLAYBACK に応じたサウンドの再生方法を返す
+func newPlaybackBackend(repo *repository.Repository, f *repository.FileService) playbackBackend {
+	switch mode := config.SoundboardPlayback(); mode {
+	case "native":
+		if !audio.OpusAvailable() {
+			fmt.Println("SOUNDBOARD_PLAYBACK=native requires building with -tags opus, falling back to ingress")
+			break
+		}
+		load := func(ctx context.Context, soundID string) ([]byte, error) {
+			return f.ReadFile(ctx, soundID, nativeMaxSoundBytes)
+		}
+		return newNativePlaybackBackend(lksdkBotConnector(repo.LiveKitHost, repo.ApiKey, repo.ApiSecret), load)
+	case "ingress":
+	default:
+		fmt.Printf("Invalid SOUNDBOARD_PLAYBACK, using default ingress: %s\n", mode)
+	}
+	return &ingressPlaybackBackend{client: lksdk.NewIngressClient(repo.LiveKitHost, repo.ApiKey, repo.ApiSecret)}
+}
+
+// CleanupSoundboardPlayback は以前の起動で残ったままのサウンドボードの Ingress 等を削除する
+func (h *Handler) CleanupSoundboardPlayback(ctx context.Context) error {
+	return h.player.cleanup(ctx)
+}
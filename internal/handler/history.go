@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/util"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// GetRoomHistory GET /rooms/:roomId/history
+// ルームの通話セッションと参加記録を新しい順に返す。
+func (h *Handler) GetRoomHistory(c echo.Context, roomID uuid.UUID, params models.GetRoomHistoryParams) error {
+	limit, offset, err := historyPage(params.Limit, params.Offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// 続きがあるか判定するために1件多く取得する
+	sessions, err := h.repo.GetRoomSessions(roomID.String(), limit+1, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get room sessions: %v", err),
+		})
+	}
+	var nextOffset *int
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+		next := offset + limit
+		nextOffset = &next
+	}
+
+	sessionIDs := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		sessionIDs = append(sessionIDs, s.SessionID)
+	}
+	stints, err := h.repo.GetParticipantStintsBySessionIDs(sessionIDs)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get participant stints: %v", err),
+		})
+	}
+	stintsBySession := make(map[string][]models.ParticipantStint, len(sessions))
+	for _, st := range stints {
+		stint, ok := toParticipantStintModel(st)
+		if !ok {
+			continue
+		}
+		stintsBySession[st.SessionID] = append(stintsBySession[st.SessionID], stint)
+	}
+
+	res := models.RoomHistoryResponse{
+		Sessions:   make([]models.CallSession, 0, len(sessions)),
+		NextOffset: nextOffset,
+	}
+	for _, s := range sessions {
+		participants := stintsBySession[s.SessionID]
+		if participants == nil {
+			participants = make([]models.ParticipantStint, 0)
+		}
+		sessionID, roomID, ok := parseHistoryIDs(s.SessionID, s.RoomID)
+		if !ok {
+			continue
+		}
+		session := models.CallSession{
+			SessionId:        sessionID,
+			RoomId:           roomID,
+			StartedAt:        s.StartedAt,
+			IsWebinar:        s.IsWebinar,
+			PeakParticipants: s.PeakParticipants,
+			Participants:     participants,
+		}
+		if s.EndedAt.Valid {
+			session.EndedAt = &s.EndedAt.Time
+		}
+		res.Sessions = append(res.Sessions, session)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetUserHistory GET /users/:userId/history
+// ユーザの通話参加記録を新しい順に返す。自分の記録しか取得できない。
+func (h *Handler) GetUserHistory(c echo.Context, userID string, params models.GetUserHistoryParams) error {
+	requestUserID, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if requestUserID != userID {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "you can only get your own history",
+		})
+	}
+
+	limit, offset, err := historyPage(params.Limit, params.Offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	stints, err := h.repo.GetParticipantStintsByUserID(userID, limit+1, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get participant stints: %v", err),
+		})
+	}
+
+	res := models.UserHistoryResponse{
+		Stints: make([]models.ParticipantStint, 0, len(stints)),
+	}
+	if len(stints) > limit {
+		stints = stints[:limit]
+		next := offset + limit
+		res.NextOffset = &next
+	}
+	for _, st := range stints {
+		if stint, ok := toParticipantStintModel(st); ok {
+			res.Stints = append(res.Stints, stint)
+		}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// historyPage は limit / offset クエリを検証し、省略時はデフォルト値を返す
+func historyPage(limitParam, offsetParam *int) (int, int, error) {
+	limit := defaultHistoryLimit
+	if limitParam != nil {
+		limit = *limitParam
+	}
+	if limit < 1 || limit > maxHistoryLimit {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxHistoryLimit)
+	}
+
+	offset := 0
+	if offsetParam != nil {
+		offset = *offsetParam
+	}
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must not be negative")
+	}
+	return limit, offset, nil
+}
+
+// parseHistoryIDs は記録されたセッションIDとルームIDを UUID に変換する。
+// UUID でない記録はレスポンスに含められないので、ログに残して false を返す
+func parseHistoryIDs(sessionID, roomID string) (uuid.UUID, uuid.UUID, bool) {
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		fmt.Printf("Skipped call history with invalid session id: session=%s, room=%s\n", sessionID, roomID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	rid, err := uuid.Parse(roomID)
+	if err != nil {
+		fmt.Printf("Skipped call history with invalid room id: session=%s, room=%s\n", sessionID, roomID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return sid, rid, true
+}
+
+func toParticipantStintModel(st repository.ParticipantStint) (models.ParticipantStint, bool) {
+	sessionID, roomID, ok := parseHistoryIDs(st.SessionID, st.RoomID)
+	if !ok {
+		return models.ParticipantStint{}, false
+	}
+	stint := models.ParticipantStint{
+		SessionId: sessionID,
+		RoomId:    roomID,
+		UserId:    st.UserID,
+		Identity:  st.Identity,
+		JoinedAt:  st.JoinedAt,
+	}
+	if st.LeftAt.Valid {
+		stint.LeftAt = &st.LeftAt.Time
+	}
+	return stint, true
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// TestGetUserHistoryOnlyOwn は他のユーザの通話履歴を DB に触れる前に断ることを確認する
+func TestGetUserHistoryOnlyOwn(t *testing.T) {
+	// repo を持たない Handler なので、権限の確認より先に DB に触れると panic する
+	h := &Handler{}
+	tests := []struct {
+		name   string
+		userID any
+		want   int
+	}{
+		{"unauthenticated", nil, http.StatusUnauthorized},
+		{"another user", "bob", http.StatusForbidden},
+	}
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+			if tt.userID != nil {
+				c.Set("traqUserID", tt.userID)
+			}
+			if err := h.GetUserHistory(c, "alice", models.GetUserHistoryParams{}); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// eventReplaySize は Last-Event-ID による再開のために保持しておくイベント数
+const eventReplaySize = 256
+
+var errSlowConsumer = errors.New("send queue is full")
+
+// hubEvent はハブが配るイベント。ID は全購読者で共通の通し番号。
+type hubEvent struct {
+	ID      int64
+	Type    models.WsEventType
+	RoomID  *uuid.UUID
+	Payload json.RawMessage
+}
+
+// eventSubscriber は WebSocket / SSE の接続1つ分
+type eventSubscriber interface {
+	// subscribes はルームのイベントを受け取るかを返す
+	subscribes(roomID uuid.UUID) bool
+	// deliver はイベントを送信キューに積む。ブロックせず、溢れた場合は errSlowConsumer を返す。
+	deliver(event hubEvent) error
+	// close は接続を閉じる (何度呼んでもよい)
+	close()
+	// onDrop は送信キューが溢れて切断した時に呼ばれる (メトリクス用)
+	onDrop()
+}
+
+// eventHub はルームのイベントに通し番号を振り、購読者へ配る。
+// WebSocket と SSE はどちらもこのハブを通してイベントを受け取る。
+type eventHub struct {
+	mu          sync.Mutex
+	lastID      int64
+	replay      []hubEvent
+	subscribers map[eventSubscriber]struct{}
+	// rooms は snapshot に使う現在のルーム状態を返す
+	rooms func() []models.RoomWithParticipants
+}
+
+func newEventHub(rooms func() []models.RoomWithParticipants) *eventHub {
+	return &eventHub{
+		replay:      make([]hubEvent, 0, eventReplaySize),
+		subscribers: make(map[eventSubscriber]struct{}),
+		rooms:       rooms,
+	}
+}
+
+func (hub *eventHub) register(sub eventSubscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.subscribers[sub] = struct{}{}
+}
+
+func (hub *eventHub) unregister(sub eventSubscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	delete(hub.subscribers, sub)
+}
+
+// publish はルームのイベントに通し番号を振り、そのルームを購読している全購読者へ配る
+func (hub *eventHub) publish(eventType models.WsEventType, roomID uuid.UUID, payload interface{}) {
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Printf("Failed to marshal %s event: %v\n", eventType, err)
+		return
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	event := hub.appendEvent(eventType, &roomID, rawPayload)
+	for sub := range hub.subscribers {
+		if !sub.subscribes(roomID) {
+			continue
+		}
+		if err := sub.deliver(event); err != nil {
+			hub.drop(sub, err)
+		}
+	}
+}
+
+// broadcastSnapshot は全購読者へ購読中のルームの snapshot を送り直す。
+// 差分では表せない変更として通し番号を1つ進めるので、それより前から再開しようとした購読者にも snapshot が送られる。
+// ルーム状態はロックの中で読むので、先に publish されたイベントの変更は必ず snapshot に含まれる。
+func (hub *eventHub) broadcastSnapshot() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	rooms := hub.rooms()
+	hub.appendEvent(models.Snapshot, nil, nil)
+	for sub := range hub.subscribers {
+		if err := hub.sendSnapshotLocked(sub, rooms); err != nil {
+			hub.drop(sub, err)
+		}
+	}
+}
+
+// sendSnapshot は1購読者へ購読中のルームの snapshot を送る
+func (hub *eventHub) sendSnapshot(sub eventSubscriber) error {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	return hub.sendSnapshotLocked(sub, hub.rooms())
+}
+
+// resume は購読者を登録し、lastEventID より後のイベントを再送する。
+// 登録と再送を同じロックの中で行うので、再送中に publish されたイベントと順序が入れ替わることはない。
+// 再送に必要なイベントが残っていない場合は代わりに snapshot を送る。
+func (hub *eventHub) resume(sub eventSubscriber, lastEventID int64) error {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.subscribers[sub] = struct{}{}
+
+	events, ok := hub.eventsSince(lastEventID)
+	if !ok {
+		return hub.sendSnapshotLocked(sub, hub.rooms())
+	}
+	for _, event := range events {
+		if event.RoomID != nil && !sub.subscribes(*event.RoomID) {
+			continue
+		}
+		if err := sub.deliver(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendEvent は通し番号を振ってイベントを再送用のバッファに積む。hub.mu を取った状態で呼ぶこと。
+func (hub *eventHub) appendEvent(eventType models.WsEventType, roomID *uuid.UUID, payload json.RawMessage) hubEvent {
+	hub.lastID++
+	event := hubEvent{
+		ID:      hub.lastID,
+		Type:    eventType,
+		RoomID:  roomID,
+		Payload: payload,
+	}
+	if len(hub.replay) == eventReplaySize {
+		hub.replay = append(hub.replay[:0], hub.replay[1:]...)
+	}
+	hub.replay = append(hub.replay, event)
+	return event
+}
+
+// eventsSince は lastEventID より後のイベントを返す。
+// バッファから溢れていたり、間に snapshot を挟んでいて差分だけでは再開できない場合は false を返す。
+func (hub *eventHub) eventsSince(lastEventID int64) ([]hubEvent, bool) {
+	if lastEventID > hub.lastID || lastEventID < 0 {
+		return nil, false
+	}
+	if lastEventID == hub.lastID {
+		return nil, true
+	}
+	if len(hub.replay) == 0 || hub.replay[0].ID > lastEventID+1 {
+		return nil, false
+	}
+
+	events := hub.replay[lastEventID+1-hub.replay[0].ID:]
+	for _, event := range events {
+		if event.Type == models.Snapshot {
+			return nil, false
+		}
+	}
+	return events, true
+}
+
+// sendSnapshotLocked は購読中のルームだけを含む snapshot を送る。hub.mu を取った状態で呼ぶこと。
+// snapshot の ID はその時点で最後に振った通し番号になる。
+func (hub *eventHub) sendSnapshotLocked(sub eventSubscriber, rooms []models.RoomWithParticipants) error {
+	subscribed := make([]models.RoomWithParticipants, 0, len(rooms))
+	for _, room := range rooms {
+		if sub.subscribes(room.RoomId) {
+			subscribed = append(subscribed, room)
+		}
+	}
+	payload, err := json.Marshal(models.WsSnapshotPayload{Rooms: subscribed})
+	if err != nil {
+		return err
+	}
+	return sub.deliver(hubEvent{
+		ID:      hub.lastID,
+		Type:    models.Snapshot,
+		Payload: payload,
+	})
+}
+
+// drop は送信に失敗した購読者を切断する。hub.mu を取った状態で呼ぶこと。
+func (hub *eventHub) drop(sub eventSubscriber, err error) {
+	fmt.Printf("Disconnecting event subscriber: %v\n", err)
+	if errors.Is(err, errSlowConsumer) {
+		sub.onDrop()
+	}
+	sub.close()
+	delete(hub.subscribers, sub)
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// wsTestServer は接続ごとに wsClient を作ってハブに登録する WebSocket サーバ。
+// pump が false の接続は writePump を動かさず、書き込みが詰まって送信キューが消費されない接続として扱う。
+func wsTestServer(t *testing.T, hub *eventHub, pump func() bool, clients chan<- *wsClient) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		client := newWsClient(conn)
+		client.global = true
+		hub.register(client)
+		if pump() {
+			go client.writePump()
+		}
+		clients <- client
+		<-client.done
+		hub.unregister(client)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dialWs(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestEventHubSlowSubscriber は読まない購読者がいても他の購読者への publish が詰まらず、
+// 送信キュー (wsSendQueueSize) が溢れた購読者だけが切断されることを確認する
+func TestEventHubSlowSubscriber(t *testing.T) {
+	const fastClients = 3
+	events := wsSendQueueSize * 2
+
+	hub := newEventHub(func() []models.RoomWithParticipants { return nil })
+	clients := make(chan *wsClient, fastClients+1)
+	pumps := make(chan bool, fastClients+1)
+	srv := wsTestServer(t, hub, func() bool { return <-pumps }, clients)
+
+	pumps <- false
+	dialWs(t, srv) // 読まない購読者
+	slow := <-clients
+
+	fast := make([]*websocket.Conn, fastClients)
+	for i := range fast {
+		pumps <- true
+		fast[i] = dialWs(t, srv)
+		<-clients
+	}
+
+	// 読む購読者はイベントごとに受け取ってから次を publish するので、送信キューが溢れることはない
+	roomID := uuid.New()
+	for i := range events {
+		start := time.Now()
+		hub.publish(models.ParticipantJoined, roomID, map[string]int{"n": i})
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("publish %d took %v", i, elapsed)
+		}
+		for j, conn := range fast {
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("client %d: read event %d: %v", j, i, err)
+			}
+			var event models.WsEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				t.Fatalf("client %d: %v", j, err)
+			}
+			if event.Seq != int64(i+1) || event.Type != models.ParticipantJoined {
+				t.Fatalf("client %d: got %s seq %d, want %s seq %d", j, event.Type, event.Seq, models.ParticipantJoined, i+1)
+			}
+		}
+	}
+
+	select {
+	case <-slow.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow subscriber was not disconnected")
+	}
+	hub.mu.Lock()
+	_, registered := hub.subscribers[slow]
+	remaining := len(hub.subscribers)
+	hub.mu.Unlock()
+	if registered {
+		t.Error("slow subscriber is still registered")
+	}
+	if remaining != fastClients {
+		t.Errorf("%d subscribers registered, want %d", remaining, fastClients)
+	}
+	// 溢れるまでに積めたのはキューの長さ分だけ
+	if len(slow.queue) != wsSendQueueSize {
+		t.Errorf("slow subscriber queued %d messages, want %d", len(slow.queue), wsSendQueueSize)
+	}
+}
+
+// recordingSubscriber は届いたイベントを順に記録する購読者
+type recordingSubscriber struct {
+	mu     sync.Mutex
+	events []hubEvent
+}
+
+func (s *recordingSubscriber) subscribes(uuid.UUID) bool { return true }
+
+func (s *recordingSubscriber) deliver(event hubEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSubscriber) close()  {}
+func (s *recordingSubscriber) onDrop() {}
+
+// TestEventHubSnapshotIncludesPublishedEvents は publish と broadcastSnapshot を同時に呼んでも、
+// snapshot がそれより前に配られたイベントの変更を必ず含むことを確認する
+func TestEventHubSnapshotIncludesPublishedEvents(t *testing.T) {
+	roomID := uuid.New()
+	var (
+		stateMu sync.Mutex
+		state   int // ルーム状態の代わりに、反映済みの変更の数を metadata に入れる
+	)
+	hub := newEventHub(func() []models.RoomWithParticipants {
+		stateMu.Lock()
+		metadata := strconv.Itoa(state)
+		stateMu.Unlock()
+		// 読んでから返すまでの間に publish が割り込みやすくする
+		time.Sleep(10 * time.Microsecond)
+		return []models.RoomWithParticipants{{RoomId: roomID, Metadata: &metadata}}
+	})
+	sub := &recordingSubscriber{}
+	hub.register(sub)
+
+	const changes = 500
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// Webhook と同じく、状態を変えてから publish する
+		for i := 1; i <= changes; i++ {
+			stateMu.Lock()
+			state = i
+			stateMu.Unlock()
+			hub.publish(models.MetadataChanged, roomID, i)
+			time.Sleep(10 * time.Microsecond)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range changes {
+			hub.broadcastSnapshot()
+		}
+	}()
+	wg.Wait()
+
+	latest := 0
+	for _, event := range sub.events {
+		if event.Type != models.Snapshot {
+			if err := json.Unmarshal(event.Payload, &latest); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		var payload models.WsSnapshotPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+		got, err := strconv.Atoi(*payload.Rooms[0].Metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got < latest {
+			t.Fatalf("snapshot %d reflects change %d, but change %d was delivered before it", event.ID, got, latest)
+		}
+	}
+}
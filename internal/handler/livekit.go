@@ -52,24 +52,11 @@ func (h *Handler) GetLiveKitToken(c echo.Context, _ models.GetLiveKitTokenParams
 	}
 
 	// 6-2) ルームが存在するか確認
-	isExistingRoom := false
-	for _, roomState := range h.repo.RoomState {
-		if roomState.RoomId.String() == room {
-			isExistingRoom = true
-			break
-		}
-	}
+	roomState, isExistingRoom := h.repo.RoomState.Get(uuid.MustParse(room))
 
-	if isExistingRoom {
-		// ルームが存在して、webinar=true の場合はCanPublish=false
-		for _, roomState := range h.repo.RoomState {
-			if roomState.RoomId.String() == room {
-				if roomState.IsWebinar != nil && *roomState.IsWebinar {
-					isWebinar = true
-				}
-				break
-			}
-		}
+	// ルームが存在して、webinar=true の場合はCanPublish=false
+	if isExistingRoom && roomState.IsWebinar != nil && *roomState.IsWebinar {
+		isWebinar = true
 	}
 
 	// 7) VideoGrant にルーム名、CanPublishData=true を設定
@@ -128,6 +115,7 @@ func (h *Handler) GetLiveKitToken(c echo.Context, _ models.GetLiveKitTokenParams
 			Participants: []models.Participant{},
 		}
 		h.repo.AddRoomState(roomWithParticipants)
+		h.publishRoomStarted(roomWithParticipants.RoomId)
 		h.repo.SendStartRoomMessageToTraQ(room)
 	}
 
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/metrics"
+)
+
+// GetMetrics GET /metrics
+// Prometheus のテキスト形式でメトリクスを返す。
+func (h *Handler) GetMetrics(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	return metrics.WritePrometheus(c.Response())
+}
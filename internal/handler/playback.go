@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/livekit/protocol/livekit"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+const (
+	// soundboardIngressPrefix はサウンドボードが作る Ingress の名前の接頭辞。起動時の掃除にも使う
+	soundboardIngressPrefix = "soundboard-"
+	// soundboardQueueSize はルームごとにキューで待てるサウンドの数
+	soundboardQueueSize = 10
+	// soundboardPlaybackGrace は Ingress の起動にかかる時間を見込んで、サウンドの長さに足す時間
+	soundboardPlaybackGrace = 3 * time.Second
+	// soundboardStartTimeout は再生の開始 (Ingress の作成やボットの入室) のタイムアウト
+	soundboardStartTimeout = 10 * time.Second
+)
+
+var (
+	errPlaybackQueueFull = errors.New("soundboard queue is full")
+	errPlaybackNotFound  = errors.New("playback not found")
+)
+
+// playbackBackend はサウンドを実際にルームへ流す方法 (LiveKit Ingress / サーバ内のボット)
+type playbackBackend interface {
+	// start は再生を始める。再生が最後まで終わったら done を呼ぶ (呼ばなくてもサウンドの長さが経過すると終わる)
+	start(ctx context.Context, pb *soundPlayback, done func()) (playbackHandle, error)
+	// cleanup は以前の起動で残ったままのリソースを削除する
+	cleanup(ctx context.Context) error
+}
+
+// playbackHandle は開始した再生1回分
+type playbackHandle interface {
+	// ingress は再生に使っている Ingress を返す。Ingress を使わない場合は nil
+	ingress() *livekit.IngressInfo
+	// stop は再生を止めてリソースを解放する (何度呼んでもよい)
+	stop()
+}
+
+// soundPlayback はサウンドの再生1回分
+type soundPlayback struct {
+	ID        string
+	RoomID    uuid.UUID
+	SoundID   string
+	SoundName string
+	UserID    string
+	// URL は Ingress に読ませる音声ファイルの URL
+	URL      string
+	Duration time.Duration
+
+	// 以下は soundboardPlayer.mu で守る
+	handle    playbackHandle
+	IngressID string
+	StartedAt time.Time
+	stopped   bool
+	timer     *time.Timer
+}
+
+// roomPlayback はルームごとの再生状況
+type roomPlayback struct {
+	policy  models.SoundboardQueuePolicy
+	playing []*soundPlayback
+	queue   []*soundPlayback
+}
+
+// soundboardPlayer はルームごとにサウンドの再生を管理し、終わった再生の Ingress を削除する。
+// 再生はサウンドの長さが経過するか、ingress_ended の Webhook を受け取ると終わる。
+type soundboardPlayer struct {
+	mu            sync.Mutex
+	backend       playbackBackend
+	defaultPolicy models.SoundboardQueuePolicy
+	rooms         map[uuid.UUID]*roomPlayback
+}
+
+func newSoundboardPlayer(backend playbackBackend, defaultPolicy models.SoundboardQueuePolicy) *soundboardPlayer {
+	return &soundboardPlayer{
+		backend:       backend,
+		defaultPolicy: defaultPolicy,
+		rooms:         make(map[uuid.UUID]*roomPlayback),
+	}
+}
+
+// isValidQueuePolicy はキューポリシーとして正しい値かを返す
+func isValidQueuePolicy(policy models.SoundboardQueuePolicy) bool {
+	switch policy {
+	case models.Overlap, models.Queue, models.Replace:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *soundboardPlayer) roomLocked(roomID uuid.UUID) *roomPlayback {
+	room, ok := p.rooms[roomID]
+	if !ok {
+		room = &roomPlayback{policy: p.defaultPolicy}
+		p.rooms[roomID] = room
+	}
+	return room
+}
+
+// play はルームのキューポリシーに従ってサウンドを再生するか、キューに追加する。
+// 再生を始めた場合は Ingress の作成まで待ち、作成した Ingress を返す。
+func (p *soundboardPlayer) play(ctx context.Context, pb *soundPlayback) (models.SoundboardPlaybackStatus, *livekit.IngressInfo, error) {
+	p.mu.Lock()
+	room := p.roomLocked(pb.RoomID)
+	var replaced []*soundPlayback
+	switch room.policy {
+	case models.Queue:
+		if len(room.playing) > 0 || len(room.queue) > 0 {
+			if len(room.queue) >= soundboardQueueSize {
+				p.mu.Unlock()
+				return "", nil, errPlaybackQueueFull
+			}
+			room.queue = append(room.queue, pb)
+			p.mu.Unlock()
+			return models.Queued, nil, nil
+		}
+	case models.Replace:
+		replaced = room.playing
+		room.playing = nil
+		room.queue = nil
+		for _, old := range replaced {
+			p.markStoppedLocked(old)
+		}
+	}
+	pb.StartedAt = time.Now()
+	room.playing = append(room.playing, pb)
+	p.mu.Unlock()
+
+	for _, old := range replaced {
+		go p.release(old)
+	}
+
+	info, err := p.start(ctx, pb)
+	if err != nil {
+		return "", nil, err
+	}
+	return models.Playing, info, nil
+}
+
+// start は再生を始め、サウンドの長さが経過したら終わるようにタイマーを仕掛ける。
+// pb は既に room.playing に入っていること。
+func (p *soundboardPlayer) start(ctx context.Context, pb *soundPlayback) (*livekit.IngressInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, soundboardStartTimeout)
+	defer cancel()
+
+	handle, err := p.backend.start(ctx, pb, func() { p.finish(pb) })
+	if err != nil {
+		// 失敗した再生は取り除き、キューの次のサウンドに進む
+		p.finish(pb)
+		return nil, err
+	}
+
+	info := handle.ingress()
+	p.mu.Lock()
+	pb.handle = handle
+	if info != nil {
+		pb.IngressID = info.IngressId
+	}
+	stopped := pb.stopped
+	if !stopped {
+		pb.timer = time.AfterFunc(pb.Duration+soundboardPlaybackGrace, func() { p.finish(pb) })
+	}
+	p.mu.Unlock()
+
+	// 開始中に止められた場合はすぐに止める
+	if stopped {
+		handle.stop()
+	}
+	return info, nil
+}
+
+// startInBackground はキューから取り出したサウンドの再生を始める
+func (p *soundboardPlayer) startInBackground(pb *soundPlayback) {
+	if pb == nil {
+		return
+	}
+	go func() {
+		if _, err := p.start(context.Background(), pb); err != nil {
+			fmt.Printf("Failed to play queued sound %s in room %s: %v\n", pb.SoundID, pb.RoomID, err)
+		}
+	}()
+}
+
+// finish は再生を終わらせてリソースを解放し、ルームで再生中のサウンドが無くなればキューの次のサウンドを再生する
+func (p *soundboardPlayer) finish(pb *soundPlayback) {
+	p.mu.Lock()
+	room, ok := p.rooms[pb.RoomID]
+	if !ok || !slices.Contains(room.playing, pb) {
+		p.mu.Unlock()
+		return
+	}
+	room.playing = slices.DeleteFunc(room.playing, func(other *soundPlayback) bool { return other == pb })
+	p.markStoppedLocked(pb)
+	next := p.nextLocked(room)
+	p.mu.Unlock()
+
+	p.release(pb)
+	p.startInBackground(next)
+}
+
+// nextLocked は再生中のサウンドが無ければキューの先頭を再生中にして返す
+func (p *soundboardPlayer) nextLocked(room *roomPlayback) *soundPlayback {
+	if len(room.playing) > 0 || len(room.queue) == 0 {
+		return nil
+	}
+	next := room.queue[0]
+	room.queue = room.queue[1:]
+	next.StartedAt = time.Now()
+	room.playing = append(room.playing, next)
+	return next
+}
+
+func (p *soundboardPlayer) markStoppedLocked(pb *soundPlayback) {
+	pb.stopped = true
+	if pb.timer != nil {
+		pb.timer.Stop()
+	}
+}
+
+// stop はルームの再生を止める。playbackID が空の場合はルームの全ての再生を止め、キューも空にする。
+func (p *soundboardPlayer) stop(roomID uuid.UUID, playbackID string) error {
+	p.mu.Lock()
+	room, ok := p.rooms[roomID]
+	if !ok {
+		p.mu.Unlock()
+		return errPlaybackNotFound
+	}
+
+	if playbackID == "" {
+		stopped := room.playing
+		if len(stopped) == 0 && len(room.queue) == 0 {
+			p.mu.Unlock()
+			return errPlaybackNotFound
+		}
+		room.playing = nil
+		room.queue = nil
+		for _, pb := range stopped {
+			p.markStoppedLocked(pb)
+		}
+		p.mu.Unlock()
+
+		for _, pb := range stopped {
+			p.release(pb)
+		}
+		return nil
+	}
+
+	// キューで待っているだけなら取り除くだけでよい
+	if i := slices.IndexFunc(room.queue, func(pb *soundPlayback) bool { return pb.ID == playbackID }); i >= 0 {
+		room.queue = slices.Delete(room.queue, i, i+1)
+		p.mu.Unlock()
+		return nil
+	}
+	i := slices.IndexFunc(room.playing, func(pb *soundPlayback) bool { return pb.ID == playbackID })
+	if i < 0 {
+		p.mu.Unlock()
+		return errPlaybackNotFound
+	}
+	pb := room.playing[i]
+	p.mu.Unlock()
+
+	p.finish(pb)
+	return nil
+}
+
+// ingressEnded は ingress_ended の Webhook を受け取った時に、その Ingress を使っていた再生を終わらせる
+func (p *soundboardPlayer) ingressEnded(ingressID string) {
+	if ingressID == "" {
+		return
+	}
+	p.mu.Lock()
+	var found *soundPlayback
+	for _, room := range p.rooms {
+		if i := slices.IndexFunc(room.playing, func(pb *soundPlayback) bool { return pb.IngressID == ingressID }); i >= 0 {
+			found = room.playing[i]
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if found != nil {
+		p.finish(found)
+	}
+}
+
+// roomFinished はルームが終了した時に、ルームの再生を全て止めてキューポリシーも忘れる
+func (p *soundboardPlayer) roomFinished(roomID uuid.UUID) {
+	p.mu.Lock()
+	room, ok := p.rooms[roomID]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.rooms, roomID)
+	stopped := room.playing
+	for _, pb := range stopped {
+		p.markStoppedLocked(pb)
+	}
+	p.mu.Unlock()
+
+	for _, pb := range stopped {
+		p.release(pb)
+	}
+}
+
+// setPolicy はルームのキューポリシーを変更する
+func (p *soundboardPlayer) setPolicy(roomID uuid.UUID, policy models.SoundboardQueuePolicy) models.SoundboardPlaybackState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roomLocked(roomID).policy = policy
+	return p.stateLocked(roomID)
+}
+
+// state はルームの再生状況を返す
+func (p *soundboardPlayer) state(roomID uuid.UUID) models.SoundboardPlaybackState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stateLocked(roomID)
+}
+
+func (p *soundboardPlayer) stateLocked(roomID uuid.UUID) models.SoundboardPlaybackState {
+	state := models.SoundboardPlaybackState{
+		RoomName: roomID,
+		Policy:   p.defaultPolicy,
+		Playing:  []models.SoundboardPlayback{},
+		Queue:    []models.SoundboardPlayback{},
+	}
+	room, ok := p.rooms[roomID]
+	if !ok {
+		return state
+	}
+	state.Policy = room.policy
+	for _, pb := range room.playing {
+		state.Playing = append(state.Playing, pb.toModel(models.Playing))
+	}
+	for _, pb := range room.queue {
+		state.Queue = append(state.Queue, pb.toModel(models.Queued))
+	}
+	return state
+}
+
+// toModel は soundboardPlayer.mu を取った状態で呼ぶ
+func (pb *soundPlayback) toModel(status models.SoundboardPlaybackStatus) models.SoundboardPlayback {
+	playback := models.SoundboardPlayback{
+		PlaybackId:  pb.ID,
+		SoundId:     pb.SoundID,
+		SoundName:   pb.SoundName,
+		RequestedBy: pb.UserID,
+		Status:      status,
+		DurationMs:  pb.Duration.Milliseconds(),
+	}
+	if pb.IngressID != "" {
+		ingressID := pb.IngressID
+		playback.IngressId = &ingressID
+	}
+	if !pb.StartedAt.IsZero() {
+		startedAt := pb.StartedAt
+		playback.StartedAt = &startedAt
+	}
+	return playback
+}
+
+// release は再生を止めてリソース (Ingress 等) を解放する。まだ開始中の場合は開始後に start が止める
+func (p *soundboardPlayer) release(pb *soundPlayback) {
+	p.mu.Lock()
+	handle := pb.handle
+	p.mu.Unlock()
+	if handle != nil {
+		handle.stop()
+	}
+}
+
+// cleanup は以前の起動で残ったままのリソースを削除する
+func (p *soundboardPlayer) cleanup(ctx context.Context) error {
+	return p.backend.cleanup(ctx)
+}
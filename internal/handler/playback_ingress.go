@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// ingressAPI は LiveKit の Ingress を操作するクライアント (*lksdk.IngressClient)
+type ingressAPI interface {
+	CreateIngress(ctx context.Context, in *livekit.CreateIngressRequest) (*livekit.IngressInfo, error)
+	DeleteIngress(ctx context.Context, in *livekit.DeleteIngressRequest) (*livekit.IngressInfo, error)
+	ListIngress(ctx context.Context, in *livekit.ListIngressRequest) (*livekit.ListIngressResponse, error)
+}
+
+// ingressPlaybackBackend は URL 入力の Ingress にサウンドの URL を読ませて再生する
+type ingressPlaybackBackend struct {
+	client ingressAPI
+}
+
+func (b *ingressPlaybackBackend) start(ctx context.Context, pb *soundPlayback, _ func()) (playbackHandle, error) {
+	info, err := b.client.CreateIngress(ctx, &livekit.CreateIngressRequest{
+		InputType:           livekit.IngressInput_URL_INPUT,
+		Name:                soundboardIngressPrefix + pb.ID,
+		RoomName:            pb.RoomID.String(),
+		ParticipantIdentity: soundboardIngressPrefix + pb.ID,
+		ParticipantName:     "Soundboard " + pb.SoundName,
+		Url:                 pb.URL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingress: %w", err)
+	}
+	return &ingressHandle{client: b.client, info: info}, nil
+}
+
+// cleanup は以前の起動で作られたまま残っているサウンドボードの Ingress を削除する
+func (b *ingressPlaybackBackend) cleanup(ctx context.Context) error {
+	res, err := b.client.ListIngress(ctx, &livekit.ListIngressRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list ingresses: %w", err)
+	}
+	for _, info := range res.Items {
+		if !strings.HasPrefix(info.Name, soundboardIngressPrefix) {
+			continue
+		}
+		if _, err := b.client.DeleteIngress(ctx, &livekit.DeleteIngressRequest{IngressId: info.IngressId}); err != nil {
+			fmt.Printf("Failed to delete stale soundboard ingress %s: %v\n", info.IngressId, err)
+			continue
+		}
+		fmt.Printf("Deleted stale soundboard ingress: %s (room=%s)\n", info.IngressId, info.RoomName)
+	}
+	return nil
+}
+
+// ingressHandle は再生に使っている Ingress。stop で削除する
+type ingressHandle struct {
+	client ingressAPI
+	info   *livekit.IngressInfo
+	once   sync.Once
+}
+
+func (h *ingressHandle) ingress() *livekit.IngressInfo {
+	return h.info
+}
+
+func (h *ingressHandle) stop() {
+	h.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), soundboardStartTimeout)
+		defer cancel()
+		if _, err := h.client.DeleteIngress(ctx, &livekit.DeleteIngressRequest{IngressId: h.info.IngressId}); err != nil {
+			fmt.Printf("Failed to delete soundboard ingress %s: %v\n", h.info.IngressId, err)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	lksdk "github.com/livekit/server-sdk-go/v2"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+const (
+	// nativeSampleRate / nativeChannels は Opus にエンコードする PCM の形式
+	nativeSampleRate = 48000
+	nativeChannels   = 1
+	// nativeFrameDuration は Opus の1フレームの長さ
+	nativeFrameDuration = 20 * time.Millisecond
+	// nativeMaxSoundBytes は再生のために読み込むファイルのサイズの上限
+	nativeMaxSoundBytes = 32 << 20
+)
+
+// botRoom は再生用のボットが参加したルーム
+type botRoom interface {
+	// publishAudio は Opus の音声トラックを公開し、サンプルの書き込み先を返す
+	publishAudio(name string) (sampleWriter, error)
+	disconnect()
+}
+
+// sampleWriter は音声トラックへ Opus のフレームを書き込む (*lksdk.LocalTrack)
+type sampleWriter interface {
+	WriteSample(sample media.Sample, opts *lksdk.SampleWriteOptions) error
+}
+
+// botConnector はボットとしてルームに参加する
+type botConnector func(roomName, identity, name string) (botRoom, error)
+
+// nativePlaybackBackend はサーバ自身がボットとしてルームに参加し、サウンドを Opus にエンコードして音声トラックとして公開する。
+// Ingress を使わないので再生開始までの遅延が小さい。
+type nativePlaybackBackend struct {
+	connect botConnector
+	// load は保存されているサウンドのファイルを読み込む
+	load       func(ctx context.Context, soundID string) ([]byte, error)
+	newEncoder func(sampleRate, channels int) (audio.OpusEncoder, error)
+	// frameInterval はフレームを書き込む間隔 (通常は nativeFrameDuration)
+	frameInterval time.Duration
+}
+
+func newNativePlaybackBackend(connect botConnector, load func(ctx context.Context, soundID string) ([]byte, error)) *nativePlaybackBackend {
+	return &nativePlaybackBackend{
+		connect:       connect,
+		load:          load,
+		newEncoder:    audio.NewOpusEncoder,
+		frameInterval: nativeFrameDuration,
+	}
+}
+
+func (b *nativePlaybackBackend) start(ctx context.Context, pb *soundPlayback, done func()) (playbackHandle, error) {
+	// ルームに入る前にエンコードまで済ませ、失敗した場合にボットが無音で入室しないようにする
+	data, err := b.load(ctx, pb.SoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sound: %w", err)
+	}
+	clip, err := audio.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sound: %w", err)
+	}
+	enc, err := b.newEncoder(nativeSampleRate, nativeChannels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create opus encoder: %w", err)
+	}
+	frames, err := audio.EncodeOpusFrames(enc, clip.PCM16(nativeSampleRate, nativeChannels), nativeSampleRate, nativeChannels, nativeFrameDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := b.connect(pb.RoomID.String(), soundboardIngressPrefix+pb.ID, "Soundboard "+pb.SoundName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join room: %w", err)
+	}
+	track, err := room.publishAudio(pb.SoundName)
+	if err != nil {
+		room.disconnect()
+		return nil, fmt.Errorf("failed to publish audio track: %w", err)
+	}
+
+	handle := &nativeHandle{stopCh: make(chan struct{})}
+	go handle.run(room, track, frames, b.frameInterval, done)
+	return handle, nil
+}
+
+// cleanup はボットはサーバが終了するとルームから抜けるので何もしない
+func (b *nativePlaybackBackend) cleanup(context.Context) error {
+	return nil
+}
+
+// nativeHandle はボットによる再生1回分
+type nativeHandle struct {
+	stopCh chan struct{}
+	once   sync.Once
+}
+
+func (h *nativeHandle) ingress() *livekit.IngressInfo {
+	return nil
+}
+
+func (h *nativeHandle) stop() {
+	h.once.Do(func() { close(h.stopCh) })
+}
+
+// run はフレームを実時間の速さで書き込み、終わったらルームから抜ける。最後まで再生した場合は done を呼ぶ
+func (h *nativeHandle) run(room botRoom, track sampleWriter, frames [][]byte, interval time.Duration, done func()) {
+	defer room.disconnect()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for _, frame := range frames {
+		select {
+		case <-h.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if err := track.WriteSample(media.Sample{Data: frame, Duration: nativeFrameDuration}, nil); err != nil {
+			fmt.Printf("Failed to write soundboard sample: %v\n", err)
+			break
+		}
+	}
+	done()
+}
+
+// lksdkBotConnector は server-sdk-go でルームに参加する botConnector を返す
+func lksdkBotConnector(host, apiKey, apiSecret string) botConnector {
+	return func(roomName, identity, name string) (botRoom, error) {
+		room, err := lksdk.ConnectToRoom(host, lksdk.ConnectInfo{
+			APIKey:              apiKey,
+			APISecret:           apiSecret,
+			RoomName:            roomName,
+			ParticipantIdentity: identity,
+			ParticipantName:     name,
+			ParticipantKind:     lksdk.ParticipantAgent,
+		}, lksdk.NewRoomCallback(), lksdk.WithAutoSubscribe(false))
+		if err != nil {
+			return nil, err
+		}
+		return &lksdkBotRoom{room: room}, nil
+	}
+}
+
+type lksdkBotRoom struct {
+	room *lksdk.Room
+}
+
+func (r *lksdkBotRoom) publishAudio(name string) (sampleWriter, error) {
+	track, err := lksdk.NewLocalSampleTrack(webrtc.RTPCodecCapability{
+		MimeType:  webrtc.MimeTypeOpus,
+		ClockRate: nativeSampleRate,
+		Channels:  2,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.room.LocalParticipant.PublishTrack(track, &lksdk.TrackPublicationOptions{
+		Name:   name,
+		Source: livekit.TrackSource_MICROPHONE,
+	}); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+func (r *lksdkBotRoom) disconnect() {
+	r.room.Disconnect()
+}
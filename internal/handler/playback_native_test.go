@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	lksdk "github.com/livekit/server-sdk-go/v2"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// stubOpusEncoder は PCM の先頭のサンプルだけを書き込むエンコーダ
+type stubOpusEncoder struct{}
+
+func (stubOpusEncoder) Encode(pcm []int16, out []byte) (int, error) {
+	out[0] = byte(len(pcm) / 10)
+	return 1, nil
+}
+
+// stubRoom はボットが参加したルームの代わりに、書き込まれたサンプルを記録する
+type stubRoom struct {
+	mu           sync.Mutex
+	roomName     string
+	identity     string
+	trackName    string
+	samples      []media.Sample
+	disconnected chan struct{}
+}
+
+func (r *stubRoom) publishAudio(name string) (sampleWriter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trackName = name
+	return r, nil
+}
+
+func (r *stubRoom) WriteSample(sample media.Sample, _ *lksdk.SampleWriteOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, sample)
+	return nil
+}
+
+func (r *stubRoom) disconnect() {
+	close(r.disconnected)
+}
+
+func (r *stubRoom) sampleCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.samples)
+}
+
+// newStubNativeBackend は seconds 秒の無音の WAV を再生するバックエンドと、ボットが参加するルームを返す
+func newStubNativeBackend(t *testing.T, seconds float64, interval time.Duration) (*nativePlaybackBackend, *stubRoom) {
+	t.Helper()
+	frames := int(seconds * 44100)
+	wav := audio.EncodeWAV(&audio.Clip{SampleRate: 44100, Samples: [][]float64{make([]float64, frames), make([]float64, frames)}})
+
+	room := &stubRoom{disconnected: make(chan struct{})}
+	connect := func(roomName, identity, _ string) (botRoom, error) {
+		room.roomName = roomName
+		room.identity = identity
+		return room, nil
+	}
+	load := func(_ context.Context, soundID string) ([]byte, error) {
+		if soundID != "sound" {
+			return nil, errors.New("unknown sound")
+		}
+		return wav, nil
+	}
+	backend := newNativePlaybackBackend(connect, load)
+	backend.newEncoder = func(sampleRate, channels int) (audio.OpusEncoder, error) {
+		if sampleRate != nativeSampleRate || channels != nativeChannels {
+			t.Errorf("unexpected encoder format: %d Hz, %d ch", sampleRate, channels)
+		}
+		return stubOpusEncoder{}, nil
+	}
+	backend.frameInterval = interval
+	return backend, room
+}
+
+func waitClosed(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+func TestNativePlaybackPublishesWholeSound(t *testing.T) {
+	backend, room := newStubNativeBackend(t, 0.1, time.Millisecond)
+	player := newSoundboardPlayer(backend, models.Queue)
+	roomID := uuid.New()
+
+	first := &soundPlayback{ID: "first", RoomID: roomID, SoundID: "sound", SoundName: "test", Duration: time.Minute}
+	status, info, err := player.play(context.Background(), first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != models.Playing || info != nil {
+		t.Fatalf("status = %s, ingress = %v", status, info)
+	}
+	if room.roomName != roomID.String() || room.identity != soundboardIngressPrefix+"first" {
+		t.Fatalf("joined %s as %s", room.roomName, room.identity)
+	}
+
+	waitClosed(t, room.disconnected)
+
+	// 100ms の音声は 20ms のフレーム5つ (48kHz モノラルなら1フレーム 960 サンプル)
+	if n := room.sampleCount(); n != 5 {
+		t.Fatalf("wrote %d samples, want 5", n)
+	}
+	for _, sample := range room.samples {
+		if sample.Duration != nativeFrameDuration || sample.Data[0] != 96 {
+			t.Fatalf("unexpected sample: %+v", sample)
+		}
+	}
+	if room.trackName != "test" {
+		t.Fatalf("track name = %q", room.trackName)
+	}
+
+	// 最後まで再生するとタイマーを待たずに再生が終わる
+	deadline := time.Now().Add(5 * time.Second)
+	for len(player.state(roomID).Playing) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("playback did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNativePlaybackStop(t *testing.T) {
+	backend, room := newStubNativeBackend(t, 1, 10*time.Millisecond)
+	player := newSoundboardPlayer(backend, models.Overlap)
+	roomID := uuid.New()
+
+	pb := &soundPlayback{ID: "stop", RoomID: roomID, SoundID: "sound", SoundName: "test", Duration: time.Second}
+	if _, _, err := player.play(context.Background(), pb); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := player.stop(roomID, "stop"); err != nil {
+		t.Fatal(err)
+	}
+	waitClosed(t, room.disconnected)
+
+	if n := room.sampleCount(); n == 0 || n >= 50 {
+		t.Fatalf("wrote %d samples, want playback to stop part way", n)
+	}
+	if len(player.state(roomID).Playing) != 0 {
+		t.Fatal("playback is still listed after stop")
+	}
+}
+
+func TestNativePlaybackLoadError(t *testing.T) {
+	backend, _ := newStubNativeBackend(t, 0.1, time.Millisecond)
+	player := newSoundboardPlayer(backend, models.Overlap)
+	roomID := uuid.New()
+
+	pb := &soundPlayback{ID: "missing", RoomID: roomID, SoundID: "missing", Duration: time.Second}
+	if _, _, err := player.play(context.Background(), pb); err == nil {
+		t.Fatal("expected an error for a missing sound")
+	}
+	if len(player.state(roomID).Playing) != 0 {
+		t.Fatal("failed playback is still listed")
+	}
+}
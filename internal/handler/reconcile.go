@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RunRoomStateReconciler は interval ごとにルーム状態をLiveKitと突き合わせ、
+// ズレを修正した場合はログに残して全クライアントへ通知する。ctx が終了するまでブロックする。
+func (h *Handler) RunRoomStateReconciler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.reconcileRoomState(ctx, interval)
+		}
+	}
+}
+
+func (h *Handler) reconcileRoomState(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	changes, err := h.repo.ReconcileRoomState(ctx)
+	if err != nil {
+		fmt.Printf("Failed to reconcile room state: %v\n", err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	for _, change := range changes {
+		fmt.Printf("Room state reconciled: %s\n", change)
+	}
+
+	// 差分イベントでは表せないので、全クライアントに snapshot を送り直す
+	h.broadcastSnapshot()
+}
@@ -45,10 +45,8 @@ func (h *Handler) GetRoomMetadata(ctx echo.Context, roomID uuid.UUID) error {
 		})
 	}
 
-	for _, room := range h.repo.RoomState {
-		if room.RoomId.String() == roomID.String() {
-			return ctx.JSON(http.StatusOK, room.Metadata)
-		}
+	if room, ok := h.repo.RoomState.Get(roomID); ok {
+		return ctx.JSON(http.StatusOK, room.Metadata)
 	}
 
 	return ctx.JSON(http.StatusNotFound, map[string]string{
@@ -89,49 +87,48 @@ func (h *Handler) UpdateRoomMetadata(ctx echo.Context, roomID uuid.UUID) error {
 	}
 
 	// ルームのメタデータを変更
-	for _, room := range h.repo.RoomState {
-		if room.RoomId.String() == roomID.String() {
-			// ルームに参加しているか確認
-			for _, participant := range room.Participants {
-				if *participant.Name == userID {
-					// ルームのメタデータを変更
-					room.Metadata = &req.Metadata
-					metadata := &util.Metadata{
-						Status:    req.Metadata,
-						IsWebinar: *room.IsWebinar,
-					}
-					metadataStr, err := json.Marshal(metadata)
-					if err != nil {
-						return ctx.JSON(http.StatusInternalServerError, map[string]string{
-							"error on Marshal": err.Error(),
-						})
-					}
-					_, err = c.UpdateRoomMetadata(ctx.Request().Context(), &livekit.UpdateRoomMetadataRequest{
-						Room:     roomID.String(),
-						Metadata: string(metadataStr),
-					})
-					if err != nil {
-						return ctx.JSON(http.StatusInternalServerError, map[string]string{
-							"error on UpdateRoom": err.Error(),
-						})
-					}
-
-					// 全体に通知
-					h.broadcastRoomState()
-
-					return ctx.JSON(http.StatusOK, map[string]string{})
-				}
+	room, ok := h.repo.RoomState.Get(roomID)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"error": "Room not found",
+		})
+	}
+
+	// ルームに参加しているか確認
+	for _, participant := range room.Participants {
+		if participant.Name != nil && *participant.Name == userID {
+			// ルームのメタデータを変更
+			metadata := &util.Metadata{
+				Status:    req.Metadata,
+				IsWebinar: room.IsWebinar != nil && *room.IsWebinar,
 			}
-			return ctx.JSON(http.StatusForbidden, map[string]string{
-				"error": "You don't have permission to change room metadata",
+			metadataStr, err := json.Marshal(metadata)
+			if err != nil {
+				return ctx.JSON(http.StatusInternalServerError, map[string]string{
+					"error on Marshal": err.Error(),
+				})
+			}
+			_, err = c.UpdateRoomMetadata(ctx.Request().Context(), &livekit.UpdateRoomMetadataRequest{
+				Room:     roomID.String(),
+				Metadata: string(metadataStr),
 			})
+			if err != nil {
+				return ctx.JSON(http.StatusInternalServerError, map[string]string{
+					"error on UpdateRoom": err.Error(),
+				})
+			}
+			h.repo.RoomState.SetMetadata(roomID, req.Metadata)
+
+			// 全体に通知
+			h.publishMetadataChanged(roomID)
+
+			return ctx.JSON(http.StatusOK, map[string]string{})
 		}
 	}
 
-	return ctx.JSON(http.StatusNotFound, map[string]string{
-		"error": "Room not found",
+	return ctx.JSON(http.StatusForbidden, map[string]string{
+		"error": "You don't have permission to change room metadata",
 	})
-
 }
 
 // PatchRoomParticipants PATCH /rooms/:room_id/participants
@@ -164,40 +161,40 @@ func (h *Handler) ChangeParticipantRole(ctx echo.Context, roomID uuid.UUID) erro
 	}
 
 	// ルームが存在するか確認
-	for _, roomState := range h.repo.RoomState {
-		if roomState.RoomId == roomID {
-			// userがcanPublishかどうかを確認
-			canPublish := false
-			for _, participant := range roomState.Participants {
-				if *participant.Identity == userID {
-					canPublish = *participant.CanPublish
-					break
-				}
+	if roomState, ok := h.repo.RoomState.Get(roomID); ok {
+		// userがcanPublishかどうかを確認
+		canPublish := false
+		for _, participant := range roomState.Participants {
+			if participant.Identity != nil && *participant.Identity == userID {
+				canPublish = participant.CanPublish != nil && *participant.CanPublish
+				break
 			}
-			if !canPublish {
-				return ctx.JSON(http.StatusForbidden, map[string]string{
-					"error": "You don't have permission to change participant role",
-				})
+		}
+		if !canPublish {
+			return ctx.JSON(http.StatusForbidden, map[string]string{
+				"error": "You don't have permission to change participant role",
+			})
+		}
+		c := lksdk.NewRoomServiceClient(apiHost, apiKey, apiSecret)
+		for _, participant := range req {
+			_, err := c.UpdateParticipant(ctx.Request().Context(), &livekit.UpdateParticipantRequest{
+				Room:     roomID.String(),
+				Identity: *participant.Identity,
+				Permission: &livekit.ParticipantPermission{
+					CanPublish: *participant.CanPublish,
+				},
+			})
+			if err != nil {
+				failedUsers[*participant.Identity] = err.Error()
+			} else {
+				succeedUsers = append(succeedUsers, *participant.Identity)
+				h.repo.UpdateParticipantCanPublish(roomID.String(), *participant.Identity, *participant.CanPublish)
+
+				// 全体に通知
+				h.publishPermissionChanged(roomID, *participant.Identity, *participant.CanPublish)
 			}
-			c := lksdk.NewRoomServiceClient(apiHost, apiKey, apiSecret)
-			for _, participant := range req {
-				_, err := c.UpdateParticipant(ctx.Request().Context(), &livekit.UpdateParticipantRequest{
-					Room:     roomID.String(),
-					Identity: *participant.Identity,
-					Permission: &livekit.ParticipantPermission{
-						CanPublish: *participant.CanPublish,
-					},
-				})
-				if err != nil {
-					failedUsers[*participant.Identity] = err.Error()
-				} else {
-					succeedUsers = append(succeedUsers, *participant.Identity)
-					h.repo.UpdateParticipantCanPublish(roomID.String(), *participant.Identity, *participant.CanPublish)
-				}
 
-			}
 		}
-		break
 	}
 
 	response := make([]map[string]string, 0)
@@ -219,9 +216,6 @@ func (h *Handler) ChangeParticipantRole(ctx echo.Context, roomID uuid.UUID) erro
 		})
 	}
 
-	// 全体に通知
-	h.broadcastRoomState()
-
 	return ctx.JSON(http.StatusOK, response)
 
 }
@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/pkg/util"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+const (
+	// soundUploadCompleteTimeout は完了処理 (ダウンロード・変換・アップロード) のタイムアウト
+	soundUploadCompleteTimeout = 30 * time.Second
+	// soundUploadProcessingGrace は processing のまま残ったアップロード (処理中にサーバが落ちた等) を
+	// 期限切れからどれだけ経ったら掃除するか
+	soundUploadProcessingGrace = 10 * time.Minute
+)
+
+// PostSoundboardUpload POST /soundboard/uploads
+// ストレージへ直接アップロードするための署名付き PUT URL を発行する。
+func (h *Handler) PostSoundboardUpload(c echo.Context) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	var req models.SoundboardUploadUrlRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+	if req.SoundName == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "soundName is required",
+		})
+	}
+	if !h.repo.CheckStampExistence(req.StampId) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "stampId is invalid",
+		})
+	}
+	// 形式はアップロード後にファイルの中身で判定する (ブラウザは .webm を video/webm などとして送るので Content-Type では判定しない)。
+	// ファイル名の拡張子も使わない
+	if req.Size <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "size must be positive",
+		})
+	}
+	if maxBytes := config.SoundUploadMaxBytes(); req.Size > maxBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("file is too large (%d bytes). Must be <= %d", req.Size, maxBytes),
+		})
+	}
+	var quotaErr *repository.SoundQuotaError
+	if err := h.checkSoundQuota(userId, req.Size); errors.As(err, &quotaErr) {
+		return soundQuotaExceeded(c, quotaErr)
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// uploadId はそのまま soundId になる
+	uploadId := uuid.NewString()
+	expiry := config.SoundUploadExpiry()
+	now := time.Now()
+	upload := repository.SoundUpload{
+		UploadID:    uploadId,
+		CreatorID:   userId,
+		SoundName:   req.SoundName,
+		StampID:     req.StampId,
+		// 形式が分かるまでは拡張子の無い一時的な名前で受け取り、完了時に元のファイルとして保存し直す
+		ObjectKey:   uploadId + ".upload",
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(expiry),
+	}
+
+	uploadURL, err := h.FileService.GenerateUploadURL(c.Request().Context(), upload.ObjectKey, upload.ContentType, expiry)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to generate upload URL: %v", err),
+		})
+	}
+	if err := h.repo.InsertSoundUpload(upload); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to insert sound upload: %v", err),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, models.SoundboardUploadUrlResponse{
+		UploadId:  uploadId,
+		UploadUrl: uploadURL,
+		ExpiresAt: upload.ExpiresAt,
+	})
+}
+
+// PostSoundboardUploadComplete POST /soundboard/uploads/{uploadId}/complete
+// アップロードされたファイルを検証・変換し、サウンドとして登録する。
+func (h *Handler) PostSoundboardUploadComplete(c echo.Context, uploadId string, params models.PostSoundboardUploadCompleteParams) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	rejectDuplicate, err := rejectDuplicates(params.OnDuplicate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	upload, err := h.repo.GetSoundUpload(uploadId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "upload not found",
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound upload: %v", err),
+		})
+	}
+	if upload.CreatorID != userId {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "only the uploader can complete this upload",
+		})
+	}
+	if upload.Status != repository.SoundUploadPending {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": fmt.Sprintf("upload is already %s", upload.Status),
+		})
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return c.JSON(http.StatusGone, map[string]string{
+			"error": "upload has expired",
+		})
+	}
+
+	// 同時に complete が呼ばれても1回だけ処理する
+	claimed, err := h.repo.ClaimSoundUpload(uploadId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to claim sound upload: %v", err),
+		})
+	}
+	if !claimed {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "upload is already being processed",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), soundUploadCompleteTimeout)
+	defer cancel()
+
+	existing, status, err := h.finalizeSoundUpload(ctx, upload)
+	if errors.Is(err, repository.ErrDuplicateSound) {
+		// 同じ内容のサウンドが既にあるので、アップロードは消して既存のサウンドを返す
+		h.discardSoundUpload(ctx, upload)
+		return h.duplicateSoundCompleted(c, existing, rejectDuplicate)
+	}
+	var quotaErr *repository.SoundQuotaError
+	if errors.As(err, &quotaErr) {
+		// 上限を超えるので、アップロードは消して失敗扱いにする
+		h.discardSoundUpload(ctx, upload)
+		return soundQuotaExceeded(c, quotaErr)
+	}
+	if err != nil {
+		if status >= http.StatusInternalServerError {
+			// サーバ側の一時的な失敗はやり直せるように pending に戻す
+			if err := h.repo.SetSoundUploadStatus(uploadId, repository.SoundUploadPending); err != nil {
+				fmt.Printf("Failed to reset sound upload %s: %v\n", uploadId, err)
+			}
+		} else {
+			// 不正なファイルは消して、アップロードを失敗扱いにする
+			h.discardSoundUpload(ctx, upload)
+		}
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	sound, err := h.repo.GetSoundboardByID(upload.UploadID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get soundboard item: %v", err),
+		})
+	}
+	return c.JSON(http.StatusOK, soundboardItem(sound, nil, false))
+}
+
+// finalizeSoundUpload はアップロードされたファイルを検証・変換して保存し、sounds テーブルへ登録する。
+// 同じ内容のサウンドが既に登録されている場合はそのサウンドと ErrDuplicateSound を返す。
+// 失敗した場合は返すべきステータスコードとエラーを返す。
+func (h *Handler) finalizeSoundUpload(ctx context.Context, upload repository.SoundUpload) (repository.Sound, int, error) {
+	data, err := h.FileService.ReadFile(ctx, upload.ObjectKey, config.SoundUploadMaxBytes())
+	if errors.Is(err, repository.ErrBlobNotFound) {
+		return repository.Sound{}, http.StatusBadRequest, errors.New("file has not been uploaded")
+	}
+	if errors.Is(err, repository.ErrBlobTooLarge) {
+		return repository.Sound{}, http.StatusRequestEntityTooLarge, fmt.Errorf("file is too large. Must be <= %d bytes", config.SoundUploadMaxBytes())
+	}
+	if err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	hash := contentHash(data)
+	existing, found, err := h.findDuplicateSound(hash, repository.SoundClip{})
+	if err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, err
+	}
+	if found {
+		return existing, http.StatusConflict, repository.ErrDuplicateSound
+	}
+	if err := h.checkSoundQuota(upload.CreatorID, int64(len(data))); err != nil {
+		return repository.Sound{}, soundQuotaStatus(err), err
+	}
+
+	processed, source, err := processSound(data, repository.SoundClip{})
+	if err != nil {
+		return repository.Sound{}, processSoundStatus(err), err
+	}
+	source.ContentHash = hash
+
+	// 元のファイルは中身から判定した形式の拡張子を付けて保存し直す
+	// (拡張子付きの名前で受け取っていた以前のアップロードで、名前が同じ場合はそのまま使う)
+	originalKey := upload.UploadID + ".original" + audio.DetectFormat(data).Ext()
+	if originalKey != upload.ObjectKey {
+		if err := h.FileService.UploadFile(ctx, data, originalKey); err != nil {
+			return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to upload file: %w", err)
+		}
+	}
+	// 登録できなかった場合に消すファイル (アップロードされたファイルは呼び出し元が扱う)
+	created := []string{upload.UploadID}
+	if originalKey != upload.ObjectKey {
+		created = append(created, originalKey)
+	}
+
+	if err := h.FileService.UploadFile(ctx, processed.WAV, upload.UploadID); err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to upload file: %w", err)
+	}
+	previewKey, previewSize, err := h.uploadSoundPreview(ctx, upload.UploadID, processed.Clip)
+	if err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, err
+	}
+	created = append(created, previewKey)
+	err = h.repo.CompleteSoundUpload(repository.Sound{
+		SoundID:     upload.UploadID,
+		SoundName:   upload.SoundName,
+		StampID:     upload.StampID,
+		CreatorID:   upload.CreatorID,
+		OriginalKey: originalKey,
+		DurationMS:  durationMillis(processed.Duration),
+		Waveform:    repository.EncodeWaveform(processed.Waveform),
+		PreviewKey:  previewKey,
+		StoredBytes: int64(len(data)+len(processed.WAV)) + previewSize,
+		SoundSource: source,
+	}, config.SoundboardQuota())
+	if status := soundQuotaStatus(err); status != http.StatusInternalServerError {
+		// 確認してから登録するまでの間に他のサウンドが登録され、上限を超えた
+		h.deleteSoundFiles(ctx, created...)
+		return repository.Sound{}, status, err
+	}
+	if errors.Is(err, repository.ErrDuplicateSound) {
+		// 同じファイルが同時に登録された
+		h.deleteSoundFiles(ctx, created...)
+		existing, err := h.repo.GetSoundboardBySource(hash, repository.SoundClip{})
+		if err != nil {
+			return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to get duplicate sound: %w", err)
+		}
+		return existing, http.StatusConflict, repository.ErrDuplicateSound
+	}
+	if err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to insert soundboard item: %w", err)
+	}
+	if originalKey != upload.ObjectKey {
+		// 保存し直したので、一時的な名前で受け取ったファイルは要らない
+		h.deleteSoundFiles(ctx, upload.ObjectKey)
+	}
+	return repository.Sound{}, http.StatusOK, nil
+}
+
+// duplicateSoundCompleted はアップロードの完了時に同じ内容のサウンドが既に登録されていた時のレスポンスを返す
+func (h *Handler) duplicateSoundCompleted(c echo.Context, existing repository.Sound, reject bool) error {
+	if reject {
+		return duplicateSoundConflict(c, existing)
+	}
+	userId, _ := util.GetTraqUserID(c)
+	items, err := h.soundboardItems(userId, []repository.Sound{existing})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, items[0])
+}
+
+// discardSoundUpload はアップロードされたファイルを削除し、アップロードを失敗扱いにする
+func (h *Handler) discardSoundUpload(ctx context.Context, upload repository.SoundUpload) {
+	if err := h.FileService.DeleteFile(ctx, upload.ObjectKey); err != nil {
+		fmt.Printf("Failed to delete uploaded file %s: %v\n", upload.ObjectKey, err)
+	}
+	if err := h.repo.SetSoundUploadStatus(upload.UploadID, repository.SoundUploadFailed); err != nil {
+		fmt.Printf("Failed to mark sound upload %s as failed: %v\n", upload.UploadID, err)
+	}
+}
+
+// RunSoundUploadSweeper は interval ごとに期限切れのアップロードを、アップロードされたファイルごと削除する。
+// ctx が終了するまでブロックする。
+func (h *Handler) RunSoundUploadSweeper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.sweepSoundUploads(ctx, interval)
+		}
+	}
+}
+
+func (h *Handler) sweepSoundUploads(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	now := time.Now()
+	uploads, err := h.repo.GetExpiredSoundUploads(now)
+	if err != nil {
+		fmt.Printf("Failed to get expired sound uploads: %v\n", err)
+		return
+	}
+
+	for _, upload := range uploads {
+		switch upload.Status {
+		case repository.SoundUploadCompleted:
+			// ファイルはサウンドの元ファイルとして使っているので消さない
+		case repository.SoundUploadProcessing:
+			if now.Sub(upload.ExpiresAt) < soundUploadProcessingGrace {
+				continue
+			}
+			fallthrough
+		default:
+			if err := h.FileService.DeleteFile(ctx, upload.ObjectKey); err != nil {
+				fmt.Printf("Failed to delete expired upload %s: %v\n", upload.ObjectKey, err)
+				continue
+			}
+		}
+		if err := h.repo.DeleteSoundUpload(upload.UploadID); err != nil {
+			fmt.Printf("Failed to delete sound upload %s: %v\n", upload.UploadID, err)
+			continue
+		}
+		if upload.Status != repository.SoundUploadCompleted {
+			fmt.Printf("Sound upload expired: %s (%s)\n", upload.UploadID, upload.Status)
+		}
+	}
+}
@@ -1,35 +1,44 @@
 package handler
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"github.com/go-audio/wav"
 	"github.com/google/uuid"
-	"github.com/hajimehoshi/go-mp3"
-	"github.com/jfreymuth/oggvorbis"
 	"github.com/labstack/echo/v4"
-	"github.com/livekit/protocol/livekit"
-	lksdk "github.com/livekit/server-sdk-go/v2"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
 	"github.com/pikachu0310/livekit-server/internal/pkg/util"
+	"github.com/pikachu0310/livekit-server/internal/repository"
 	"github.com/pikachu0310/livekit-server/openapi/models"
 	"io"
+	"math"
 	"net/http"
-	"path/filepath"
-	"strings"
+	"strconv"
 	"time"
 )
 
+// maxSoundSeconds はサウンドボードにアップロードできる音声の長さの上限
+const maxSoundSeconds = 20.0
+
 // PostSoundboard handles uploading a short audio file (<=20s) to S3, storing metadata in DB
 // POST /soundboard
-func (h *Handler) PostSoundboard(c echo.Context) error {
+func (h *Handler) PostSoundboard(c echo.Context, params models.PostSoundboardParams) error {
 	userId, err := util.GetTraqUserID(c)
 	if err != nil {
 		return c.JSON(http.StatusUnauthorized, map[string]string{
 			"error: AuthTraQClient": err.Error(),
 		})
 	}
+	rejectDuplicate, err := rejectDuplicates(params.OnDuplicate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
 
 	// multipart フォームから取得
 	file, err := c.FormFile("audio")
@@ -60,11 +69,19 @@ func (h *Handler) PostSoundboard(c echo.Context) error {
 		})
 	}
 
-	// Content-Type が audio/ で始まらない場合は 400
-	contentType := file.Header.Get("Content-Type")
-	if !strings.HasPrefix(contentType, "audio/") {
+	// 切り出す範囲とフェード (任意)
+	clip, err := soundClipForm(c)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("invalid content-type: %s", contentType),
+			"error": err.Error(),
+		})
+	}
+
+	// サイズの上限を超えるファイルは読み込まない
+	maxBytes := config.SoundUploadMaxBytes()
+	if file.Size > maxBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("file is too large (%d bytes). Must be <= %d", file.Size, maxBytes),
 		})
 	}
 
@@ -76,138 +93,294 @@ func (h *Handler) PostSoundboard(c echo.Context) error {
 		})
 	}
 	defer src.Close()
-	fileBytes, err := io.ReadAll(src)
+	fileBytes, err := io.ReadAll(io.LimitReader(src, maxBytes))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to read file bytes: %v", err),
 		})
 	}
 
-	// 拡張子を取得し、小文字化
-	// 例: .mp3, .wav, .ogg など
-	ext := strings.ToLower(filepath.Ext(file.Filename))
+	// 同じファイルが同じ切り出し方で既に登録されていれば、変換せずに既存のサウンドを返す
+	hash := contentHash(fileBytes)
+	existing, found, err := h.findDuplicateSound(hash, clip)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if found {
+		return duplicateSoundUploaded(c, existing, rejectDuplicate)
+	}
 
-	// 音声ファイルであり、20秒以内か判定
-	if err := checkAudioDuration(fileBytes, ext, 20.0); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+	// 登録するとサウンドの数・サイズの上限を超える場合は変換する前に断る。
+	// 加工後・試聴用のファイルのサイズは変換するまで分からないので、ここでは元のファイルの分だけで確認する
+	var quotaErr *repository.SoundQuotaError
+	if err := h.checkSoundQuota(userId, int64(len(fileBytes))); errors.As(err, &quotaErr) {
+		return soundQuotaExceeded(c, quotaErr)
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// 音声ファイルであり、(切り出した範囲が) 20秒以内か判定し、48kHz の WAV に変換する
+	processed, source, err := processSound(fileBytes, clip)
+	if err != nil {
+		return c.JSON(processSoundStatus(err), map[string]string{
 			"error": err.Error(),
 		})
 	}
+	source.ContentHash = hash
 
 	// soundId を生成
 	soundId := uuid.NewString()
+	// 元のファイルの拡張子はクライアントのファイル名ではなく、中身から判定した形式から決める
+	originalKey := soundId + ".original" + audio.DetectFormat(fileBytes).Ext()
 
-	// S3へアップロード
+	// S3へアップロード (加工後のファイルを soundId に、元のファイルと試聴用のファイルをその隣に置く)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	if err := h.FileService.UploadFile(ctx, fileBytes, soundId); err != nil {
+	if err := h.FileService.UploadFile(ctx, fileBytes, originalKey); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to upload file: %v", err),
+		})
+	}
+	if err := h.FileService.UploadFile(ctx, processed.WAV, soundId); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to upload file: %v", err),
 		})
 	}
+	previewKey, previewSize, err := h.uploadSoundPreview(ctx, soundId, processed.Clip)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
 
 	// DB保存
-	if err := h.repo.InsertSoundboardItem(soundId, soundName, stampId, userId); err != nil {
+	sound := repository.Sound{
+		SoundID:     soundId,
+		SoundName:   soundName,
+		StampID:     stampId,
+		CreatorID:   userId,
+		OriginalKey: originalKey,
+		DurationMS:  durationMillis(processed.Duration),
+		Waveform:    repository.EncodeWaveform(processed.Waveform),
+		PreviewKey:  previewKey,
+		StoredBytes: int64(len(fileBytes)+len(processed.WAV)) + previewSize,
+		SoundSource: source,
+		SoundClip:   clip,
+	}
+	err = h.repo.InsertSoundboardItem(sound, config.SoundboardQuota())
+	if errors.As(err, &quotaErr) {
+		// 確認してから登録するまでの間に他のサウンドが登録され、上限を超えた
+		h.deleteSoundFiles(ctx, soundId, originalKey, previewKey)
+		return soundQuotaExceeded(c, quotaErr)
+	}
+	if errors.Is(err, repository.ErrDuplicateSound) {
+		// 同じファイルが同時にアップロードされた
+		h.deleteSoundFiles(ctx, soundId, originalKey, previewKey)
+		existing, err := h.repo.GetSoundboardBySource(hash, clip)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to get duplicate sound: %v", err),
+			})
+		}
+		return duplicateSoundUploaded(c, existing, rejectDuplicate)
+	}
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to insert soundboard item: %v", err),
 		})
 	}
 
-	resp := models.SoundboardUploadResponse{SoundId: soundId}
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusOK, soundboardUploadResponse(sound, false))
 }
 
-// checkAudioDuration は拡張子(ext)に基づいて対応ライブラリを使い、秒数をチェックする
-// mp3 / wav / ogg に対応し、それ以外は "we only support mp3, wav, ogg" エラー
-func checkAudioDuration(fileBytes []byte, ext string, maxSeconds float64) error {
-	switch ext {
-	case ".mp3":
-		dur, err := getMp3Duration(fileBytes)
-		if err != nil {
-			return fmt.Errorf("mp3 decode error: %w", err)
-		}
-		if dur > maxSeconds {
-			return fmt.Errorf("audio is too long (%.1f sec). Must be <= %.0f", dur, maxSeconds)
+// soundClipForm は multipart フォームの startMs, endMs, fadeInMs, fadeOutMs を読む。指定されていない値は 0
+func soundClipForm(c echo.Context) (repository.SoundClip, error) {
+	var clip repository.SoundClip
+	fields := []struct {
+		name  string
+		value *int64
+	}{
+		{"startMs", &clip.ClipStartMS},
+		{"endMs", &clip.ClipEndMS},
+		{"fadeInMs", &clip.FadeInMS},
+		{"fadeOutMs", &clip.FadeOutMS},
+	}
+	for _, field := range fields {
+		value := c.FormValue(field.name)
+		if value == "" {
+			continue
 		}
-		return nil
-
-	case ".wav":
-		dur, err := getWavDuration(fileBytes)
-		if err != nil {
-			return fmt.Errorf("wav decode error: %w", err)
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || v < 0 {
+			return repository.SoundClip{}, fmt.Errorf("%s must be a non-negative integer", field.name)
 		}
-		if dur > maxSeconds {
-			return fmt.Errorf("audio is too long (%.1f sec). Must be <= %.0f", dur, maxSeconds)
+		if field.name == "endMs" && v <= clip.ClipStartMS {
+			return repository.SoundClip{}, errors.New("endMs must be greater than startMs")
 		}
-		return nil
+		*field.value = v
+	}
+	return clip, nil
+}
 
-	case ".ogg":
-		dur, err := getOggDuration(fileBytes)
-		if err != nil {
-			return fmt.Errorf("ogg decode error: %w", err)
-		}
-		if dur > maxSeconds {
-			return fmt.Errorf("audio is too long (%.1f sec). Must be <= %.0f", dur, maxSeconds)
-		}
-		return nil
+// soundboardUploadResponse はサウンドを登録した (または既存のサウンドを返す) 時のレスポンスを作る
+func soundboardUploadResponse(sound repository.Sound, duplicate bool) models.SoundboardUploadResponse {
+	return models.SoundboardUploadResponse{
+		SoundId:    sound.SoundID,
+		Duplicate:  duplicate,
+		DurationMs: sound.DurationMS,
+		Waveform:   waveformPeaks(sound.Waveform),
+	}
+}
 
-	default:
-		return errors.New("we only support .mp3, .wav, .ogg")
+// waveformPeaks は DB に保存した 0〜255 の波形を 0〜1 のピークの配列に戻す。記録されていない場合は空の配列
+func waveformPeaks(waveform []byte) []float32 {
+	peaks := make([]float32, len(waveform))
+	for i, v := range waveform {
+		peaks[i] = float32(v) / 255
 	}
+	return peaks
 }
 
-// getMp3Duration returns duration in seconds for MP3
-func getMp3Duration(data []byte) (float64, error) {
-	r := bytes.NewReader(data)
-	decoder, err := mp3.NewDecoder(r)
+// uploadSoundPreview は加工後の音声から試聴用のファイルを作って加工後のファイルの隣にアップロードし、そのキーとサイズを返す
+func (h *Handler) uploadSoundPreview(ctx context.Context, soundId string, clip *audio.Clip) (string, int64, error) {
+	preview, ext, err := audio.EncodePreview(clip)
 	if err != nil {
-		return 0, err
+		return "", 0, fmt.Errorf("failed to encode preview: %w", err)
 	}
-	// decoder.Length() はサンプル数
-	// decoder.SampleRate() はサンプリングレート(例: 44100)
-	sampleRate := float64(decoder.SampleRate())
-	totalSamples := float64(decoder.Length())
-	if sampleRate <= 0 {
-		return 0, errors.New("invalid mp3 sample rate")
+	key := repository.SoundPreviewKey(soundId, ext)
+	if err := h.FileService.UploadFile(ctx, preview, key); err != nil {
+		return "", 0, fmt.Errorf("failed to upload preview: %w", err)
 	}
-	seconds := totalSamples / sampleRate
-	return seconds, nil
+	return key, int64(len(preview)), nil
 }
 
-// getWavDuration returns duration in seconds for WAV
-func getWavDuration(data []byte) (float64, error) {
-	r := bytes.NewReader(data)
-	wavDecoder := wav.NewDecoder(r)
-	buf, err := wavDecoder.FullPCMBuffer()
+// processSound は音声ファイルの形式を中身から判定してデコードし、clip の範囲を切り出して長さを検証し、
+// 48kHz の WAV に変換してラウドネスを揃える。
+// 元のファイルの情報 (ContentHash 以外) も返す。返すエラーはそのままクライアントに返してよい。
+func processSound(data []byte, clip repository.SoundClip) (*audio.Result, repository.SoundSource, error) {
+	start := float64(clip.ClipStartMS) / 1000
+	end := float64(clip.ClipEndMS) / 1000
+
+	// 長すぎるファイルはデコードする前にヘッダの長さで弾く (切り出す場合は切り出す範囲の長さ)
+	info, err := audio.Probe(data)
+	if err != nil {
+		return nil, repository.SoundSource{}, err
+	}
+	window := info.Duration - start
+	if end > 0 {
+		window = min(window, end-start)
+	}
+	if window > maxSoundSeconds {
+		return nil, repository.SoundSource{}, soundTooLong(window)
+	}
+
+	decoded, err := audio.Decode(data)
+	if errors.Is(err, audio.ErrOpusUnavailable) || errors.Is(err, audio.ErrAACUnavailable) {
+		return nil, repository.SoundSource{}, fmt.Errorf("this server cannot decode %s audio: %w", info.Codec, err)
+	}
 	if err != nil {
-		return 0, err
+		return nil, repository.SoundSource{}, err
+	}
+	if start > 0 && start >= decoded.Duration() {
+		return nil, repository.SoundSource{}, fmt.Errorf("startMs is beyond the end of the audio (%.1f sec)", decoded.Duration())
 	}
-	if buf == nil || buf.Format == nil {
-		return 0, errors.New("invalid wav format or buffer")
+	sliced := decoded.Slice(start, end)
+	if dur := sliced.Duration(); dur > maxSoundSeconds {
+		return nil, repository.SoundSource{}, soundTooLong(dur)
 	}
-	sampleRate := float64(buf.Format.SampleRate)
-	sampleCount := float64(len(buf.Data)) // PCMBufferのサンプル数
-	if sampleRate <= 0 {
-		return 0, errors.New("invalid wav sample rate")
+	source := repository.SoundSource{
+		SizeBytes:        int64(len(data)),
+		Codec:            info.Codec,
+		SampleRate:       info.SampleRate,
+		Channels:         info.Channels,
+		SourceDurationMS: durationMillis(decoded.Duration()),
+	}
+
+	opts := config.SoundProcessingOptions()
+	opts.FadeIn = float64(clip.FadeInMS) / 1000
+	opts.FadeOut = float64(clip.FadeOutMS) / 1000
+	opts.WaveformPoints = repository.SoundWaveformPoints
+	return audio.Process(sliced, opts), source, nil
+}
+
+// processSoundStatus は processSound のエラーに対して返すステータスコードを返す。
+// ファイルは正しいがサーバのビルドにデコーダが無い場合はクライアントの誤りではないので 415 にする
+func processSoundStatus(err error) int {
+	if errors.Is(err, audio.ErrOpusUnavailable) || errors.Is(err, audio.ErrAACUnavailable) {
+		return http.StatusUnsupportedMediaType
 	}
-	seconds := sampleCount / sampleRate
-	return seconds, nil
+	return http.StatusBadRequest
 }
 
-// getOggDuration returns duration in seconds for OGG(Vorbis)
-func getOggDuration(data []byte) (float64, error) {
-	r := bytes.NewReader(data)
-	stream, err := oggvorbis.NewReader(r)
+// soundTooLong は長さの上限を超えた時のエラーを返す
+func soundTooLong(dur float64) error {
+	return fmt.Errorf("audio is too long (%.1f sec). Must be <= %.0f", dur, maxSoundSeconds)
+}
+
+// contentHash はファイルの SHA-256 を16進数で返す
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// findDuplicateSound は内容が hash のファイルを clip の切り出し方で登録したサウンドがあれば返す
+func (h *Handler) findDuplicateSound(hash string, clip repository.SoundClip) (repository.Sound, bool, error) {
+	sound, err := h.repo.GetSoundboardBySource(hash, clip)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.Sound{}, false, nil
+	}
 	if err != nil {
-		return 0, err
+		return repository.Sound{}, false, fmt.Errorf("failed to check duplicate sound: %w", err)
 	}
-	sampleRate := float64(stream.SampleRate())
-	sampleCount := float64(stream.Length()) // Total samples
-	if sampleRate <= 0 {
-		return 0, errors.New("invalid ogg sample rate")
+	return sound, true, nil
+}
+
+// duplicateSoundUploaded は POST /soundboard で同じ内容のサウンドが既に登録されていた時のレスポンスを返す
+func duplicateSoundUploaded(c echo.Context, existing repository.Sound, reject bool) error {
+	if reject {
+		return duplicateSoundConflict(c, existing)
 	}
-	seconds := sampleCount / sampleRate
-	return seconds, nil
+	return c.JSON(http.StatusOK, soundboardUploadResponse(existing, true))
+}
+
+// rejectDuplicates は onDuplicate が conflict (重複を 409 にする) かを返す
+func rejectDuplicates(onDuplicate *models.OnDuplicateParam) (bool, error) {
+	if onDuplicate == nil {
+		return false, nil
+	}
+	switch *onDuplicate {
+	case models.Existing:
+		return false, nil
+	case models.Conflict:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid onDuplicate: %s", *onDuplicate)
+	}
+}
+
+func duplicateSoundConflict(c echo.Context, existing repository.Sound) error {
+	return c.JSON(http.StatusConflict, models.SoundboardDuplicateError{
+		Error:   "sound with the same content already exists",
+		SoundId: &existing.SoundID,
+	})
+}
+
+// deleteSoundFiles は登録に失敗したサウンドのファイルを削除する
+func (h *Handler) deleteSoundFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := h.FileService.DeleteFile(ctx, key); err != nil {
+			fmt.Printf("Failed to delete sound file %s: %v\n", key, err)
+		}
+	}
+}
+
+// durationMillis は秒をミリ秒に変換する
+func durationMillis(seconds float64) int64 {
+	return int64(math.Round(seconds * 1000))
 }
 
 // PostSoundboardPlay triggers playing an uploaded audio file via LiveKit Ingress
@@ -232,75 +405,408 @@ func (h *Handler) PostSoundboardPlay(c echo.Context) error {
 		})
 	}
 
-	// 2) 認証トークンからユーザ情報を取得 (実装は省略/仮)
-	//    例: userID, err := h.extractUserIDFromBearer(c)
-	//    ここで "ユーザが roomName に参加しているか" を確認
-	//    もし参加していないなら 400や403を返す
-	//    例: if !h.repo.IsUserInRoom(userID, req.RoomName) { ... }
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// 3) S3ファイルキー = soundId として署名付きURL生成
-	audioURL, err := h.FileService.GeneratePresignedURL(ctx, req.SoundId)
+	// 2) 認証トークンからユーザ情報を取得し、ユーザが roomName に参加しているか確認
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if status, err := h.checkRoomMember(ctx, RoomId, userId); err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// 3) サウンドの名前・長さを取得
+	sound, err := h.repo.GetSoundboardByID(req.SoundId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "sound not found",
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": fmt.Sprintf("failed to generate presigned URL: %v", err),
+			"error": fmt.Sprintf("failed to get soundboard item: %v", err),
 		})
 	}
 
-	// 4) Ingressクライアント
-	ingressClient := lksdk.NewIngressClient(h.repo.LiveKitHost, h.repo.ApiKey, h.repo.ApiSecret)
+	if sound.HiddenAt.Valid {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "sound is hidden pending moderation review",
+		})
+	}
 
-	// 5) Ingress リクエスト作成
-	ingReq := &livekit.CreateIngressRequest{
-		InputType: livekit.IngressInput_URL_INPUT,
-		// ここでは SFU内の participantIdentity等をどう扱うかは任意
-		// たとえば "soundboard-user" など固定でOK
-		Name:                "soundboard-ingress",
-		RoomName:            req.RoomName.String(), // openapi_types.UUID → string
-		ParticipantIdentity: "soundboard-" + req.SoundId,
-		ParticipantName:     "Soundboard " + req.SoundId,
-		Url:                 audioURL,
+	// ユーザごと・ルームごとの回数制限 (ルームで制限された場合はユーザの分を返す)
+	if ok, retryAfter := h.userPlayLimit.Allow(userId); !ok {
+		return tooManyPlays(c, retryAfter)
+	}
+	if ok, retryAfter := h.roomPlayLimit.Allow(RoomId.String()); !ok {
+		h.userPlayLimit.Refund(userId)
+		return tooManyPlays(c, retryAfter)
 	}
 
-	info, err := ingressClient.CreateIngress(ctx, ingReq)
+	// 4) S3ファイルキー = soundId として署名付きURL生成 (以降で再生できなかった場合は回数を返す)
+	audioURL, err := h.FileService.GeneratePresignedURL(ctx, req.SoundId)
 	if err != nil {
+		h.refundPlay(userId, RoomId)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": fmt.Sprintf("failed to create ingress: %v", err),
+			"error": fmt.Sprintf("failed to generate presigned URL: %v", err),
 		})
 	}
 
-	// 6) SoundboardPlayResponse にマッピングして返す
+	// 5) ルームのキューポリシーに従って再生する (Ingress は再生が終わると削除される)
+	duration := time.Duration(sound.DurationMS) * time.Millisecond
+	if duration <= 0 {
+		// 長さを記録する前にアップロードされたサウンドは上限の長さとみなす
+		duration = time.Duration(maxSoundSeconds * float64(time.Second))
+	}
+	playback := &soundPlayback{
+		ID:        uuid.NewString(),
+		RoomID:    RoomId,
+		SoundID:   sound.SoundID,
+		SoundName: sound.SoundName,
+		UserID:    userId,
+		URL:       audioURL,
+		Duration:  duration,
+	}
+	status, info, err := h.player.play(ctx, playback)
+	if err != nil {
+		h.refundPlay(userId, RoomId)
+	}
+	if errors.Is(err, errPlaybackQueueFull) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// 6) 統計のために再生を記録する (記録に失敗しても再生は続ける)
+	if err := h.repo.InsertSoundPlay(repository.SoundPlay{
+		SoundID:  sound.SoundID,
+		RoomID:   RoomId.String(),
+		UserID:   userId,
+		PlayedAt: time.Now(),
+	}); err != nil {
+		fmt.Printf("Failed to record sound play: %v\n", err)
+	}
+
+	// 7) SoundboardPlayResponse にマッピングして返す
 	resp := models.SoundboardPlayResponse{
-		IngressId: info.IngressId,
-		Url:       &info.Url,
-		StreamKey: &info.StreamKey,
+		PlaybackId: playback.ID,
+		Status:     status,
+	}
+	if info != nil {
+		resp.IngressId = &info.IngressId
+		resp.Url = &info.Url
+		resp.StreamKey = &info.StreamKey
 	}
 	return c.JSON(http.StatusOK, resp)
 }
 
-// GetSoundboardList returns an array of SoundboardItem (soundId, soundName, stampId)
-// GET /soundboard
-func (h *Handler) GetSoundboardList(c echo.Context) error {
-	// 1) DBからサウンドボード一覧を取得
-	items, err := h.repo.GetAllSoundboards()
+// checkRoomMember はユーザがルームに参加していることを確認する。
+// 参加していない場合は返すべきステータスコードとエラーを返す。
+func (h *Handler) checkRoomMember(ctx context.Context, roomId uuid.UUID, userId string) (int, error) {
+	inRoom, err := h.repo.IsUserInRoom(ctx, roomId, userId)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	if !inRoom {
+		return http.StatusForbidden, errors.New("you are not in this room")
+	}
+	return http.StatusOK, nil
+}
+
+// refundPlay は再生できなかった場合に、ユーザとルームの回数制限に使った分を返す
+func (h *Handler) refundPlay(userId string, roomId uuid.UUID) {
+	h.userPlayLimit.Refund(userId)
+	h.roomPlayLimit.Refund(roomId.String())
+}
+
+// tooManyPlays は回数制限に引っかかった時の 429 を返す
+func tooManyPlays(c echo.Context, retryAfter time.Duration) error {
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	return c.JSON(http.StatusTooManyRequests, models.SoundboardRateLimitError{
+		Error:      fmt.Sprintf("too many sounds played, retry after %d seconds", seconds),
+		RetryAfter: seconds,
+	})
+}
+
+// PostSoundboardStop stops playing sounds in a room
+// POST /soundboard/stop
+func (h *Handler) PostSoundboardStop(c echo.Context) error {
+	var req models.SoundboardStopRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+	if req.RoomName == uuid.Nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "roomName is required",
+		})
+	}
+
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if status, err := h.checkRoomMember(c.Request().Context(), req.RoomName, userId); err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	playbackID := ""
+	if req.PlaybackId != nil {
+		playbackID = *req.PlaybackId
+	}
+	if err := h.player.stop(req.RoomName, playbackID); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSoundboardPlayback returns the playing and queued sounds of a room
+// GET /soundboard/playback
+func (h *Handler) GetSoundboardPlayback(c echo.Context, params models.GetSoundboardPlaybackParams) error {
+	if params.RoomName == uuid.Nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "roomName is required",
+		})
+	}
+	return c.JSON(http.StatusOK, h.player.state(params.RoomName))
+}
+
+// PutSoundboardPlaybackPolicy changes how overlapping plays are handled in a room
+// PUT /soundboard/playback/policy
+func (h *Handler) PutSoundboardPlaybackPolicy(c echo.Context) error {
+	var req models.SoundboardPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+	if req.RoomName == uuid.Nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "roomName is required",
+		})
+	}
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if status, err := h.checkRoomMember(c.Request().Context(), req.RoomName, userId); err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if !isValidQueuePolicy(req.Policy) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid policy: %s", req.Policy),
+		})
+	}
+	return c.JSON(http.StatusOK, h.player.setPolicy(req.RoomName, req.Policy))
+}
+
+// PatchSoundboard updates the name and/or stamp of a sound
+// PATCH /soundboard/{soundId}
+func (h *Handler) PatchSoundboard(c echo.Context, soundId string) error {
+	var req models.SoundboardUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	sound, status, err := h.getEditableSound(c, soundId)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if req.SoundName != nil {
+		if *req.SoundName == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "soundName must not be empty",
+			})
+		}
+		sound.SoundName = *req.SoundName
+	}
+	if req.StampId != nil {
+		if !h.repo.CheckStampExistence(*req.StampId) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "stampId is invalid",
+			})
+		}
+		sound.StampID = *req.StampId
+	}
+	var tags []string
+	if req.Tags != nil {
+		tags, err = normalizeSoundTags(*req.Tags)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	// tags が nil の場合はタグを変えない
+	if err := h.repo.UpdateSoundboardItem(sound.SoundID, sound.SoundName, sound.StampID, tags); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to update soundboard item: %v", err),
+		})
+	}
+
+	userId, _ := util.GetTraqUserID(c)
+	items, err := h.soundboardItems(userId, []repository.Sound{sound})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, items[0])
+}
+
+// GetSoundboardPreview redirects to a small file for previewing a sound in the browser
+// GET /soundboard/{soundId}/preview
+func (h *Handler) GetSoundboardPreview(c echo.Context, soundId string) error {
+	sound, err := h.repo.GetSoundboardByID(soundId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "sound not found",
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": fmt.Sprintf("failed to get soundboard list: %v", err),
+			"error": fmt.Sprintf("failed to get soundboard item: %v", err),
+		})
+	}
+	// 通報により非表示になったサウンドは確認する管理者だけが試聴できる
+	if userId, _ := util.GetTraqUserID(c); sound.HiddenAt.Valid && !isSoundboardAdmin(userId) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "sound is hidden pending moderation review",
+		})
+	}
+	if sound.PreviewKey == "" {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "preview has not been generated for this sound",
 		})
 	}
 
-	// 2) models.SoundboardListResponse = []SoundboardItem に変換
-	var resp models.SoundboardListResponse
-	for _, it := range items {
-		resp = append(resp, models.SoundboardItem{
-			SoundId:   it.SoundID,
-			SoundName: it.SoundName,
-			StampId:   it.StampID,
-			CreatorId: it.CreatorID,
+	previewURL, err := h.FileService.GeneratePresignedURL(c.Request().Context(), sound.PreviewKey)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to generate presigned URL: %v", err),
 		})
 	}
+	return c.Redirect(http.StatusFound, previewURL)
+}
 
-	return c.JSON(http.StatusOK, resp)
+// DeleteSoundboard removes a sound from DB and its audio file from S3
+// DELETE /soundboard/{soundId}
+func (h *Handler) DeleteSoundboard(c echo.Context, soundId string) error {
+	sound, status, err := h.getEditableSound(c, soundId)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := h.deleteSound(ctx, sound); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// deleteSound はサウンドのファイルとDBの行を削除する。
+// 先にS3のファイル (加工後・加工前・試聴用) を消す (失敗した場合はDBの行を残し、再度削除できるようにする)
+func (h *Handler) deleteSound(ctx context.Context, sound repository.Sound) error {
+	for _, key := range []string{sound.SoundID, sound.OriginalKey, sound.PreviewKey} {
+		if key == "" {
+			continue
+		}
+		if err := h.FileService.DeleteFile(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+	if err := h.repo.DeleteSoundboardItem(sound.SoundID); err != nil {
+		return fmt.Errorf("failed to delete soundboard item: %w", err)
+	}
+	return nil
+}
+
+// getEditableSound はサウンドを取得し、リクエストしたユーザが作成者か管理者であることを確認する。
+// 失敗した場合は返すべきステータスコードとエラーを返す。
+func (h *Handler) getEditableSound(c echo.Context, soundId string) (repository.Sound, int, error) {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return repository.Sound{}, http.StatusUnauthorized, err
+	}
+
+	sound, err := h.repo.GetSoundboardByID(soundId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.Sound{}, http.StatusNotFound, errors.New("sound not found")
+	}
+	if err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to get soundboard item: %w", err)
+	}
+
+	if sound.CreatorID != userId && !isSoundboardAdmin(userId) {
+		return repository.Sound{}, http.StatusForbidden, errors.New("only the creator or an admin can modify this sound")
+	}
+	return sound, http.StatusOK, nil
+}
+
+func soundboardItem(sound repository.Sound, tags []string, favorite bool) models.SoundboardItem {
+	if tags == nil {
+		tags = []string{}
+	}
+	return models.SoundboardItem{
+		SoundId:    sound.SoundID,
+		SoundName:  sound.SoundName,
+		StampId:    sound.StampID,
+		CreatorId:  sound.CreatorID,
+		DurationMs: sound.DurationMS,
+		Waveform:   waveformPeaks(sound.Waveform),
+		Tags:       tags,
+		Favorite:   favorite,
+		Hidden:     sound.HiddenAt.Valid,
+		CreatedAt:  sound.CreatedAt,
+		Source:     soundboardSource(sound.SoundSource),
+	}
+}
+
+func soundboardSource(source repository.SoundSource) *models.SoundboardSource {
+	if source.ContentHash == "" {
+		return nil
+	}
+	return &models.SoundboardSource{
+		Sha256:     source.ContentHash,
+		SizeBytes:  source.SizeBytes,
+		Codec:      source.Codec,
+		SampleRate: source.SampleRate,
+		Channels:   source.Channels,
+		DurationMs: source.SourceDurationMS,
+	}
 }
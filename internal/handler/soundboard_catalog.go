@@ -0,0 +1,479 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/util"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+const (
+	// defaultSoundListLimit は cursor だけを指定して limit を省略した時に返す件数。続きは X-Next-Cursor で取得する
+	// (どちらも省略した場合は従来通り全件返す)
+	defaultSoundListLimit = 100
+	maxSoundListLimit     = 100
+	// maxSoundTags はサウンドに付けられるタグの数の上限
+	maxSoundTags = 10
+	// maxSoundTagLength はタグの文字数の上限 (sound_tags.tag の長さ)
+	maxSoundTagLength = 64
+	// maxPackSounds はパックに入れられるサウンドの数の上限
+	maxPackSounds = 200
+)
+
+// GetSoundboardList returns sounds matching the query parameters, newest first
+// GET /soundboard
+func (h *Handler) GetSoundboardList(c echo.Context, params models.GetSoundboardListParams) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// 1) クエリパラメータから絞り込み条件を作る
+	filter := repository.SoundFilter{
+		Query:     strings.TrimSpace(deref(params.Q)),
+		Tag:       strings.TrimSpace(deref(params.Tag)),
+		CreatorID: deref(params.CreatorId),
+		PackID:    deref(params.Pack),
+	}
+	if params.Favorite != nil && *params.Favorite {
+		filter.FavoriteOf = userId
+	}
+	if params.ChannelId != nil {
+		filter.ChannelID = params.ChannelId.String()
+	}
+	// limit も cursor も無い場合はページ分けしない (X-Next-Cursor を読まない既存のクライアントのため)
+	paged := params.Limit != nil || (params.Cursor != nil && *params.Cursor != "")
+	limit := defaultSoundListLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > maxSoundListLimit {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("limit must be between 1 and %d", maxSoundListLimit),
+		})
+	}
+	if paged {
+		// 続きがあるかを知るために1件多く取得する
+		filter.Limit = limit + 1
+	}
+	if params.Cursor != nil && *params.Cursor != "" {
+		cursor, err := decodeSoundCursor(*params.Cursor)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid cursor",
+			})
+		}
+		filter.Cursor = cursor
+	}
+
+	// 2) DBからサウンドボード一覧を取得
+	sounds, err := h.repo.ListSounds(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get soundboard list: %v", err),
+		})
+	}
+	if paged && len(sounds) > limit {
+		sounds = sounds[:limit]
+		last := sounds[len(sounds)-1]
+		c.Response().Header().Set("X-Next-Cursor", encodeSoundCursor(repository.SoundCursor{
+			CreatedAt: last.CreatedAt,
+			SoundID:   last.SoundID,
+		}))
+	}
+
+	// 3) models.SoundboardListResponse = []SoundboardItem に変換
+	items, err := h.soundboardItems(userId, sounds)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, models.SoundboardListResponse(items))
+}
+
+// soundboardItems はサウンドにタグと userId のお気に入りかどうかを付けてレスポンスの形にする
+func (h *Handler) soundboardItems(userId string, sounds []repository.Sound) ([]models.SoundboardItem, error) {
+	ids := make([]string, 0, len(sounds))
+	for _, sound := range sounds {
+		ids = append(ids, sound.SoundID)
+	}
+	tags, err := h.repo.GetSoundTags(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sound tags: %w", err)
+	}
+	favorites, err := h.repo.GetFavoriteSoundIDs(userId, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorite sounds: %w", err)
+	}
+
+	items := make([]models.SoundboardItem, 0, len(sounds))
+	for _, sound := range sounds {
+		items = append(items, soundboardItem(sound, tags[sound.SoundID], favorites[sound.SoundID]))
+	}
+	return items, nil
+}
+
+// encodeSoundCursor はカーソルをクエリパラメータに入れられる文字列にする
+func encodeSoundCursor(cursor repository.SoundCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSoundCursor(s string) (*repository.SoundCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor repository.SoundCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.SoundID == "" || cursor.CreatedAt.IsZero() {
+		return nil, errors.New("incomplete cursor")
+	}
+	return &cursor, nil
+}
+
+// normalizeSoundTags は前後の空白を取り除き、重複と空のタグを除いて並べ替える
+func normalizeSoundTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxSoundTagLength {
+			return nil, fmt.Errorf("tag is too long: %s (must be <= %d characters)", tag, maxSoundTagLength)
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxSoundTags {
+		return nil, fmt.Errorf("too many tags (%d). Must be <= %d", len(normalized), maxSoundTags)
+	}
+	slices.Sort(normalized)
+	return normalized, nil
+}
+
+// GetSoundboardTags returns the tags in use, most used first
+// GET /soundboard/tags
+func (h *Handler) GetSoundboardTags(c echo.Context) error {
+	tags, err := h.repo.GetAllSoundTags()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound tags: %v", err),
+		})
+	}
+	resp := make([]models.SoundboardTag, 0, len(tags))
+	for _, tag := range tags {
+		resp = append(resp, models.SoundboardTag{Tag: tag.Tag, Count: tag.Count})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PutSoundboardFavorite adds a sound to the user's favorites
+// PUT /soundboard/{soundId}/favorite
+func (h *Handler) PutSoundboardFavorite(c echo.Context, soundId string) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if _, err := h.repo.GetSoundboardByID(soundId); errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "sound not found",
+		})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get soundboard item: %v", err),
+		})
+	}
+	if err := h.repo.AddSoundFavorite(userId, soundId); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to add favorite: %v", err),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteSoundboardFavorite removes a sound from the user's favorites
+// DELETE /soundboard/{soundId}/favorite
+func (h *Handler) DeleteSoundboardFavorite(c echo.Context, soundId string) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := h.repo.RemoveSoundFavorite(userId, soundId); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to remove favorite: %v", err),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSoundboardPacks returns all sound packs
+// GET /soundboard/packs
+func (h *Handler) GetSoundboardPacks(c echo.Context) error {
+	packs, err := h.repo.GetAllSoundPacks()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound packs: %v", err),
+		})
+	}
+	return c.JSON(http.StatusOK, soundboardPacks(packs))
+}
+
+// PostSoundboardPack creates a sound pack
+// POST /soundboard/packs
+func (h *Handler) PostSoundboardPack(c echo.Context) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	var req models.SoundboardPackRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	pack := repository.SoundPack{
+		PackID:    uuid.NewString(),
+		CreatorID: userId,
+		CreatedAt: time.Now(),
+	}
+	if status, err := h.applySoundPackRequest(&pack, req); err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := h.repo.InsertSoundPack(pack); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to insert sound pack: %v", err),
+		})
+	}
+	return c.JSON(http.StatusCreated, soundboardPack(pack))
+}
+
+// GetSoundboardPack returns a sound pack
+// GET /soundboard/packs/{packId}
+func (h *Handler) GetSoundboardPack(c echo.Context, packId string) error {
+	pack, err := h.repo.GetSoundPack(packId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "sound pack not found",
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound pack: %v", err),
+		})
+	}
+	return c.JSON(http.StatusOK, soundboardPack(pack))
+}
+
+// PutSoundboardPack replaces the name, description and sounds of a sound pack
+// PUT /soundboard/packs/{packId}
+func (h *Handler) PutSoundboardPack(c echo.Context, packId string) error {
+	var req models.SoundboardPackRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	pack, status, err := h.getEditableSoundPack(c, packId)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if status, err := h.applySoundPackRequest(&pack, req); err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := h.repo.UpdateSoundPack(pack); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to update sound pack: %v", err),
+		})
+	}
+	return c.JSON(http.StatusOK, soundboardPack(pack))
+}
+
+// DeleteSoundboardPack removes a sound pack (the sounds in it are kept)
+// DELETE /soundboard/packs/{packId}
+func (h *Handler) DeleteSoundboardPack(c echo.Context, packId string) error {
+	pack, status, err := h.getEditableSoundPack(c, packId)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := h.repo.DeleteSoundPack(pack.PackID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to delete sound pack: %v", err),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getEditableSoundPack はパックを取得し、リクエストしたユーザが作成者か管理者であることを確認する。
+// 失敗した場合は返すべきステータスコードとエラーを返す。
+func (h *Handler) getEditableSoundPack(c echo.Context, packId string) (repository.SoundPack, int, error) {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return repository.SoundPack{}, http.StatusUnauthorized, err
+	}
+
+	pack, err := h.repo.GetSoundPack(packId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.SoundPack{}, http.StatusNotFound, errors.New("sound pack not found")
+	}
+	if err != nil {
+		return repository.SoundPack{}, http.StatusInternalServerError, fmt.Errorf("failed to get sound pack: %w", err)
+	}
+
+	if pack.CreatorID != userId && !isSoundboardAdmin(userId) {
+		return repository.SoundPack{}, http.StatusForbidden, errors.New("only the creator or an admin can modify this sound pack")
+	}
+	return pack, http.StatusOK, nil
+}
+
+// applySoundPackRequest はリクエストを検証してパックに反映する。
+// 失敗した場合は返すべきステータスコードとエラーを返す。
+func (h *Handler) applySoundPackRequest(pack *repository.SoundPack, req models.SoundboardPackRequest) (int, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return http.StatusBadRequest, errors.New("name is required")
+	}
+	soundIds := uniqueStrings(req.SoundIds)
+	if len(soundIds) > maxPackSounds {
+		return http.StatusBadRequest, fmt.Errorf("too many sounds (%d). Must be <= %d", len(soundIds), maxPackSounds)
+	}
+	count, err := h.repo.CountExistingSounds(soundIds)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check sounds: %w", err)
+	}
+	if count != len(soundIds) {
+		return http.StatusBadRequest, errors.New("soundIds contains a sound that does not exist")
+	}
+
+	pack.Name = name
+	pack.Description = deref(req.Description)
+	pack.SoundIDs = soundIds
+	return http.StatusOK, nil
+}
+
+// GetChannelSoundboardPacks returns the sound packs enabled in a channel
+// GET /channels/{channelId}/soundboard-packs
+func (h *Handler) GetChannelSoundboardPacks(c echo.Context, channelId uuid.UUID) error {
+	if !h.repo.CheckChannelExistence(channelId.String()) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "channel not found",
+		})
+	}
+	packs, err := h.repo.GetChannelSoundPacks(channelId.String())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get channel sound packs: %v", err),
+		})
+	}
+	return c.JSON(http.StatusOK, soundboardPacks(packs))
+}
+
+// PutChannelSoundboardPacks replaces the sound packs enabled in a channel
+// PUT /channels/{channelId}/soundboard-packs
+func (h *Handler) PutChannelSoundboardPacks(c echo.Context, channelId uuid.UUID) error {
+	var req models.ChannelSoundboardPacksRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+	if !h.repo.CheckChannelExistence(channelId.String()) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "channel not found",
+		})
+	}
+
+	packIds := uniqueStrings(req.PackIds)
+	count, err := h.repo.CountExistingSoundPacks(packIds)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to check sound packs: %v", err),
+		})
+	}
+	if count != len(packIds) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "packIds contains a pack that does not exist",
+		})
+	}
+
+	if err := h.repo.SetChannelSoundPacks(channelId.String(), packIds); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to set channel sound packs: %v", err),
+		})
+	}
+	return h.GetChannelSoundboardPacks(c, channelId)
+}
+
+func soundboardPack(pack repository.SoundPack) models.SoundboardPack {
+	soundIds := pack.SoundIDs
+	if soundIds == nil {
+		soundIds = []string{}
+	}
+	return models.SoundboardPack{
+		PackId:      pack.PackID,
+		Name:        pack.Name,
+		Description: pack.Description,
+		CreatorId:   pack.CreatorID,
+		SoundIds:    soundIds,
+		CreatedAt:   pack.CreatedAt,
+	}
+}
+
+func soundboardPacks(packs []repository.SoundPack) []models.SoundboardPack {
+	resp := make([]models.SoundboardPack, 0, len(packs))
+	for _, pack := range packs {
+		resp = append(resp, soundboardPack(pack))
+	}
+	return resp
+}
+
+// uniqueStrings は空文字列と重複を取り除く (順序は保つ)
+func uniqueStrings(values []string) []string {
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && !slices.Contains(unique, v) {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/pkg/util"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// GetSoundboardQuota returns the sound count and upload size used by the user and the whole soundboard
+// GET /soundboard/quota
+func (h *Handler) GetSoundboardQuota(c echo.Context) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	usage, err := h.repo.GetSoundUsage(userId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound usage: %v", err),
+		})
+	}
+
+	quota := config.SoundboardQuota()
+	return c.JSON(http.StatusOK, models.SoundboardQuota{
+		Sounds:     quotaUsage(usage.UserSounds, quota.MaxSoundsPerUser),
+		Bytes:      quotaUsage(usage.UserBytes, quota.MaxBytesPerUser),
+		TotalBytes: quotaUsage(usage.TotalBytes, quota.MaxTotalBytes),
+	})
+}
+
+// quotaUsage は使用量と上限をレスポンスの形にする。上限が 0 (無制限) の場合は limit を省略する
+func quotaUsage(used, limit int64) models.SoundboardQuotaUsage {
+	usage := models.SoundboardQuotaUsage{Used: used}
+	if limit > 0 {
+		usage.Limit = &limit
+	}
+	return usage
+}
+
+// checkSoundQuota はファイルを変換・保存する前に、sizeBytes のサウンドを登録すると上限を超えないかを確認する。
+// 登録時にもトランザクションの中で確認するので、ここでは早めに断るためだけに使う。
+func (h *Handler) checkSoundQuota(userId string, sizeBytes int64) error {
+	usage, err := h.repo.GetSoundUsage(userId)
+	if err != nil {
+		return fmt.Errorf("failed to get sound usage: %w", err)
+	}
+	return repository.CheckSoundQuota(config.SoundboardQuota(), usage, sizeBytes)
+}
+
+// soundQuotaStatus は err が上限を超えたことによるエラーであれば 413 (サイズ) か 422 (サウンドの数) を、それ以外は 500 を返す
+func soundQuotaStatus(err error) int {
+	var quotaErr *repository.SoundQuotaError
+	if !errors.As(err, &quotaErr) {
+		return http.StatusInternalServerError
+	}
+	if quotaErr.Code == repository.SoundQuotaUserSounds {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusRequestEntityTooLarge
+}
+
+// soundQuotaExceeded は上限を超えた時の 413 (サイズ) か 422 (サウンドの数) を、どの上限を超えたかと共に返す
+func soundQuotaExceeded(c echo.Context, err *repository.SoundQuotaError) error {
+	return c.JSON(soundQuotaStatus(err), models.SoundboardQuotaError{
+		Error:     err.Error(),
+		Code:      models.SoundboardQuotaErrorCode(err.Code),
+		Limit:     err.Limit,
+		Used:      err.Used,
+		Requested: err.Requested,
+	})
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/pkg/util"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// maxSoundReportReasonLength は通報の理由の最大文字数
+const maxSoundReportReasonLength = 500
+
+// PostSoundboardReport reports an offensive or too loud sound, hiding it once enough reports are collected
+// POST /soundboard/{soundId}/report
+func (h *Handler) PostSoundboardReport(c echo.Context, soundId string) error {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	var req models.SoundboardReportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+	reason := ""
+	if req.Reason != nil {
+		reason = strings.TrimSpace(*req.Reason)
+	}
+	if utf8.RuneCountInString(reason) > maxSoundReportReasonLength {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("reason must be at most %d characters", maxSoundReportReasonLength),
+		})
+	}
+
+	sound, err := h.repo.GetSoundboardByID(soundId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "sound not found",
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get soundboard item: %v", err),
+		})
+	}
+
+	threshold := config.SoundboardReportThreshold()
+	result, err := h.repo.ReportSound(sound.SoundID, userId, reason, threshold)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to report sound: %v", err),
+		})
+	}
+	// 非表示になったのは1回だけ、メンション付きで通知する。
+	// 最初の通報も (非表示になるまで管理者が気付かないことが無いよう) 設定で無効にしなければ通知する
+	switch {
+	case result.NewlyHidden:
+		h.repo.SendSoundHiddenMessageToTraQ(sound, result.OpenReports)
+	case result.FirstOpenReport && config.SoundboardReportNotifyFirst():
+		h.repo.SendSoundReportedMessageToTraQ(sound, reason, threshold)
+	}
+
+	return c.JSON(http.StatusOK, models.SoundboardReportResponse{
+		OpenReports: result.OpenReports,
+		Hidden:      result.Hidden,
+	})
+}
+
+// GetAdminSoundboardReports returns the sounds waiting for review
+// GET /admin/soundboard/reports
+func (h *Handler) GetAdminSoundboardReports(c echo.Context) error {
+	userId, status, err := soundboardAdmin(c)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	reported, err := h.repo.GetReportedSounds()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get reported sounds: %v", err),
+		})
+	}
+	sounds := make([]repository.Sound, 0, len(reported))
+	ids := make([]string, 0, len(reported))
+	for _, r := range reported {
+		sounds = append(sounds, r.Sound)
+		ids = append(ids, r.SoundID)
+	}
+	items, err := h.soundboardItems(userId, sounds)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	reports, err := h.repo.GetOpenSoundReports(ids)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound reports: %v", err),
+		})
+	}
+
+	queue := make([]models.SoundboardReportedSound, 0, len(reported))
+	for i, r := range reported {
+		entries := make([]models.SoundboardReport, 0, len(reports[r.SoundID]))
+		for _, report := range reports[r.SoundID] {
+			entries = append(entries, models.SoundboardReport{
+				ReporterId: report.ReporterID,
+				Reason:     report.Reason,
+				CreatedAt:  report.CreatedAt,
+			})
+		}
+		queue = append(queue, models.SoundboardReportedSound{
+			Sound:          items[i],
+			OpenReports:    r.OpenReports,
+			LastReportedAt: r.LastReportedAt,
+			Reports:        entries,
+		})
+	}
+	return c.JSON(http.StatusOK, queue)
+}
+
+// PostAdminSoundboardReportApprove dismisses the reports of a sound and shows it again
+// POST /admin/soundboard/reports/{soundId}/approve
+func (h *Handler) PostAdminSoundboardReportApprove(c echo.Context, soundId string) error {
+	sound, status, err := h.getModeratedSound(c, soundId)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := h.repo.ApproveSound(sound.SoundID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to approve sound: %v", err),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PostAdminSoundboardReportRemove deletes a reported sound and its files
+// POST /admin/soundboard/reports/{soundId}/remove
+func (h *Handler) PostAdminSoundboardReportRemove(c echo.Context, soundId string) error {
+	sound, status, err := h.getModeratedSound(c, soundId)
+	if err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := h.deleteSound(ctx, sound); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// soundboardAdmin はリクエストしたユーザが管理者であることを確認し、そのユーザIDを返す。
+// 失敗した場合は返すべきステータスコードとエラーを返す。
+func soundboardAdmin(c echo.Context) (string, int, error) {
+	userId, err := util.GetTraqUserID(c)
+	if err != nil {
+		return "", http.StatusUnauthorized, err
+	}
+	if !isSoundboardAdmin(userId) {
+		return "", http.StatusForbidden, errors.New("only admins can moderate sounds")
+	}
+	return userId, http.StatusOK, nil
+}
+
+// isSoundboardAdmin はユーザがサウンドボードの管理者かを返す
+func isSoundboardAdmin(userId string) bool {
+	return slices.Contains(config.SoundboardAdmins(), userId)
+}
+
+// getModeratedSound はリクエストしたユーザが管理者であることを確認してサウンドを取得する。
+// 失敗した場合は返すべきステータスコードとエラーを返す。
+func (h *Handler) getModeratedSound(c echo.Context, soundId string) (repository.Sound, int, error) {
+	if _, status, err := soundboardAdmin(c); err != nil {
+		return repository.Sound{}, status, err
+	}
+	sound, err := h.repo.GetSoundboardByID(soundId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.Sound{}, http.StatusNotFound, errors.New("sound not found")
+	}
+	if err != nil {
+		return repository.Sound{}, http.StatusInternalServerError, fmt.Errorf("failed to get soundboard item: %w", err)
+	}
+	return sound, http.StatusOK, nil
+}
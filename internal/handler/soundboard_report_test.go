@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestAdminSoundboardReportsRequireAdmin は管理者以外のモデレーション操作を DB に触れる前に断ることを確認する
+func TestAdminSoundboardReportsRequireAdmin(t *testing.T) {
+	t.Setenv("SOUNDBOARD_ADMINS", "admin1,admin2")
+	// repo を持たない Handler なので、権限の確認より先に DB に触れると panic する
+	h := &Handler{}
+
+	endpoints := []struct {
+		name string
+		call func(c echo.Context) error
+	}{
+		{"list", h.GetAdminSoundboardReports},
+		{"approve", func(c echo.Context) error { return h.PostAdminSoundboardReportApprove(c, "sound") }},
+		{"remove", func(c echo.Context) error { return h.PostAdminSoundboardReportRemove(c, "sound") }},
+	}
+	users := []struct {
+		name   string
+		userID any
+		want   int
+	}{
+		{"unauthenticated", nil, http.StatusUnauthorized},
+		{"not an admin", "alice", http.StatusForbidden},
+		{"prefix of an admin", "admin", http.StatusForbidden},
+	}
+
+	e := echo.New()
+	for _, ep := range endpoints {
+		for _, u := range users {
+			t.Run(ep.name+"/"+u.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+				if u.userID != nil {
+					c.Set("traqUserID", u.userID)
+				}
+				if err := ep.call(c); err != nil {
+					t.Fatal(err)
+				}
+				if rec.Code != u.want {
+					t.Errorf("status = %d, want %d: %s", rec.Code, u.want, rec.Body)
+				}
+			})
+		}
+	}
+}
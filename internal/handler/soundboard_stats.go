@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+const (
+	defaultRankingLimit = 20
+	// statsTopCount は統計で返す再生回数の多いルーム・ユーザの数
+	statsTopCount = 5
+)
+
+// GetSoundboardStats returns play statistics of a sound
+// GET /soundboard/{soundId}/stats
+func (h *Handler) GetSoundboardStats(c echo.Context, soundId string, params models.GetSoundboardStatsParams) error {
+	period := models.All
+	if params.Period != nil {
+		period = *params.Period
+	}
+	since, err := statsPeriodStart(period, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if _, err := h.repo.GetSoundboardByID(soundId); errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "sound not found",
+		})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get soundboard item: %v", err),
+		})
+	}
+
+	stats, err := h.repo.GetSoundPlayStats(soundId, since, statsTopCount)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound stats: %v", err),
+		})
+	}
+
+	resp := models.SoundboardStats{
+		SoundId:       soundId,
+		Period:        period,
+		Plays:         stats.Plays,
+		UniquePlayers: stats.UniquePlayers,
+		TopRooms:      roomPlays(stats.TopRooms),
+		TopPlayers:    make([]models.SoundboardUserPlays, 0, len(stats.TopPlayers)),
+	}
+	if stats.LastPlayedAt.Valid {
+		resp.LastPlayedAt = &stats.LastPlayedAt.Time
+	}
+	for _, player := range stats.TopPlayers {
+		resp.TopPlayers = append(resp.TopPlayers, models.SoundboardUserPlays{
+			UserId: player.UserID,
+			Plays:  player.Plays,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetSoundboardRanking returns the most played sounds and rooms in a period
+// GET /soundboard/ranking
+func (h *Handler) GetSoundboardRanking(c echo.Context, params models.GetSoundboardRankingParams) error {
+	period := models.Week
+	if params.Period != nil {
+		period = *params.Period
+	}
+	since, err := statsPeriodStart(period, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	limit := defaultRankingLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > maxSoundListLimit {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("limit must be between 1 and %d", maxSoundListLimit),
+		})
+	}
+
+	sounds, err := h.repo.GetSoundRanking(since, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get sound ranking: %v", err),
+		})
+	}
+	rooms, err := h.repo.GetRoomPlayRanking(since, statsTopCount)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get room ranking: %v", err),
+		})
+	}
+
+	resp := models.SoundboardRanking{
+		Period:   period,
+		Since:    since,
+		Sounds:   make([]models.SoundboardRankingItem, 0, len(sounds)),
+		TopRooms: roomPlays(rooms),
+	}
+	for _, sound := range sounds {
+		resp.Sounds = append(resp.Sounds, models.SoundboardRankingItem{
+			SoundId:       sound.SoundID,
+			SoundName:     sound.SoundName,
+			StampId:       sound.StampID,
+			Plays:         sound.Plays,
+			UniquePlayers: sound.UniquePlayers,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// statsPeriodStart は集計期間の開始日時を返す。全期間の場合は nil を返す
+func statsPeriodStart(period models.SoundboardStatsPeriod, now time.Time) (*time.Time, error) {
+	var since time.Time
+	switch period {
+	case models.Day:
+		since = now.Add(-24 * time.Hour)
+	case models.Week:
+		since = now.AddDate(0, 0, -7)
+	case models.Month:
+		since = now.AddDate(0, 0, -30)
+	case models.All:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid period: %s", period)
+	}
+	return &since, nil
+}
+
+func roomPlays(rooms []repository.RoomPlayCount) []models.SoundboardRoomPlays {
+	resp := make([]models.SoundboardRoomPlays, 0, len(rooms))
+	for _, room := range rooms {
+		roomId, err := uuid.Parse(room.RoomID)
+		if err != nil {
+			continue
+		}
+		resp = append(resp, models.SoundboardRoomPlays{
+			RoomId: roomId,
+			Plays:  room.Plays,
+		})
+	}
+	return resp
+}
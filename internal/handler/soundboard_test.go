@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+	"github.com/pikachu0310/livekit-server/internal/pkg/ratelimit"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// TestProcessSoundUnavailableCodec はデコーダ無しでビルドされたサーバが、読めるファイルを 400 ではなく 415 で断ることを確認する
+func TestProcessSoundUnavailableCodec(t *testing.T) {
+	tests := []struct {
+		file      string
+		available func() bool
+	}{
+		{"silence.opus", audio.OpusAvailable},
+		{"silence.m4a", audio.AACAvailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if tt.available() {
+				t.Skip("codec is available in this build")
+			}
+			data, err := os.ReadFile("../pkg/audio/testdata/" + tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = processSound(data, repository.SoundClip{})
+			if err == nil {
+				t.Fatal("processSound succeeded without the decoder")
+			}
+			if status := processSoundStatus(err); status != http.StatusUnsupportedMediaType {
+				t.Errorf("status = %d, want %d (err: %v)", status, http.StatusUnsupportedMediaType, err)
+			}
+			if !strings.Contains(err.Error(), "this server cannot decode") {
+				t.Errorf("error = %q, want it to say the server cannot decode the codec", err)
+			}
+		})
+	}
+}
+
+func TestProcessSoundStatusInvalidFile(t *testing.T) {
+	_, _, err := processSound([]byte("not audio"), repository.SoundClip{})
+	if err == nil {
+		t.Fatal("processSound accepted an invalid file")
+	}
+	if status := processSoundStatus(err); status != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestTooManyPlays(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/api/soundboard/play", nil), rec)
+	if err := tooManyPlays(c, 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q", got, "2")
+	}
+	var body models.SoundboardRateLimitError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.RetryAfter != 2 {
+		t.Errorf("retryAfter = %d, want 2", body.RetryAfter)
+	}
+}
+
+// TestRefundPlay は再生できなかった場合にユーザとルームの両方の回数が戻ることを確認する
+func TestRefundPlay(t *testing.T) {
+	h := &Handler{
+		userPlayLimit: ratelimit.New(1, time.Hour),
+		roomPlayLimit: ratelimit.New(1, time.Hour),
+	}
+	roomID := uuid.New()
+	for i := range 3 {
+		if ok, _ := h.userPlayLimit.Allow("alice"); !ok {
+			t.Fatalf("play %d: user limit was not refunded", i)
+		}
+		if ok, _ := h.roomPlayLimit.Allow(roomID.String()); !ok {
+			t.Fatalf("play %d: room limit was not refunded", i)
+		}
+		h.refundPlay("alice", roomID)
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/metrics"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+const (
+	// sseSendQueueSize はクライアントごとの送信キューの長さ。溢れたクライアントは切断する。
+	sseSendQueueSize = 64
+	// sseKeepAlivePeriod はプロキシに接続を切られないようにコメント行を送る間隔
+	sseKeepAlivePeriod = 30 * time.Second
+)
+
+// sseClient は Server-Sent Events クライアント1接続分の状態。
+// 購読するルームは接続時に決まり、以降は変わらない。
+type sseClient struct {
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+
+	global bool
+	rooms  map[uuid.UUID]bool
+
+	mu sync.Mutex
+	// seq はこの接続に最後に送ったメッセージの通し番号 (WebSocket と同じく接続ごと)
+	seq int64
+}
+
+func newSseClient(params models.GetRoomsStreamParams) *sseClient {
+	client := &sseClient{
+		queue:  make(chan []byte, sseSendQueueSize),
+		done:   make(chan struct{}),
+		global: params.Global != nil && *params.Global,
+		rooms:  make(map[uuid.UUID]bool),
+	}
+	if params.Rooms != nil {
+		for _, roomID := range *params.Rooms {
+			client.rooms[roomID] = true
+		}
+	}
+	return client
+}
+
+func (c *sseClient) subscribes(roomID uuid.UUID) bool {
+	return c.global || c.rooms[roomID]
+}
+
+// deliver はイベントを SSE のフレームにして送信キューに積む。
+// 再開に使えるよう id には全接続で共通の通し番号を、取りこぼしを検知できるよう WsEvent の seq には接続ごとの通し番号を入れる。
+func (c *sseClient) deliver(event hubEvent) error {
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	c.mu.Unlock()
+
+	data, err := json.Marshal(models.WsEvent{
+		Version: wsProtocolVersion,
+		Type:    event.Type,
+		Seq:     seq,
+		RoomId:  event.RoomID,
+		Payload: event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	frame := []byte(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data))
+
+	select {
+	case c.queue <- frame:
+		return nil
+	default:
+		metrics.SseMessagesDropped.Add(1)
+		return errSlowConsumer
+	}
+}
+
+func (c *sseClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *sseClient) onDrop() {
+	metrics.SseSlowConsumerDisconnects.Add(1)
+}
+
+// GetRoomsStream Server-Sent Events エンドポイント: GET /rooms/stream
+// GetWs と同じイベントを text/event-stream で送る。Last-Event-ID があればそれ以降のイベントを再送する。
+func (h *Handler) GetRoomsStream(c echo.Context, params models.GetRoomsStreamParams) error {
+	lastEventID, resume := int64(0), false
+	if params.LastEventID != nil && *params.LastEventID != "" {
+		id, err := strconv.ParseInt(*params.LastEventID, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid Last-Event-ID",
+			})
+		}
+		lastEventID, resume = id, true
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// nginx 等のリバースプロキシにバッファリングさせない
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	client := newSseClient(params)
+	defer client.close()
+	metrics.SseConnections.Add(1)
+	defer func() {
+		h.events.unregister(client)
+		metrics.SseConnections.Add(-1)
+	}()
+
+	// 再開できる場合は差分を、そうでなければ現在のルーム状態を送信
+	if resume {
+		if err := h.events.resume(client, lastEventID); err != nil {
+			fmt.Printf("Failed to resume SSE client: %v\n", err)
+			return nil
+		}
+	} else {
+		h.events.register(client)
+		if err := h.events.sendSnapshot(client); err != nil {
+			fmt.Printf("Failed to send room state to SSE client: %v\n", err)
+			return nil
+		}
+	}
+
+	ticker := time.NewTicker(sseKeepAlivePeriod)
+	defer ticker.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-client.done:
+			return nil
+		case frame := <-client.queue:
+			if _, err := res.Write(frame); err != nil {
+				return nil
+			}
+			res.Flush()
+			metrics.SseMessagesSent.Add(1)
+		case <-ticker.C:
+			if _, err := res.Write([]byte(": keepalive\n\n")); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// TestSseClientSeq はルームを絞って購読した SSE クライアントの seq が、全接続で共通の id と違って飛ばずに1ずつ増えることを確認する
+func TestSseClientSeq(t *testing.T) {
+	hub := newEventHub(func() []models.RoomWithParticipants { return nil })
+	watched, other := uuid.New(), uuid.New()
+	client := newSseClient(models.GetRoomsStreamParams{Rooms: &[]uuid.UUID{watched}})
+	hub.register(client)
+
+	for i := range 6 {
+		roomID := other
+		if i%2 == 0 {
+			roomID = watched
+		}
+		hub.publish(models.MetadataChanged, roomID, i)
+	}
+
+	var lastID int64
+	for want := int64(1); want <= 3; want++ {
+		frame := string(<-client.queue)
+		var id int64
+		var eventType string
+		if _, err := fmt.Sscanf(frame, "id: %d\nevent: %s\n", &id, &eventType); err != nil {
+			t.Fatalf("parse frame %q: %v", frame, err)
+		}
+		_, data, _ := strings.Cut(frame, "data: ")
+		var event models.WsEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Seq != want {
+			t.Errorf("seq = %d, want %d", event.Seq, want)
+		}
+		if id <= lastID {
+			t.Errorf("id = %d, want greater than %d", id, lastID)
+		}
+		lastID = id
+	}
+	select {
+	case frame := <-client.queue:
+		t.Errorf("received an event of an unsubscribed room: %s", frame)
+	default:
+	}
+	// 他のルームのイベントの分だけ id は飛んでいる
+	if lastID != 5 {
+		t.Errorf("last id = %d, want 5", lastID)
+	}
+}
@@ -3,10 +3,15 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/webhook"
+	"github.com/pikachu0310/livekit-server/internal/repository"
+	"github.com/pikachu0310/livekit-server/openapi/models"
 )
 
 // LiveKitWebhook POST /webhook
@@ -23,33 +28,147 @@ func (h *Handler) LiveKitWebhook(c echo.Context) error {
 		})
 	}
 
-	// ルーム状態を更新
+	// LiveKit は同じイベントを再送することがあるので、イベントIDで重複を除く
+	if event.Id != "" {
+		inserted, err := h.repo.SaveWebhookEvent(event, time.Now())
+		if err != nil {
+			fmt.Printf("Failed to save webhook event: %v\n", err)
+		} else if !inserted {
+			fmt.Printf("Duplicate webhook event: id=%s, event=%s\n", event.Id, event.Event)
+			return c.NoContent(http.StatusOK)
+		}
+	}
+
+	// ルーム状態を更新する。順番が入れ替わって届いた古いイベントは無視する
+	result := h.repo.ApplyWebhookEvent(event)
+	if !result.Applied && !result.Unhandled {
+		fmt.Printf("Skipped webhook event: id=%s, event=%s, createdAt=%d\n", event.Id, event.Event, event.CreatedAt)
+		return c.NoContent(http.StatusOK)
+	}
+	roomID := result.RoomID
+
+	// 差分をWebSocketで通知し、履歴の記録と traQ への通知を行う
 	switch event.Event {
+	case webhook.EventRoomStarted:
+		fmt.Printf("Room started: room=%s", event.Room.Name)
+		if result.NewRoom {
+			h.publishRoomStarted(roomID)
+		}
+		isWebinar := false
+		if room, ok := h.repo.RoomState.Get(roomID); ok {
+			isWebinar = room.IsWebinar != nil && *room.IsWebinar
+		}
+		// 通話履歴はルームIDが UUID のルームだけ記録する
+		if _, err := uuid.Parse(event.Room.Name); err == nil {
+			if _, err := h.repo.StartRoomSession(event.Room.Name, isWebinar, webhookEventTime(event)); err != nil {
+				fmt.Printf("Failed to record room started: %v\n", err)
+			}
+		}
 	case webhook.EventParticipantJoined:
 		fmt.Printf("Participant joined: room=%s, participant=%s", event.Room.Name, event.Participant.Identity)
-		h.repo.AddParticipantToRoomState(event.Room, event.Participant)
+		if result.NewRoom {
+			h.publishRoomStarted(roomID)
+		} else {
+			h.publishParticipantJoined(roomID, event.Participant.Identity)
+		}
+		h.recordParticipantJoined(event)
 		h.repo.SendJoinMessageToTraQ(event.Room.Name, event.Participant.Name)
 	case webhook.EventParticipantLeft:
 		fmt.Printf("Participant left: room=%s, participant=%s", event.Room.Name, event.Participant.Identity)
-		h.repo.RemoveParticipant(event.Room.Name, event.Participant.Identity)
+		h.publishParticipantLeft(roomID, event.Participant.Identity)
+		if err := h.repo.RecordParticipantLeft(event.Room.Name, event.Participant.Identity, webhookEventTime(event)); err != nil {
+			fmt.Printf("Failed to record participant left: %v\n", err)
+		}
 		h.repo.SendLeaveMessageToTraQ(event.Room.Name, event.Participant.Name)
 	case webhook.EventRoomFinished:
 		fmt.Printf("Room finished: room=%s", event.Room.Name)
-		h.repo.RemoveRoomState(event.Room.Name)
+		h.publishRoomFinished(roomID)
+		h.player.roomFinished(roomID)
+		if err := h.repo.EndRoomSession(event.Room.Name, webhookEventTime(event)); err != nil {
+			fmt.Printf("Failed to record room finished: %v\n", err)
+		}
 		h.repo.SendEndRoomMessageToTraQ(event.Room.Name)
-	//case webhook.EventTrackPublished:
-	//	fmt.Printf("Track published: room=%s, participant=%s, track=%s", event.Room.Name, event.Participant.Identity, event.Track.Sid)
-	//	if h.repo.CheckUserExistenceByName(event.Participant.Name) {
-	//		if len(event.Participant.Attributes) >= 1 {
-	//			h.repo.SendStartScreenShareMessageToTraQ(event.Room.Name, event.Participant.Name)
-	//		}
-	//	}
+	case webhook.EventTrackPublished:
+		fmt.Printf("Track published: room=%s, participant=%s, track=%s", event.Room.Name, event.Participant.Identity, event.Track.Sid)
+		if result.Changed {
+			h.publishTrackPublished(roomID, event.Participant.Identity, result.Track)
+		}
+		if result.Track.Source == models.ScreenShare && h.repo.CheckUserExistenceByName(event.Participant.Name) {
+			h.repo.SendStartScreenShareMessageToTraQ(event.Room.Name, event.Participant.Name)
+		}
+	case webhook.EventTrackUnpublished:
+		fmt.Printf("Track unpublished: room=%s, participant=%s, track=%s", event.Room.Name, event.Participant.Identity, event.Track.Sid)
+		if result.Changed {
+			h.publishTrackUnpublished(roomID, event.Participant.Identity, result.Track)
+		}
+		if result.Track.Source == models.ScreenShare && h.repo.CheckUserExistenceByName(event.Participant.Name) {
+			h.repo.SendStopScreenShareMessageToTraQ(event.Room.Name, event.Participant.Name)
+		}
+	case webhook.EventEgressStarted, webhook.EventEgressUpdated:
+		fmt.Printf("Egress updated: room=%s, egress=%s, status=%s", event.EgressInfo.RoomName, event.EgressInfo.EgressId, event.EgressInfo.Status)
+		if result.Changed {
+			h.publishEgress(models.EgressUpdated, roomID, result.Egress)
+		}
+	case webhook.EventEgressEnded:
+		fmt.Printf("Egress ended: room=%s, egress=%s, status=%s", event.EgressInfo.RoomName, event.EgressInfo.EgressId, event.EgressInfo.Status)
+		if result.Changed {
+			h.publishEgress(models.EgressEnded, roomID, result.Egress)
+		}
+	case webhook.EventIngressStarted:
+		fmt.Printf("Ingress started: room=%s, ingress=%s", event.IngressInfo.RoomName, event.IngressInfo.IngressId)
+		if result.Changed {
+			h.publishIngress(models.IngressUpdated, roomID, result.Ingress)
+		}
+	case webhook.EventIngressEnded:
+		fmt.Printf("Ingress ended: room=%s, ingress=%s", event.IngressInfo.RoomName, event.IngressInfo.IngressId)
+		if result.Changed {
+			h.publishIngress(models.IngressEnded, roomID, result.Ingress)
+		}
+		// サウンドボードの再生が終わった場合は Ingress を削除し、キューの次のサウンドに進む
+		h.player.ingressEnded(event.IngressInfo.IngressId)
 	default:
-		fmt.Printf("Unhandled webhook event: %s", event.Event)
+		fmt.Printf("Unhandled webhook event: %s\n", event.Event)
 	}
 
-	// 全ルームの状態をWebSocketでブロードキャスト
-	h.broadcastRoomState()
-
 	return c.NoContent(http.StatusOK)
 }
+
+// recordParticipantJoined は参加者の入室を通話履歴に記録する。
+// 表示名は参加者自身が変更できるので、ユーザは identity から決め、ユーザのトークンで参加していない identity
+// (サウンドボードの Ingress など) は記録しない。
+func (h *Handler) recordParticipantJoined(event *livekit.WebhookEvent) {
+	roomID, err := uuid.Parse(event.Room.Name)
+	if err != nil {
+		return
+	}
+	userID, ok := repository.UserIDOfIdentity(event.Participant.Identity)
+	if !ok {
+		return
+	}
+	isWebinar := false
+	participants := 1
+	if room, ok := h.repo.RoomState.Get(roomID); ok {
+		isWebinar = room.IsWebinar != nil && *room.IsWebinar
+		participants = len(room.Participants)
+	}
+
+	err = h.repo.RecordParticipantJoined(
+		event.Room.Name,
+		userID,
+		event.Participant.Identity,
+		isWebinar,
+		webhookEventTime(event),
+		participants,
+	)
+	if err != nil {
+		fmt.Printf("Failed to record participant joined: %v\n", err)
+	}
+}
+
+// webhookEventTime はイベントの発生時刻を返す (未設定の場合は受信時刻)
+func webhookEventTime(event *livekit.WebhookEvent) time.Time {
+	if event.CreatedAt == 0 {
+		return time.Now()
+	}
+	return time.Unix(event.CreatedAt, 0)
+}
@@ -3,102 +3,258 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
-	"net/http"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+	"github.com/pikachu0310/livekit-server/internal/pkg/metrics"
+	"github.com/pikachu0310/livekit-server/openapi/models"
 )
 
-// GetWs WebSocketエンドポイント: GET /ws
-func (h *Handler) GetWs(c echo.Context) error {
-	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+// wsSubprotocol はサブプロトコルでトークンを渡す場合に一緒に指定してもらうプロトコル名
+const wsSubprotocol = "qall.v1"
+
+const (
+	// wsSendQueueSize はクライアントごとの送信キューの長さ。溢れたクライアントは切断する。
+	wsSendQueueSize = 64
+	// wsWriteWait は1メッセージの書き込みにかけてよい時間
+	wsWriteWait = 10 * time.Second
+	// wsPongWait はクライアントからの pong を待つ時間
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod は ping を送る間隔 (wsPongWait より短くする)
+	wsPingPeriod = wsPongWait * 9 / 10
+	// wsMaxMessageSize はクライアントから受け付けるメッセージの最大サイズ
+	wsMaxMessageSize = 4096
+)
+
+// wsClient はWebSocketクライアント1接続分の状態。
+// 書き込みは writePump だけが行い、他の goroutine は送信キューに積むだけにする。
+type wsClient struct {
+	conn      *websocket.Conn
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu sync.Mutex
+	// seq はこの接続に最後に送ったメッセージの通し番号
+	seq int64
+	// global が true なら全ルームのイベントを受け取る
+	global bool
+	rooms  map[uuid.UUID]bool
+}
+
+func newWsClient(conn *websocket.Conn) *wsClient {
+	return &wsClient{
+		conn:  conn,
+		queue: make(chan []byte, wsSendQueueSize),
+		done:  make(chan struct{}),
+		rooms: make(map[uuid.UUID]bool),
+	}
+}
+
+func (c *wsClient) subscribes(roomID uuid.UUID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.global || c.rooms[roomID]
+}
+
+// deliver は接続ごとの seq を振ってイベントを送信キューに積む
+func (c *wsClient) deliver(event hubEvent) error {
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	c.mu.Unlock()
+
+	message, err := json.Marshal(models.WsEvent{
+		Version: wsProtocolVersion,
+		Type:    event.Type,
+		Seq:     seq,
+		RoomId:  event.RoomID,
+		Payload: event.Payload,
+	})
 	if err != nil {
-		fmt.Printf("Failed to upgrade to WebSocket: %v", err)
 		return err
 	}
-	defer conn.Close()
 
-	// クライアントを登録
-	h.Mutex.Lock()
-	h.Clients[conn] = true
-	h.Mutex.Unlock()
+	select {
+	case c.queue <- message:
+		return nil
+	default:
+		metrics.WsMessagesDropped.Add(1)
+		return errSlowConsumer
+	}
+}
 
-	// 現在のルーム状態を送信
-	err = h.broadcastRoomStateToSingleClient(conn)
+func (c *wsClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+func (c *wsClient) onDrop() {
+	metrics.WsSlowConsumerDisconnects.Add(1)
+}
+
+// writePump は送信キューのメッセージと定期的な ping を書き込む。接続が閉じるまでブロックする。
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case message := <-c.queue:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				fmt.Printf("Failed to send message to WebSocket client: %v\n", err)
+				return
+			}
+			metrics.WsMessagesSent.Add(1)
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// GetWs WebSocketエンドポイント: GET /ws
+// 認証はミドルウェアで済ませてあり、ここでは購読の管理とイベントの送信を行う。
+func (h *Handler) GetWs(c echo.Context, params models.GetWsParams) error {
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
 	if err != nil {
-		fmt.Printf("Failed to send room state to WebSocket client: %v", err)
-		delete(h.Clients, conn)
+		fmt.Printf("Failed to upgrade to WebSocket: %v", err)
 		return err
 	}
 
+	// 接続時に指定されたルームを購読
+	client := newWsClient(conn)
+	defer client.close()
+	client.global = params.Global != nil && *params.Global
+	if params.Rooms != nil {
+		for _, roomID := range *params.Rooms {
+			client.rooms[roomID] = true
+		}
+	}
+
+	// クライアントを登録し、書き込み用の goroutine を開始
+	h.events.register(client)
+	metrics.WsConnections.Add(1)
+	go client.writePump()
+
 	// WebSocket切断時にクライアントを削除
 	defer func() {
-		h.Mutex.Lock()
-		delete(h.Clients, conn)
-		h.Mutex.Unlock()
+		h.events.unregister(client)
+		metrics.WsConnections.Add(-1)
 	}()
 
-	// WebSocket接続維持
+	// 現在のルーム状態を送信
+	if err := h.events.sendSnapshot(client); err != nil {
+		fmt.Printf("Failed to send room state to WebSocket client: %v\n", err)
+		return nil
+	}
+
+	// pong が届かなくなったら読み込みをタイムアウトさせて切断する
+	conn.SetReadLimit(wsMaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	// クライアントからのメッセージを処理
 	for {
-		_, _, err := conn.ReadMessage()
+		_, data, err := conn.ReadMessage()
 		if err != nil {
-			fmt.Printf("WebSocket connection error: %v", err)
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				fmt.Printf("WebSocket connection error: %v\n", err)
+			}
+			break
+		}
+
+		var msg models.WsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		switch msg.Type {
+		case models.Subscribe, models.Unsubscribe:
+			client.updateSubscription(msg)
+		case models.Resync:
+			// seq の抜けを検知したクライアントに snapshot を送り直す
+		default:
+			continue
+		}
+
+		if err := h.events.sendSnapshot(client); err != nil {
+			fmt.Printf("Failed to send room state to WebSocket client: %v\n", err)
 			break
 		}
 	}
 	return nil
 }
 
-// 現在のルーム状態をWebSocketでブロードキャスト
-func (h *Handler) broadcastRoomState() {
-	h.Mutex.Lock()
-	defer h.Mutex.Unlock()
+// updateSubscription は subscribe / unsubscribe メッセージに従って購読するルームを変更する
+func (c *wsClient) updateSubscription(msg models.WsClientMessage) {
+	subscribe := msg.Type == models.Subscribe
 
-	// RoomStateをRoomWithParticipantsの形式に変換
-	rooms := h.repo.RoomState
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	// 全ルームの状態をJSONにシリアライズ
-	roomStateJSON, err := json.Marshal(rooms)
-	if err != nil {
-		fmt.Printf("Failed to marshal room state: %v", err)
-		return
+	if msg.Global != nil && *msg.Global {
+		c.global = subscribe
 	}
-
-	// 全クライアントに送信
-	for client := range h.Clients {
-		if err := client.WriteMessage(websocket.TextMessage, roomStateJSON); err != nil {
-			fmt.Printf("Failed to send message to WebSocket client: %v", err)
-			client.Close()
-			delete(h.Clients, client)
+	if msg.RoomIds != nil {
+		for _, roomID := range *msg.RoomIds {
+			if subscribe {
+				c.rooms[roomID] = true
+			} else {
+				delete(c.rooms, roomID)
+			}
 		}
 	}
 }
 
-func (h *Handler) broadcastRoomStateToSingleClient(client *websocket.Conn) error {
-	h.Mutex.Lock()
-	defer h.Mutex.Unlock()
-
-	// RoomStateをRoomWithParticipantsの形式に変換
-	rooms := h.repo.RoomState
+// WebSocket用のアップグレーダ
+var upgrader = websocket.Upgrader{
+	CheckOrigin:  checkOrigin,
+	Subprotocols: []string{wsSubprotocol},
+}
 
-	// 全ルームの状態をJSONにシリアライズ
-	roomStateJSON, err := json.Marshal(rooms)
-	if err != nil {
-		fmt.Printf("Failed to marshal room state: %v", err)
-		return err
+// checkOrigin は CORS と同じ許可リストでオリジンを検証する。
+// Origin ヘッダの無いリクエスト (ブラウザ以外のクライアント) は許可する。
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
 	}
-
-	// 全クライアントに送信
-	if err := client.WriteMessage(websocket.TextMessage, roomStateJSON); err != nil {
-		fmt.Printf("Failed to send message to WebSocket client: %v", err)
-		return err
+	for _, allowed := range config.AllowedOrigins() {
+		if matchOrigin(allowed, origin) {
+			return true
+		}
 	}
-
-	return nil
+	return false
 }
 
-// WebSocket用のアップグレーダ
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // 全リクエスト許可 (必要に応じて制限)
-	},
+// matchOrigin は "https://*.trap.jp" のようなワイルドカードを含むパターンとオリジンを比較する
+func matchOrigin(pattern, origin string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok {
+		return false
+	}
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
 }
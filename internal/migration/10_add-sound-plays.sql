@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS sound_plays
+(
+    play_id   BIGINT       NOT NULL AUTO_INCREMENT,
+    sound_id  VARCHAR(36)  NOT NULL,
+    room_id   VARCHAR(36)  NOT NULL,
+    user_id   VARCHAR(255) NOT NULL,
+    played_at DATETIME(3)  NOT NULL,
+    PRIMARY KEY (play_id),
+    -- ランキング (期間内の集計) 用。集計に使う列を全て含めてテーブルを読まずに済ませる
+    INDEX idx_sound_plays_played_at (played_at, sound_id, user_id, room_id),
+    -- サウンドごとの統計用
+    INDEX idx_sound_plays_sound_id_played_at (sound_id, played_at, room_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sound_plays;
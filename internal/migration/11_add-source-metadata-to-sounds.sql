@@ -0,0 +1,20 @@
+-- +goose Up
+-- アップロードされた元のファイルの情報。これより前に登録されたサウンドは content_hash が NULL
+ALTER TABLE sounds
+    ADD COLUMN content_hash       CHAR(64),
+    ADD COLUMN size_bytes         BIGINT      NOT NULL DEFAULT 0,
+    ADD COLUMN codec              VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN sample_rate        INT         NOT NULL DEFAULT 0,
+    ADD COLUMN channels           INT         NOT NULL DEFAULT 0,
+    ADD COLUMN source_duration_ms INT         NOT NULL DEFAULT 0,
+    ADD UNIQUE INDEX uq_sounds_content_hash (content_hash);
+
+-- +goose Down
+ALTER TABLE sounds
+    DROP INDEX uq_sounds_content_hash,
+    DROP COLUMN source_duration_ms,
+    DROP COLUMN channels,
+    DROP COLUMN sample_rate,
+    DROP COLUMN codec,
+    DROP COLUMN size_bytes,
+    DROP COLUMN content_hash;
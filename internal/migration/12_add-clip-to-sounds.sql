@@ -0,0 +1,21 @@
+-- +goose Up
+-- アップロード時に指定された切り出し範囲 (clip_end_ms が 0 の場合は最後まで) とフェード、加工後の波形のプレビュー。
+-- 同じファイルでも切り出し方が違えば別のサウンドとして登録できるよう、一意制約に含める
+ALTER TABLE sounds
+    ADD COLUMN clip_start_ms INT NOT NULL DEFAULT 0,
+    ADD COLUMN clip_end_ms   INT NOT NULL DEFAULT 0,
+    ADD COLUMN fade_in_ms    INT NOT NULL DEFAULT 0,
+    ADD COLUMN fade_out_ms   INT NOT NULL DEFAULT 0,
+    ADD COLUMN waveform      VARBINARY(255),
+    DROP INDEX uq_sounds_content_hash,
+    ADD UNIQUE INDEX uq_sounds_content_hash_clip (content_hash, clip_start_ms, clip_end_ms, fade_in_ms, fade_out_ms);
+
+-- +goose Down
+ALTER TABLE sounds
+    DROP INDEX uq_sounds_content_hash_clip,
+    ADD UNIQUE INDEX uq_sounds_content_hash (content_hash),
+    DROP COLUMN waveform,
+    DROP COLUMN fade_out_ms,
+    DROP COLUMN fade_in_ms,
+    DROP COLUMN clip_end_ms,
+    DROP COLUMN clip_start_ms;
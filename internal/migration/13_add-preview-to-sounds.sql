@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sounds
+    ADD COLUMN preview_key VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE sounds
+    DROP COLUMN preview_key;
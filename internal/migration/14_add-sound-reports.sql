@@ -0,0 +1,24 @@
+-- +goose Up
+-- hidden_at は通報が一定数に達して非表示になった日時。NULL の場合は表示する
+ALTER TABLE sounds
+    ADD COLUMN hidden_at DATETIME NULL;
+
+CREATE TABLE IF NOT EXISTS sound_reports
+(
+    sound_id    VARCHAR(36)  NOT NULL,
+    reporter_id VARCHAR(255) NOT NULL,
+    reason      VARCHAR(500) NOT NULL DEFAULT '',
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- resolved_at は管理者が問題なしと判断した日時。NULL のものが未対応の通報
+    resolved_at DATETIME     NULL,
+    -- 同じユーザは同じサウンドを1回だけ通報できる (問題なしとされた後は再度通報できる)
+    PRIMARY KEY (sound_id, reporter_id),
+    -- 確認待ちの一覧用
+    INDEX idx_sound_reports_resolved_at (resolved_at, sound_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sound_reports;
+
+ALTER TABLE sounds
+    DROP COLUMN hidden_at;
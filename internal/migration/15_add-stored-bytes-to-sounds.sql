@@ -0,0 +1,8 @@
+-- +goose Up
+-- stored_bytes は加工後のファイル・元のファイル・試聴用のファイルの合計サイズ。0 は記録する前に登録されたサウンド
+ALTER TABLE sounds
+    ADD COLUMN stored_bytes BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE sounds
+    DROP COLUMN stored_bytes;
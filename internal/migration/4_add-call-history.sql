@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS room_sessions
+(
+    session_id        VARCHAR(36) NOT NULL,
+    room_id           VARCHAR(36) NOT NULL,
+    started_at        DATETIME(3) NOT NULL,
+    ended_at          DATETIME(3),
+    is_webinar        BOOLEAN     NOT NULL DEFAULT FALSE,
+    peak_participants INT         NOT NULL DEFAULT 0,
+    PRIMARY KEY (session_id),
+    INDEX idx_room_sessions_room_id_started_at (room_id, started_at)
+);
+
+CREATE TABLE IF NOT EXISTS participant_stints
+(
+    stint_id   VARCHAR(36)  NOT NULL,
+    session_id VARCHAR(36)  NOT NULL,
+    room_id    VARCHAR(36)  NOT NULL,
+    user_id    VARCHAR(255) NOT NULL,
+    identity   VARCHAR(255) NOT NULL,
+    joined_at  DATETIME(3)  NOT NULL,
+    left_at    DATETIME(3),
+    PRIMARY KEY (stint_id),
+    INDEX idx_participant_stints_session_id_identity (session_id, identity),
+    INDEX idx_participant_stints_user_id_joined_at (user_id, joined_at)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS participant_stints;
+DROP TABLE IF EXISTS room_sessions;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhook_events
+(
+    event_id    VARCHAR(64)  NOT NULL,
+    event       VARCHAR(64)  NOT NULL,
+    room_name   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  DATETIME     NOT NULL,
+    received_at DATETIME(3)  NOT NULL,
+    payload     MEDIUMTEXT   NOT NULL,
+    PRIMARY KEY (event_id),
+    INDEX idx_webhook_events_created_at (created_at),
+    INDEX idx_webhook_events_room_name_created_at (room_name, created_at)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS webhook_events;
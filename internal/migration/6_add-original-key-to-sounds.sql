@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sounds
+    ADD COLUMN original_key VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE sounds
+    DROP COLUMN original_key;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS sound_uploads
+(
+    upload_id    VARCHAR(36)  NOT NULL,
+    creator_id   VARCHAR(255) NOT NULL,
+    sound_name   VARCHAR(255) NOT NULL,
+    stamp_id     VARCHAR(255) NOT NULL,
+    object_key   VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size         BIGINT       NOT NULL,
+    status       VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    created_at   DATETIME(3)  NOT NULL,
+    expires_at   DATETIME(3)  NOT NULL,
+    PRIMARY KEY (upload_id),
+    INDEX idx_sound_uploads_status_expires_at (status, expires_at)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS sound_uploads;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sounds
+    ADD COLUMN duration_ms INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE sounds
+    DROP COLUMN duration_ms;
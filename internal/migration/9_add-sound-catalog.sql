@@ -0,0 +1,57 @@
+-- +goose Up
+ALTER TABLE sounds
+    ADD INDEX idx_sounds_created_at_sound_id (created_at, sound_id),
+    ADD FULLTEXT INDEX ft_sounds_sound_name (sound_name);
+
+CREATE TABLE IF NOT EXISTS sound_tags
+(
+    sound_id VARCHAR(36) NOT NULL,
+    tag      VARCHAR(64) NOT NULL,
+    PRIMARY KEY (sound_id, tag),
+    INDEX idx_sound_tags_tag (tag)
+);
+
+CREATE TABLE IF NOT EXISTS sound_favorites
+(
+    user_id    VARCHAR(255) NOT NULL,
+    sound_id   VARCHAR(36)  NOT NULL,
+    created_at DATETIME(3)  NOT NULL,
+    PRIMARY KEY (user_id, sound_id),
+    INDEX idx_sound_favorites_sound_id (sound_id)
+);
+
+CREATE TABLE IF NOT EXISTS sound_packs
+(
+    pack_id     VARCHAR(36)  NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT         NOT NULL,
+    creator_id  VARCHAR(255) NOT NULL,
+    created_at  DATETIME(3)  NOT NULL,
+    PRIMARY KEY (pack_id)
+);
+
+CREATE TABLE IF NOT EXISTS sound_pack_items
+(
+    pack_id  VARCHAR(36) NOT NULL,
+    sound_id VARCHAR(36) NOT NULL,
+    PRIMARY KEY (pack_id, sound_id),
+    INDEX idx_sound_pack_items_sound_id (sound_id)
+);
+
+CREATE TABLE IF NOT EXISTS channel_sound_packs
+(
+    channel_id VARCHAR(36) NOT NULL,
+    pack_id    VARCHAR(36) NOT NULL,
+    PRIMARY KEY (channel_id, pack_id),
+    INDEX idx_channel_sound_packs_pack_id (pack_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS channel_sound_packs;
+DROP TABLE IF EXISTS sound_pack_items;
+DROP TABLE IF EXISTS sound_packs;
+DROP TABLE IF EXISTS sound_favorites;
+DROP TABLE IF EXISTS sound_tags;
+ALTER TABLE sounds
+    DROP INDEX ft_sounds_sound_name,
+    DROP INDEX idx_sounds_created_at_sound_id;
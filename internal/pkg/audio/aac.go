@@ -0,0 +1,130 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAACUnavailable は AAC のデコーダ (libfaad) を使わずにビルドされている時に返す
+var ErrAACUnavailable = errors.New("aac decoder is not available (build with -tags aac)")
+
+// newAACDecoder はテストでデコーダを差し替えられるようにするための変数
+var newAACDecoder = NewAACDecoder
+
+// AACDecoder は MP4 に格納された AAC のフレーム (ADTS ヘッダ無し) を PCM にデコードする
+type AACDecoder interface {
+	// Decode は1フレームをデコードし、インターリーブされた 16bit の PCM を返す
+	Decode(frame []byte) ([]int16, error)
+	// SampleRate, Channels はデコード結果のサンプリングレート・チャンネル数 (HE-AAC では設定と異なることがある)
+	SampleRate() int
+	Channels() int
+	Close()
+}
+
+// aacSampleRates は AudioSpecificConfig の samplingFrequencyIndex に対応するサンプリングレート
+var aacSampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// aacConfig は MP4 の esds に入っている AudioSpecificConfig
+type aacConfig struct {
+	raw        []byte
+	objectType int
+	sampleRate int
+	channels   int
+}
+
+// parseAudioSpecificConfig は AudioSpecificConfig (ISO/IEC 14496-3 1.6.2.1) の先頭を読む
+func parseAudioSpecificConfig(data []byte) (aacConfig, error) {
+	r := &bitReader{data: data}
+	config := aacConfig{raw: data}
+
+	readObjectType := func() int {
+		objectType := r.read(5)
+		if objectType == 31 {
+			objectType = 32 + r.read(6)
+		}
+		return objectType
+	}
+	readSampleRate := func() int {
+		index := r.read(4)
+		if index == 0x0F {
+			return r.read(24)
+		}
+		if index < len(aacSampleRates) {
+			return aacSampleRates[index]
+		}
+		return 0
+	}
+
+	config.objectType = readObjectType()
+	config.sampleRate = readSampleRate()
+	channelConfig := r.read(4)
+	// SBR (HE-AAC), PS (HE-AAC v2) が明示されている場合は出力のサンプリングレートが続く
+	if config.objectType == 5 || config.objectType == 29 {
+		config.sampleRate = readSampleRate()
+		if config.objectType == 29 && channelConfig == 1 {
+			channelConfig = 2
+		}
+		config.objectType = readObjectType()
+	}
+	if r.err != nil {
+		return aacConfig{}, fmt.Errorf("invalid AudioSpecificConfig: %w", r.err)
+	}
+
+	switch {
+	case channelConfig >= 1 && channelConfig <= 6:
+		config.channels = channelConfig
+	case channelConfig == 7:
+		config.channels = 8
+	default:
+		// 0 はプログラムコンフィグ要素で指定される。デコーダに任せる
+		config.channels = 0
+	}
+	if config.sampleRate == 0 {
+		return aacConfig{}, errors.New("invalid AAC sampling frequency")
+	}
+	return config, nil
+}
+
+// decodeAACFrames は AAC のフレーム列をデコードする。
+// エンコーダが先頭に入れる無音 (priming) は取り除かないが、加工時に無音として取り除かれる。
+func decodeAACFrames(frames [][]byte, config aacConfig) (*Clip, error) {
+	dec, err := newAACDecoder(config.raw)
+	if err != nil {
+		return nil, err
+	}
+	defer dec.Close()
+
+	var pcm []int16
+	for _, frame := range frames {
+		out, err := dec.Decode(frame)
+		if err != nil {
+			return nil, fmt.Errorf("aac decode error: %w", err)
+		}
+		pcm = append(pcm, out...)
+	}
+	if dec.SampleRate() <= 0 || dec.Channels() <= 0 {
+		return nil, errors.New("aac decoder returned no audio")
+	}
+	return interleavedClip(pcm, dec.SampleRate(), dec.Channels()), nil
+}
+
+// bitReader は MSB から順にビットを読む。データが足りない場合は err を設定して 0 を返す
+type bitReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *bitReader) read(n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		if r.pos >= len(r.data)*8 {
+			r.err = errors.New("unexpected end of data")
+			return 0
+		}
+		bit := (r.data[r.pos/8] >> (7 - r.pos%8)) & 1
+		v = v<<1 | int(bit)
+		r.pos++
+	}
+	return v
+}
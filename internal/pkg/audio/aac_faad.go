@@ -0,0 +1,89 @@
+//go:build aac
+
+package audio
+
+/*
+#cgo LDFLAGS: -lfaad
+#include <stdlib.h>
+#include <neaacdec.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// faadDecoder は libfaad (FAAD2) による AAC のデコーダ
+type faadDecoder struct {
+	handle     C.NeAACDecHandle
+	sampleRate int
+	channels   int
+}
+
+// AACAvailable は AAC のデコーダを使えるかを返す
+func AACAvailable() bool {
+	return true
+}
+
+// NewAACDecoder は AudioSpecificConfig (MP4 の esds の DecoderSpecificInfo) から libfaad のデコーダを作る
+func NewAACDecoder(config []byte) (AACDecoder, error) {
+	if len(config) == 0 {
+		return nil, errors.New("missing AudioSpecificConfig")
+	}
+	handle := C.NeAACDecOpen()
+	if handle == nil {
+		return nil, errors.New("failed to open aac decoder")
+	}
+
+	// 16bit で出力し、5.1ch 等はステレオにダウンミックスする
+	conf := C.NeAACDecGetCurrentConfiguration(handle)
+	conf.outputFormat = C.uchar(C.FAAD_FMT_16BIT)
+	conf.downMatrix = 1
+	C.NeAACDecSetConfiguration(handle, conf)
+
+	cConfig := C.CBytes(config)
+	defer C.free(cConfig)
+	var (
+		sampleRate C.ulong
+		channels   C.uchar
+	)
+	if C.NeAACDecInit2(handle, (*C.uchar)(cConfig), C.ulong(len(config)), &sampleRate, &channels) < 0 {
+		C.NeAACDecClose(handle)
+		return nil, errors.New("invalid AudioSpecificConfig")
+	}
+	return &faadDecoder{handle: handle, sampleRate: int(sampleRate), channels: int(channels)}, nil
+}
+
+func (d *faadDecoder) Decode(frame []byte) ([]int16, error) {
+	if len(frame) == 0 {
+		return nil, nil
+	}
+	cFrame := C.CBytes(frame)
+	defer C.free(cFrame)
+
+	var info C.NeAACDecFrameInfo
+	out := C.NeAACDecDecode(d.handle, &info, (*C.uchar)(cFrame), C.ulong(len(frame)))
+	if info.error != 0 {
+		return nil, fmt.Errorf("%s", C.GoString(C.NeAACDecGetErrorMessage(info.error)))
+	}
+	if out == nil || info.samples == 0 {
+		return nil, nil
+	}
+	d.sampleRate = int(info.samplerate)
+	d.channels = int(info.channels)
+	return append([]int16(nil), unsafe.Slice((*int16)(out), int(info.samples))...), nil
+}
+
+func (d *faadDecoder) SampleRate() int {
+	return d.sampleRate
+}
+
+func (d *faadDecoder) Channels() int {
+	return d.channels
+}
+
+func (d *faadDecoder) Close() {
+	C.NeAACDecClose(d.handle)
+}
@@ -0,0 +1,13 @@
+//go:build !aac
+
+package audio
+
+// AACAvailable は AAC のデコーダを使えるかを返す
+func AACAvailable() bool {
+	return false
+}
+
+// NewAACDecoder は -tags aac を付けずにビルドした場合は常に ErrAACUnavailable を返す
+func NewAACDecoder([]byte) (AACDecoder, error) {
+	return nil, ErrAACUnavailable
+}
@@ -0,0 +1,245 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/wav"
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/jfreymuth/oggvorbis"
+	"github.com/mewkiz/flac"
+)
+
+// ErrUnsupportedFormat は対応していない形式のファイルが渡された時に返す
+var ErrUnsupportedFormat = errors.New("we only support mp3, wav, ogg (vorbis / opus), flac, m4a (aac / opus) and webm (opus)")
+
+// Clip はデコード済みの音声。Samples はチャンネルごとの -1.0〜1.0 のサンプル列。
+type Clip struct {
+	SampleRate int
+	Samples    [][]float64
+}
+
+// Channels はチャンネル数を返す
+func (c *Clip) Channels() int {
+	return len(c.Samples)
+}
+
+// Frames はチャンネルあたりのサンプル数を返す
+func (c *Clip) Frames() int {
+	if len(c.Samples) == 0 {
+		return 0
+	}
+	return len(c.Samples[0])
+}
+
+// Duration は長さを秒で返す
+func (c *Clip) Duration() float64 {
+	if c.SampleRate <= 0 {
+		return 0
+	}
+	return float64(c.Frames()) / float64(c.SampleRate)
+}
+
+// Decode はファイルの中身から形式を判定し、対応するデコーダで音声をデコードする。
+// Opus と AAC のデコードにはそれぞれ -tags opus / -tags aac を付けたビルドが必要。
+func Decode(data []byte) (*Clip, error) {
+	var (
+		clip *Clip
+		err  error
+	)
+	format := DetectFormat(data)
+	switch format {
+	case FormatMP3:
+		clip, err = decodeMp3(data)
+	case FormatWAV:
+		clip, err = decodeWav(data)
+	case FormatOgg:
+		clip, err = decodeOgg(data)
+	case FormatFLAC:
+		clip, err = decodeFlac(data)
+	case FormatMP4:
+		clip, err = decodeMP4(data)
+	case FormatWebM:
+		clip, err = decodeWebM(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s decode error: %w", format, err)
+	}
+	return clip, nil
+}
+
+// decodeMp3 は MP3 をデコードする。go-mp3 は常に 16bit ステレオ (リトルエンディアン) を出力する。
+func decodeMp3(data []byte) (*Clip, error) {
+	decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if decoder.SampleRate() <= 0 {
+		return nil, errors.New("invalid mp3 sample rate")
+	}
+	pcm, err := io.ReadAll(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := len(pcm) / 4
+	left := make([]float64, frames)
+	right := make([]float64, frames)
+	for i := 0; i < frames; i++ {
+		left[i] = float64(int16(uint16(pcm[4*i])|uint16(pcm[4*i+1])<<8)) / 32768
+		right[i] = float64(int16(uint16(pcm[4*i+2])|uint16(pcm[4*i+3])<<8)) / 32768
+	}
+	return &Clip{SampleRate: decoder.SampleRate(), Samples: [][]float64{left, right}}, nil
+}
+
+// decodeWav は PCM の WAV をデコードする
+func decodeWav(data []byte) (*Clip, error) {
+	decoder := wav.NewDecoder(bytes.NewReader(data))
+	buf, err := decoder.FullPCMBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if buf == nil || buf.Format == nil || buf.Format.NumChannels <= 0 {
+		return nil, errors.New("invalid wav format or buffer")
+	}
+	if buf.Format.SampleRate <= 0 {
+		return nil, errors.New("invalid wav sample rate")
+	}
+
+	channels := buf.Format.NumChannels
+	bitDepth := buf.SourceBitDepth
+	if bitDepth <= 0 {
+		bitDepth = int(decoder.BitDepth)
+	}
+	scale := float64(int64(1) << (bitDepth - 1))
+	frames := len(buf.Data) / channels
+	samples := make([][]float64, channels)
+	for ch := range samples {
+		samples[ch] = make([]float64, frames)
+		for i := 0; i < frames; i++ {
+			v := float64(buf.Data[i*channels+ch])
+			if bitDepth == 8 {
+				// 8bit の WAV は符号なし
+				v -= 128
+			}
+			samples[ch][i] = v / scale
+		}
+	}
+	return &Clip{SampleRate: buf.Format.SampleRate, Samples: samples}, nil
+}
+
+// decodeOgg は Ogg をデコードする。中身が Vorbis か Opus かは最初のパケットで判定する。
+func decodeOgg(data []byte) (*Clip, error) {
+	stream, err := readOgg(data)
+	if err != nil {
+		return nil, err
+	}
+	switch stream.codec {
+	case CodecVorbis:
+		return decodeOggVorbis(data)
+	case CodecOpus:
+		return decodeOpusPackets(stream.packets[2:], stream.opus, stream.lastGranule-int64(stream.opus.preSkip))
+	default:
+		return nil, fmt.Errorf("unsupported ogg codec: %s", stream.codec)
+	}
+}
+
+// decodeOggVorbis は Ogg Vorbis をデコードする
+func decodeOggVorbis(data []byte) (*Clip, error) {
+	pcm, format, err := oggvorbis.ReadAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format.SampleRate <= 0 || format.Channels <= 0 {
+		return nil, errors.New("invalid ogg sample rate")
+	}
+
+	channels := format.Channels
+	frames := len(pcm) / channels
+	samples := make([][]float64, channels)
+	for ch := range samples {
+		samples[ch] = make([]float64, frames)
+		for i := 0; i < frames; i++ {
+			samples[ch][i] = float64(pcm[i*channels+ch])
+		}
+	}
+	return &Clip{SampleRate: format.SampleRate, Samples: samples}, nil
+}
+
+// decodeFlac は FLAC をデコードする
+func decodeFlac(data []byte) (*Clip, error) {
+	stream, err := flac.New(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	info := stream.Info
+	if info.SampleRate == 0 || info.NChannels == 0 || info.BitsPerSample == 0 {
+		return nil, errors.New("invalid flac stream info")
+	}
+
+	scale := float64(int64(1) << (info.BitsPerSample - 1))
+	samples := make([][]float64, info.NChannels)
+	for ch := range samples {
+		samples[ch] = make([]float64, 0, info.NSamples)
+	}
+	for {
+		frame, err := stream.ParseNext()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(frame.Subframes) != len(samples) {
+			return nil, errors.New("flac channel count changed mid-stream")
+		}
+		for ch, sub := range frame.Subframes {
+			for _, v := range sub.Samples[:sub.NSamples] {
+				samples[ch] = append(samples[ch], float64(v)/scale)
+			}
+		}
+	}
+	return &Clip{SampleRate: int(info.SampleRate), Samples: samples}, nil
+}
+
+// decodeMP4 は MP4 (m4a) の最初の音声トラックをデコードする
+func decodeMP4(data []byte) (*Clip, error) {
+	track, err := readMP4(data)
+	if err != nil {
+		return nil, err
+	}
+	switch track.codec {
+	case CodecAAC:
+		return decodeAACFrames(track.samples, track.aacConfig)
+	case CodecOpus:
+		return decodeOpusPackets(track.samples, track.opus, -1)
+	default:
+		return nil, fmt.Errorf("unsupported mp4 codec: %s", track.codec)
+	}
+}
+
+// decodeWebM は WebM (Matroska) の最初の音声トラックをデコードする
+func decodeWebM(data []byte) (*Clip, error) {
+	track, err := readWebM(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOpusPackets(track.packets, track.opus, track.totalSamples)
+}
+
+// interleavedClip はインターリーブされた 16bit の PCM を Clip にする
+func interleavedClip(pcm []int16, sampleRate, channels int) *Clip {
+	frames := len(pcm) / channels
+	samples := make([][]float64, channels)
+	for ch := range samples {
+		samples[ch] = make([]float64, frames)
+		for i := 0; i < frames; i++ {
+			samples[ch][i] = float64(pcm[i*channels+ch]) / 32768
+		}
+	}
+	return &Clip{SampleRate: sampleRate, Samples: samples}
+}
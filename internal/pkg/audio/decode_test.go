@@ -0,0 +1,302 @@
+package audio
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//go:generate go run testdata/gen.go
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fakeOpusDecoder はパケットの長さ分のサンプルに、ストリームの先頭からの位置を書き込む
+type fakeOpusDecoder struct {
+	channels int
+	pos      int
+}
+
+func (d *fakeOpusDecoder) Decode(data []byte, pcm []int16) (int, error) {
+	n, err := opusPacketSamples(data)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < n; i++ {
+		for ch := 0; ch < d.channels; ch++ {
+			pcm[i*d.channels+ch] = int16(d.pos)
+		}
+		d.pos++
+	}
+	return n, nil
+}
+
+// fakeAACDecoder は1フレームを 1024 サンプルとし、全てのサンプルにフレームの先頭のバイト + 1 を書き込む
+type fakeAACDecoder struct {
+	config aacConfig
+}
+
+func (d *fakeAACDecoder) Decode(frame []byte) ([]int16, error) {
+	pcm := make([]int16, 1024*d.config.channels)
+	for i := range pcm {
+		pcm[i] = int16(frame[0]) + 1
+	}
+	return pcm, nil
+}
+
+func (d *fakeAACDecoder) SampleRate() int { return d.config.sampleRate }
+func (d *fakeAACDecoder) Channels() int   { return d.config.channels }
+func (d *fakeAACDecoder) Close()          {}
+
+// useFakeDecoders は Opus と AAC のデコーダをテスト中だけ差し替える
+func useFakeDecoders(t *testing.T) {
+	t.Helper()
+	opus, aac := newOpusDecoder, newAACDecoder
+	t.Cleanup(func() {
+		newOpusDecoder, newAACDecoder = opus, aac
+	})
+	newOpusDecoder = func(sampleRate, channels int) (OpusDecoder, error) {
+		if sampleRate != opusSampleRate {
+			t.Errorf("unexpected opus sample rate: %d", sampleRate)
+		}
+		return &fakeOpusDecoder{channels: channels}, nil
+	}
+	newAACDecoder = func(raw []byte) (AACDecoder, error) {
+		config, err := parseAudioSpecificConfig(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &fakeAACDecoder{config: config}, nil
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	id3 := []byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 2, 0, 0}
+	tests := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{"wav", readFixture(t, "tone.wav"), FormatWAV},
+		{"flac", readFixture(t, "tone.flac"), FormatFLAC},
+		{"ogg opus", readFixture(t, "silence.opus"), FormatOgg},
+		{"webm", readFixture(t, "silence.webm"), FormatWebM},
+		{"m4a", readFixture(t, "aac.m4a"), FormatMP4},
+		{"fragmented m4a", readFixture(t, "fragmented.m4a"), FormatMP4},
+		{"mp3 frame", []byte{0xFF, 0xFB, 0x90, 0x64}, FormatMP3},
+		{"mp3 with id3", append(append([]byte{}, id3...), 0xFF, 0xFB, 0x90, 0x64), FormatMP3},
+		{"flac with id3", append(append([]byte{}, id3...), readFixture(t, "tone.flac")...), FormatFLAC},
+		{"adts", []byte{0xFF, 0xF1, 0x50, 0x80}, ""},
+		{"text", []byte("hello, world"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.data); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatExt(t *testing.T) {
+	tests := map[Format]string{
+		FormatMP3:  ".mp3",
+		FormatWAV:  ".wav",
+		FormatOgg:  ".ogg",
+		FormatFLAC: ".flac",
+		FormatMP4:  ".m4a",
+		FormatWebM: ".webm",
+		"":         "",
+	}
+	for format, want := range tests {
+		if got := format.Ext(); got != want {
+			t.Errorf("Format(%q).Ext() = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		file       string
+		format     Format
+		codec      string
+		sampleRate int
+		channels   int
+		duration   float64
+	}{
+		{"tone.wav", FormatWAV, CodecPCM, 8000, 1, 0.25},
+		{"tone.flac", FormatFLAC, CodecFLAC, 8000, 1, 0.25},
+		// 25 パケット (0.5秒) から最後のグラニュール位置で 20ms 切り詰めている
+		{"silence.opus", FormatOgg, CodecOpus, 48000, 1, 0.48},
+		// 25 パケットから pre-skip (312) と DiscardPadding (10ms) を除く
+		{"silence.webm", FormatWebM, CodecOpus, 48000, 1, float64(25*960-312-480) / 48000},
+		{"aac.m4a", FormatMP4, CodecAAC, 44100, 1, float64(10*1024) / 44100},
+		{"fragmented.m4a", FormatMP4, CodecAAC, 44100, 1, float64(10*1024) / 44100},
+		{"opus.mp4", FormatMP4, CodecOpus, 48000, 1, 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			info, err := Probe(readFixture(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Format != tt.format || info.Codec != tt.codec || info.SampleRate != tt.sampleRate || info.Channels != tt.channels {
+				t.Errorf("Probe() = %+v, want %s/%s %d Hz %d ch", info, tt.format, tt.codec, tt.sampleRate, tt.channels)
+			}
+			if math.Abs(info.Duration-tt.duration) > 1e-6 {
+				t.Errorf("duration = %f, want %f", info.Duration, tt.duration)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	useFakeDecoders(t)
+	tests := []struct {
+		file       string
+		sampleRate int
+		channels   int
+		frames     int
+		// first, last は最初と最後のサンプル (16bit の値)。-1 の場合は確認しない
+		first, last int
+	}{
+		{"tone.wav", 8000, 1, 2000, -1, -1},
+		{"tone.flac", 8000, 1, 2000, -1, -1},
+		// Opus は先頭の pre-skip (312 サンプル) を捨て、コンテナに書かれた長さで末尾を切り詰める
+		{"silence.opus", 48000, 1, 23040, 312, 312 + 23040 - 1},
+		{"silence.webm", 48000, 1, 23208, 312, 312 + 23208 - 1},
+		{"opus.mp4", 48000, 1, 10*960 - 312, 312, 10*960 - 1},
+		// AAC のフレームはチャンク・フラグメントをまたいで順番通りに取り出す
+		{"aac.m4a", 44100, 1, 10 * 1024, 1, 10},
+		{"fragmented.m4a", 44100, 1, 10 * 1024, 1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			clip, err := Decode(readFixture(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if clip.SampleRate != tt.sampleRate || clip.Channels() != tt.channels || clip.Frames() != tt.frames {
+				t.Fatalf("Decode() = %d Hz %d ch %d frames, want %d Hz %d ch %d frames",
+					clip.SampleRate, clip.Channels(), clip.Frames(), tt.sampleRate, tt.channels, tt.frames)
+			}
+			if tt.first >= 0 {
+				samples := clip.Samples[0]
+				first, last := int(samples[0]*32768), int(samples[len(samples)-1]*32768)
+				if first != tt.first || last != tt.last {
+					t.Errorf("first, last = %d, %d, want %d, %d", first, last, tt.first, tt.last)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeFLACMatchesWAV(t *testing.T) {
+	flac, err := Decode(readFixture(t, "tone.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wav, err := Decode(readFixture(t, "tone.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range wav.Samples[0] {
+		if flac.Samples[0][i] != wav.Samples[0][i] {
+			t.Fatalf("sample %d = %f, want %f", i, flac.Samples[0][i], wav.Samples[0][i])
+		}
+	}
+}
+
+// TestDecodeWithCodec は実際のデコーダ (-tags opus,nolibopusfile,aac) で無音のファイルをデコードする。
+// デコーダが無いビルドではスキップする (make test-codecs で実行する)
+func TestDecodeWithCodec(t *testing.T) {
+	tests := []struct {
+		file       string
+		available  func() bool
+		sampleRate int
+		// minFrames, maxFrames はデコード結果のフレーム数の範囲 (AAC はデコーダによって先頭の遅延の扱いが異なる)
+		minFrames, maxFrames int
+	}{
+		{"silence.opus", OpusAvailable, 48000, 23040, 23040},
+		{"silence.webm", OpusAvailable, 48000, 23208, 23208},
+		{"opus.mp4", OpusAvailable, 48000, 10*960 - 312, 10*960 - 312},
+		{"silence.m4a", AACAvailable, 44100, 9 * 1024, 10 * 1024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if !tt.available() {
+				t.Skip("codec is not available (build with -tags opus,nolibopusfile,aac)")
+			}
+			clip, err := Decode(readFixture(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if clip.SampleRate != tt.sampleRate || clip.Channels() < 1 || clip.Frames() < tt.minFrames || clip.Frames() > tt.maxFrames {
+				t.Fatalf("Decode() = %d Hz %d ch %d frames, want %d Hz %d〜%d frames",
+					clip.SampleRate, clip.Channels(), clip.Frames(), tt.sampleRate, tt.minFrames, tt.maxFrames)
+			}
+			if peak := samplePeak(clip); peak > 1e-3 {
+				t.Errorf("peak = %f, want silence", peak)
+			}
+		})
+	}
+}
+
+func TestDecodeWithoutCodec(t *testing.T) {
+	tests := []struct {
+		file      string
+		available func() bool
+		want      error
+	}{
+		{"silence.opus", OpusAvailable, ErrOpusUnavailable},
+		{"silence.webm", OpusAvailable, ErrOpusUnavailable},
+		{"aac.m4a", AACAvailable, ErrAACUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if tt.available() {
+				t.Skip("codec is available")
+			}
+			if _, err := Decode(readFixture(t, tt.file)); !errors.Is(err, tt.want) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	useFakeDecoders(t)
+	truncate := func(name string) []byte {
+		data := readFixture(t, name)
+		return data[:len(data)*2/3]
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"unknown", []byte("hello, world")},
+		{"truncated ogg", truncate("silence.opus")},
+		{"truncated webm", truncate("silence.webm")},
+		{"truncated m4a", truncate("aac.m4a")},
+		{"truncated fragmented m4a", truncate("fragmented.m4a")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.data); err == nil {
+				t.Error("Decode() succeeded, want error")
+			}
+			if _, err := Probe(tt.data); err == nil {
+				t.Error("Probe() succeeded, want error")
+			}
+		})
+	}
+}
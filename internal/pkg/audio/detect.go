@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"bytes"
+)
+
+// Format はファイル (コンテナ) の形式
+type Format string
+
+const (
+	FormatMP3  Format = "mp3"
+	FormatWAV  Format = "wav"
+	FormatOgg  Format = "ogg"
+	FormatFLAC Format = "flac"
+	FormatMP4  Format = "mp4"
+	FormatWebM Format = "webm"
+)
+
+// コーデック名 (Info.Codec)
+const (
+	CodecMP3    = "mp3"
+	CodecPCM    = "pcm"
+	CodecVorbis = "vorbis"
+	CodecOpus   = "opus"
+	CodecFLAC   = "flac"
+	CodecAAC    = "aac"
+)
+
+// Ext はファイルを保存する時に付ける拡張子を返す (MP4 は音声のみなので .m4a)。形式が分からない場合は空文字列を返す
+func (f Format) Ext() string {
+	switch f {
+	case "":
+		return ""
+	case FormatMP4:
+		return ".m4a"
+	default:
+		return "." + string(f)
+	}
+}
+
+// DetectFormat はファイルの先頭のバイト列 (マジックナンバー) から形式を判定する。判定できない場合は空文字列を返す。
+// 拡張子や Content-Type はブラウザやクライアントによってまちまちなので使わない。
+func DetectFormat(data []byte) Format {
+	switch {
+	case len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && string(data[8:12]) == "WAVE":
+		return FormatWAV
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return FormatOgg
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return FormatFLAC
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		return FormatMP4
+	case bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// EBML ヘッダ。DocType が webm でも matroska でも同じように読む
+		return FormatWebM
+	case bytes.HasPrefix(data, []byte("ID3")):
+		// ID3v2 タグの後ろに何が続くかを見る (FLAC にも付いていることがある)
+		if size := id3v2Size(data); size > 0 && size < len(data) {
+			if DetectFormat(data[size:]) == FormatFLAC {
+				return FormatFLAC
+			}
+		}
+		return FormatMP3
+	case isMP3FrameSync(data):
+		return FormatMP3
+	default:
+		return ""
+	}
+}
+
+// isMP3FrameSync は MPEG オーディオのフレームヘッダで始まっているかを返す。
+// ADTS (AAC) も同じ同期ワードで始まるが、layer が 0 なので区別できる。
+func isMP3FrameSync(data []byte) bool {
+	return len(data) >= 4 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && (data[1]>>1)&0x03 != 0
+}
+
+// id3v2Size は先頭の ID3v2 タグの長さ (ヘッダとフッタを含む) を返す。タグが無い場合は 0 を返す。
+func id3v2Size(data []byte) int {
+	if len(data) < 10 || !bytes.HasPrefix(data, []byte("ID3")) {
+		return 0
+	}
+	// サイズは syncsafe integer (各バイトの下位7bit)
+	size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+	size += 10
+	if data[5]&0x10 != 0 {
+		// フッタ付き
+		size += 10
+	}
+	return size
+}
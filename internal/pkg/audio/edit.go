@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"math"
+)
+
+// Slice は start 秒から end 秒までを切り出す。end が 0 以下の場合は最後まで切り出す。
+// 範囲はクリップの長さに収める。
+func (c *Clip) Slice(start, end float64) *Clip {
+	frames := c.Frames()
+	from := min(frames, max(0, int(math.Round(start*float64(c.SampleRate)))))
+	to := frames
+	if end > 0 {
+		to = min(frames, max(from, int(math.Round(end*float64(c.SampleRate)))))
+	}
+	samples := make([][]float64, c.Channels())
+	for ch := range samples {
+		samples[ch] = c.Samples[ch][from:to]
+	}
+	return &Clip{SampleRate: c.SampleRate, Samples: samples}
+}
+
+// applyFade は先頭の fadeIn 秒と末尾の fadeOut 秒に直線のフェードをかける。
+// フェードの長さはクリップの長さに収める。
+func applyFade(clip *Clip, fadeIn, fadeOut float64) {
+	frames := clip.Frames()
+	in := min(frames, int(math.Round(fadeIn*float64(clip.SampleRate))))
+	out := min(frames, int(math.Round(fadeOut*float64(clip.SampleRate))))
+	for _, ch := range clip.Samples {
+		for i := 0; i < in; i++ {
+			ch[i] *= float64(i) / float64(in)
+		}
+		for i := 0; i < out; i++ {
+			ch[frames-1-i] *= float64(i) / float64(out)
+		}
+	}
+}
+
+// Waveform は波形のプレビュー用に、クリップを n 個の区間に分けてそれぞれの区間のピーク (0〜1) を返す。
+// クリップが n サンプルより短い場合はサンプル数分だけ返す。
+func Waveform(clip *Clip, n int) []float64 {
+	frames := clip.Frames()
+	n = min(n, frames)
+	if n <= 0 {
+		return []float64{}
+	}
+	peaks := make([]float64, n)
+	for i := range peaks {
+		from, to := i*frames/n, (i+1)*frames/n
+		for _, ch := range clip.Samples {
+			for _, v := range ch[from:to] {
+				peaks[i] = math.Max(peaks[i], math.Abs(v))
+			}
+		}
+		peaks[i] = math.Min(1, peaks[i])
+	}
+	return peaks
+}
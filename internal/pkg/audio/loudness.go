@@ -0,0 +1,128 @@
+package audio
+
+import (
+	"math"
+)
+
+// IntegratedLoudness は ITU-R BS.1770 / EBU R128 の integrated loudness (LUFS) を返す。
+// 400ms のブロック (75% 重複) ごとに K 特性で重み付けしたパワーを求め、
+// -70 LUFS の絶対ゲートと、それを通ったブロックの平均から -10 LU の相対ゲートをかけて平均する。
+// 全てのブロックがゲートで落ちた (無音) 場合は -Inf を返す。
+func IntegratedLoudness(clip *Clip) float64 {
+	frames := clip.Frames()
+	if frames == 0 || clip.SampleRate <= 0 {
+		return math.Inf(-1)
+	}
+
+	weighted := make([][]float64, clip.Channels())
+	for ch, samples := range clip.Samples {
+		weighted[ch] = kWeighting(samples, clip.SampleRate)
+	}
+
+	blockSize := clip.SampleRate * 4 / 10
+	step := clip.SampleRate / 10
+	if frames < blockSize {
+		// 400ms に満たない音声は全体を1ブロックとして扱う
+		blockSize = frames
+		step = frames
+	}
+
+	powers := make([]float64, 0, frames/step+1)
+	for start := 0; start+blockSize <= frames; start += step {
+		var power float64
+		for _, ch := range weighted {
+			var sum float64
+			for _, v := range ch[start : start+blockSize] {
+				sum += v * v
+			}
+			power += sum / float64(blockSize)
+		}
+		powers = append(powers, power)
+	}
+
+	absoluteGated := gatePowers(powers, loudnessToPower(-70))
+	if len(absoluteGated) == 0 {
+		return math.Inf(-1)
+	}
+	relativeGate := loudnessToPower(powerToLoudness(mean(absoluteGated)) - 10)
+	relativeGated := gatePowers(absoluteGated, relativeGate)
+	if len(relativeGated) == 0 {
+		return math.Inf(-1)
+	}
+	return powerToLoudness(mean(relativeGated))
+}
+
+// kWeighting は BS.1770 の K 特性フィルタ (高域シェルフ + 高域通過) をかける。
+// 係数は任意のサンプリングレートに対応できるよう、アナログの特性から双一次変換で求める。
+func kWeighting(samples []float64, rate int) []float64 {
+	// 1段目: 高域シェルフ
+	f0, gain, q := 1681.974450955533, 3.999843853973347, 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / float64(rate))
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	// 2段目: 高域通過
+	f0, q = 38.13547087602444, 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / float64(rate))
+	a0 = 1 + k/q + k*k
+	highPass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return highPass.apply(shelf.apply(samples))
+}
+
+// biquad は a0 で正規化した2次の IIR フィルタ
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+}
+
+func (f biquad) apply(in []float64) []float64 {
+	out := make([]float64, len(in))
+	var x1, x2, y1, y2 float64
+	for i, x := range in {
+		y := f.b0*x + f.b1*x1 + f.b2*x2 - f.a1*y1 - f.a2*y2
+		x2, x1 = x1, x
+		y2, y1 = y1, y
+		out[i] = y
+	}
+	return out
+}
+
+func gatePowers(powers []float64, threshold float64) []float64 {
+	gated := make([]float64, 0, len(powers))
+	for _, p := range powers {
+		if p > threshold {
+			gated = append(gated, p)
+		}
+	}
+	return gated
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func powerToLoudness(power float64) float64 {
+	return -0.691 + 10*math.Log10(power)
+}
+
+func loudnessToPower(lufs float64) float64 {
+	return math.Pow(10, (lufs+0.691)/10)
+}
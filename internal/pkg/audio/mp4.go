@@ -0,0 +1,693 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// mp4Track は MP4 (ISO BMFF) の音声トラックを読んだ結果
+type mp4Track struct {
+	trackID uint32
+	codec   string
+	// samples は AAC のフレームまたは Opus のパケット
+	samples   [][]byte
+	aacConfig aacConfig
+	opus      opusHead
+	// sampleRate, channels はサンプルエントリ (AAC の場合は AudioSpecificConfig) の値
+	sampleRate int
+	channels   int
+
+	timescale uint32
+	// duration は mdhd の長さ (timescale 単位)。フラグメント化されたファイルでは 0 のことが多い
+	duration uint64
+	// sampleDuration はサンプルごとの長さの合計 (timescale 単位)
+	sampleDuration uint64
+
+	// trex の既定値 (フラグメント化されたファイル用)
+	defaultSampleDuration uint32
+	defaultSampleSize     uint32
+}
+
+// mp4Box は MP4 のボックス
+type mp4Box struct {
+	typ string
+	// start はファイル先頭からのボックスの位置、bodyStart はヘッダを除いた中身の位置
+	start     int
+	bodyStart int
+	body      []byte
+}
+
+// readMP4 は MP4 の最初の音声トラックのサンプルを取り出す。
+// 通常の moov にサンプルテーブルがあるファイルと、moof に分かれたフラグメント化されたファイルの両方を読む。
+func readMP4(data []byte) (*mp4Track, error) {
+	boxes, err := readMP4Boxes(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	moov := findMP4Box(boxes, "moov")
+	if moov == nil {
+		return nil, errors.New("missing moov box")
+	}
+	moovBoxes, err := moov.children()
+	if err != nil {
+		return nil, err
+	}
+
+	var track *mp4Track
+	for _, trak := range moovBoxes {
+		if trak.typ != "trak" {
+			continue
+		}
+		track, err = readMP4Trak(data, trak)
+		if err != nil {
+			return nil, err
+		}
+		if track != nil {
+			break
+		}
+	}
+	if track == nil {
+		return nil, errors.New("no audio track")
+	}
+
+	if mvex := findMP4Box(moovBoxes, "mvex"); mvex != nil {
+		if err := track.readTrex(*mvex); err != nil {
+			return nil, err
+		}
+	}
+	for _, moof := range boxes {
+		if moof.typ != "moof" {
+			continue
+		}
+		if err := track.readFragment(data, moof); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(track.samples) == 0 {
+		return nil, errors.New("audio track has no samples")
+	}
+	if track.duration == 0 {
+		track.duration = track.sampleDuration
+	}
+	return track, nil
+}
+
+// durationSeconds は長さを秒で返す
+func (t *mp4Track) durationSeconds() float64 {
+	if t.timescale == 0 {
+		return 0
+	}
+	return float64(t.duration) / float64(t.timescale)
+}
+
+// readMP4Trak は trak を読む。音声以外のトラックの場合は nil を返す
+func readMP4Trak(data []byte, trak mp4Box) (*mp4Track, error) {
+	trakBoxes, err := trak.children()
+	if err != nil {
+		return nil, err
+	}
+	mdia, err := findMP4Path(trakBoxes, "mdia")
+	if err != nil {
+		return nil, err
+	}
+	mdiaBoxes, err := mdia.children()
+	if err != nil {
+		return nil, err
+	}
+	hdlr := findMP4Box(mdiaBoxes, "hdlr")
+	if hdlr == nil || len(hdlr.body) < 12 || string(hdlr.body[8:12]) != "soun" {
+		return nil, nil
+	}
+
+	track := &mp4Track{}
+	if tkhd := findMP4Box(trakBoxes, "tkhd"); tkhd != nil {
+		r := &byteReader{data: tkhd.body}
+		if r.u8() == 1 {
+			r.skip(3 + 16)
+		} else {
+			r.skip(3 + 8)
+		}
+		track.trackID = r.u32()
+		if r.err != nil {
+			return nil, fmt.Errorf("invalid tkhd box: %w", r.err)
+		}
+	}
+	mdhd := findMP4Box(mdiaBoxes, "mdhd")
+	if mdhd == nil {
+		return nil, errors.New("missing mdhd box")
+	}
+	r := &byteReader{data: mdhd.body}
+	if r.u8() == 1 {
+		r.skip(3 + 16)
+		track.timescale = r.u32()
+		track.duration = r.u64()
+	} else {
+		r.skip(3 + 8)
+		track.timescale = r.u32()
+		track.duration = uint64(r.u32())
+		if track.duration == math.MaxUint32 {
+			track.duration = math.MaxUint64
+		}
+	}
+	if r.err != nil || track.timescale == 0 {
+		return nil, errors.New("invalid mdhd box")
+	}
+	// 全ビットが 1 の場合は長さ不明
+	if track.duration == math.MaxUint64 {
+		track.duration = 0
+	}
+
+	stbl, err := findMP4Path(mdiaBoxes, "minf", "stbl")
+	if err != nil {
+		return nil, err
+	}
+	stblBoxes, err := stbl.children()
+	if err != nil {
+		return nil, err
+	}
+	stsd := findMP4Box(stblBoxes, "stsd")
+	if stsd == nil {
+		return nil, errors.New("missing stsd box")
+	}
+	if err := track.readSampleEntry(*stsd); err != nil {
+		return nil, err
+	}
+	if err := track.readSampleTable(data, stblBoxes); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// readSampleEntry は stsd の最初のサンプルエントリからコーデックと設定を読む
+func (t *mp4Track) readSampleEntry(stsd mp4Box) error {
+	r := &byteReader{data: stsd.body}
+	r.skip(4)
+	if r.u32() == 0 || r.err != nil {
+		return errors.New("missing sample entry")
+	}
+	entries, err := readMP4Boxes(r.rest(), stsd.bodyStart+r.pos)
+	if err != nil || len(entries) == 0 {
+		return errors.New("invalid stsd box")
+	}
+	entry := entries[0]
+
+	// AudioSampleEntry: reserved(6), data_reference_index(2), version(2), revision(2), vendor(4),
+	// channelcount(2), samplesize(2), compression_id(2), packet_size(2), samplerate(16.16)
+	er := &byteReader{data: entry.body}
+	er.skip(8)
+	version := er.u16()
+	er.skip(6)
+	channels := er.u16()
+	er.skip(6)
+	sampleRate := er.u32() >> 16
+	// QuickTime のサウンドディスクリプション v1, v2 は後ろにフィールドが追加されている
+	switch version {
+	case 1:
+		er.skip(16)
+	case 2:
+		er.skip(36)
+	}
+	if er.err != nil {
+		return fmt.Errorf("invalid %s sample entry", entry.typ)
+	}
+	t.sampleRate, t.channels = int(sampleRate), int(channels)
+	children, err := readMP4Boxes(er.rest(), entry.bodyStart+er.pos)
+	if err != nil {
+		return err
+	}
+
+	switch entry.typ {
+	case "mp4a":
+		esds := findMP4Box(children, "esds")
+		if esds == nil {
+			// QuickTime では wave の中に入っている
+			if wave, err := findMP4Path(children, "wave", "esds"); err == nil {
+				esds = wave
+			}
+		}
+		if esds == nil {
+			return errors.New("missing esds box")
+		}
+		asc, err := readESDS(esds.body)
+		if err != nil {
+			return err
+		}
+		config, err := parseAudioSpecificConfig(asc)
+		if err != nil {
+			return err
+		}
+		t.codec = CodecAAC
+		t.aacConfig = config
+		t.sampleRate = config.sampleRate
+		if config.channels > 0 {
+			t.channels = config.channels
+		}
+	case "Opus":
+		dOps := findMP4Box(children, "dOps")
+		if dOps == nil {
+			return errors.New("missing dOps box")
+		}
+		head, err := parseDOps(dOps.body)
+		if err != nil {
+			return err
+		}
+		t.codec = CodecOpus
+		t.opus = head
+		t.sampleRate, t.channels = opusSampleRate, head.channels
+	default:
+		return fmt.Errorf("unsupported mp4 audio codec: %q", entry.typ)
+	}
+	return nil
+}
+
+// readESDS は esds の ES_Descriptor から AudioSpecificConfig を取り出す (ISO/IEC 14496-1 7.2.6)
+func readESDS(body []byte) ([]byte, error) {
+	r := &byteReader{data: body}
+	r.skip(4)
+	tag, es := readMP4Descriptor(r)
+	if r.err != nil || tag != 0x03 {
+		return nil, errors.New("missing ES_Descriptor")
+	}
+
+	er := &byteReader{data: es}
+	er.skip(2)
+	flags := er.u8()
+	if flags&0x80 != 0 {
+		er.skip(2)
+	}
+	if flags&0x40 != 0 {
+		er.skip(int(er.u8()))
+	}
+	if flags&0x20 != 0 {
+		er.skip(2)
+	}
+	tag, decoderConfig := readMP4Descriptor(er)
+	if er.err != nil || tag != 0x04 {
+		return nil, errors.New("missing DecoderConfigDescriptor")
+	}
+
+	dr := &byteReader{data: decoderConfig}
+	// 0x40 は MPEG-4 Audio、0x66〜0x68 は MPEG-2 AAC (Main, LC, SSR)
+	if objectType := dr.u8(); objectType != 0x40 && (objectType < 0x66 || objectType > 0x68) {
+		return nil, fmt.Errorf("unsupported mp4 audio object type: 0x%02x", objectType)
+	}
+	dr.skip(12)
+	tag, asc := readMP4Descriptor(dr)
+	if dr.err != nil || tag != 0x05 || len(asc) == 0 {
+		return nil, errors.New("missing AudioSpecificConfig")
+	}
+	return asc, nil
+}
+
+// readMP4Descriptor は MPEG-4 の記述子 (タグと、7bit ずつの可変長のサイズ) を読む
+func readMP4Descriptor(r *byteReader) (byte, []byte) {
+	tag := r.u8()
+	size := 0
+	for i := 0; i < 4; i++ {
+		b := r.u8()
+		size = size<<7 | int(b&0x7F)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return tag, r.bytes(size)
+}
+
+// parseDOps は MP4 の Opus の設定 (OpusSpecificBox) を読む。OpusHead と違いビッグエンディアン
+func parseDOps(body []byte) (opusHead, error) {
+	r := &byteReader{data: body}
+	r.skip(1)
+	head := opusHead{channels: int(r.u8()), preSkip: int(r.u16())}
+	r.skip(6)
+	mapping := r.u8()
+	if r.err != nil {
+		return opusHead{}, errors.New("invalid dOps box")
+	}
+	if mapping != 0 || head.channels < 1 || head.channels > 2 {
+		return opusHead{}, fmt.Errorf("unsupported opus channel layout (channels: %d, mapping family: %d)", head.channels, mapping)
+	}
+	return head, nil
+}
+
+// readSampleTable は stbl のサンプルテーブル (stsz, stsc, stco / co64, stts) からサンプルを取り出す
+func (t *mp4Track) readSampleTable(data []byte, stblBoxes []mp4Box) error {
+	stsz := findMP4Box(stblBoxes, "stsz")
+	if stsz == nil {
+		return errors.New("missing stsz box")
+	}
+	r := &byteReader{data: stsz.body}
+	r.skip(4)
+	sampleSize := r.u32()
+	sampleCount := r.u32()
+	if r.err != nil {
+		return errors.New("invalid stsz box")
+	}
+	if sampleCount == 0 {
+		// フラグメント化されたファイルはサンプルが moof にある
+		return nil
+	}
+	if uint64(sampleCount) > uint64(len(data)) {
+		return errors.New("invalid mp4 sample count")
+	}
+	sizes := make([]uint32, sampleCount)
+	for i := range sizes {
+		if sampleSize != 0 {
+			sizes[i] = sampleSize
+		} else {
+			sizes[i] = r.u32()
+		}
+	}
+	if r.err != nil {
+		return errors.New("truncated stsz box")
+	}
+
+	var offsets []uint64
+	if stco := findMP4Box(stblBoxes, "stco"); stco != nil {
+		r := &byteReader{data: stco.body}
+		r.skip(4)
+		count := r.u32()
+		for i := uint32(0); i < count && r.err == nil; i++ {
+			offsets = append(offsets, uint64(r.u32()))
+		}
+		if r.err != nil {
+			return errors.New("truncated stco box")
+		}
+	} else if co64 := findMP4Box(stblBoxes, "co64"); co64 != nil {
+		r := &byteReader{data: co64.body}
+		r.skip(4)
+		count := r.u32()
+		for i := uint32(0); i < count && r.err == nil; i++ {
+			offsets = append(offsets, r.u64())
+		}
+		if r.err != nil {
+			return errors.New("truncated co64 box")
+		}
+	} else {
+		return errors.New("missing stco box")
+	}
+
+	stsc := findMP4Box(stblBoxes, "stsc")
+	if stsc == nil {
+		return errors.New("missing stsc box")
+	}
+	type chunkRun struct{ firstChunk, samplesPerChunk uint32 }
+	var runs []chunkRun
+	sr := &byteReader{data: stsc.body}
+	sr.skip(4)
+	count := sr.u32()
+	for i := uint32(0); i < count && sr.err == nil; i++ {
+		runs = append(runs, chunkRun{firstChunk: sr.u32(), samplesPerChunk: sr.u32()})
+		sr.skip(4)
+	}
+	if sr.err != nil {
+		return errors.New("truncated stsc box")
+	}
+
+	// チャンクごとに、stsc で決まる数のサンプルが連続して並んでいる
+	index := 0
+	for i, run := range runs {
+		lastChunk := uint32(len(offsets))
+		if i+1 < len(runs) {
+			lastChunk = runs[i+1].firstChunk - 1
+		}
+		if run.firstChunk == 0 || lastChunk > uint32(len(offsets)) {
+			return errors.New("invalid stsc box")
+		}
+		for chunk := run.firstChunk; chunk <= lastChunk && index < len(sizes); chunk++ {
+			offset := offsets[chunk-1]
+			for j := uint32(0); j < run.samplesPerChunk && index < len(sizes); j++ {
+				sample, err := mp4Sample(data, offset, sizes[index])
+				if err != nil {
+					return err
+				}
+				t.samples = append(t.samples, sample)
+				offset += uint64(sizes[index])
+				index++
+			}
+		}
+	}
+	if index != len(sizes) {
+		return errors.New("mp4 sample table is inconsistent")
+	}
+
+	if stts := findMP4Box(stblBoxes, "stts"); stts != nil {
+		r := &byteReader{data: stts.body}
+		r.skip(4)
+		count := r.u32()
+		for i := uint32(0); i < count && r.err == nil; i++ {
+			t.sampleDuration += uint64(r.u32()) * uint64(r.u32())
+		}
+		if r.err != nil {
+			return errors.New("truncated stts box")
+		}
+	}
+	return nil
+}
+
+// readTrex は mvex の trex からフラグメントのサンプルの既定値を読む
+func (t *mp4Track) readTrex(mvex mp4Box) error {
+	boxes, err := mvex.children()
+	if err != nil {
+		return err
+	}
+	for _, trex := range boxes {
+		if trex.typ != "trex" {
+			continue
+		}
+		r := &byteReader{data: trex.body}
+		r.skip(4)
+		trackID := r.u32()
+		r.skip(4)
+		duration, size := r.u32(), r.u32()
+		if r.err != nil {
+			return errors.New("invalid trex box")
+		}
+		if trackID == t.trackID {
+			t.defaultSampleDuration, t.defaultSampleSize = duration, size
+		}
+	}
+	return nil
+}
+
+// readFragment は moof のうち、このトラックの traf のサンプルを取り出す
+func (t *mp4Track) readFragment(data []byte, moof mp4Box) error {
+	moofBoxes, err := moof.children()
+	if err != nil {
+		return err
+	}
+	for _, traf := range moofBoxes {
+		if traf.typ != "traf" {
+			continue
+		}
+		trafBoxes, err := traf.children()
+		if err != nil {
+			return err
+		}
+		tfhd := findMP4Box(trafBoxes, "tfhd")
+		if tfhd == nil {
+			return errors.New("missing tfhd box")
+		}
+		r := &byteReader{data: tfhd.body}
+		flags := r.u32() & 0xFFFFFF
+		if r.u32() != t.trackID {
+			continue
+		}
+		// base-data-offset が無い場合は moof の先頭を基準にする
+		base := uint64(moof.start)
+		duration, size := t.defaultSampleDuration, t.defaultSampleSize
+		if flags&0x01 != 0 {
+			base = r.u64()
+		}
+		if flags&0x02 != 0 {
+			r.skip(4)
+		}
+		if flags&0x08 != 0 {
+			duration = r.u32()
+		}
+		if flags&0x10 != 0 {
+			size = r.u32()
+		}
+		if r.err != nil {
+			return errors.New("invalid tfhd box")
+		}
+
+		offset := base
+		for _, trun := range trafBoxes {
+			if trun.typ != "trun" {
+				continue
+			}
+			r := &byteReader{data: trun.body}
+			flags := r.u32() & 0xFFFFFF
+			count := r.u32()
+			if flags&0x01 != 0 {
+				offset = uint64(int64(base) + int64(int32(r.u32())))
+			}
+			if flags&0x04 != 0 {
+				r.skip(4)
+			}
+			for i := uint32(0); i < count; i++ {
+				sampleDuration, sampleSize := duration, size
+				if flags&0x100 != 0 {
+					sampleDuration = r.u32()
+				}
+				if flags&0x200 != 0 {
+					sampleSize = r.u32()
+				}
+				if flags&0x400 != 0 {
+					r.skip(4)
+				}
+				if flags&0x800 != 0 {
+					r.skip(4)
+				}
+				if r.err != nil {
+					return errors.New("truncated trun box")
+				}
+				sample, err := mp4Sample(data, offset, sampleSize)
+				if err != nil {
+					return err
+				}
+				t.samples = append(t.samples, sample)
+				offset += uint64(sampleSize)
+				t.sampleDuration += uint64(sampleDuration)
+			}
+		}
+	}
+	return nil
+}
+
+// mp4Sample はファイルの offset から size バイトのサンプルを取り出す
+func mp4Sample(data []byte, offset uint64, size uint32) ([]byte, error) {
+	if size == 0 || offset > uint64(len(data)) || uint64(size) > uint64(len(data))-offset {
+		return nil, errors.New("mp4 sample is out of range")
+	}
+	return data[offset : offset+uint64(size)], nil
+}
+
+// readMP4Boxes は data に並んでいるボックスを読む。offset は data のファイル先頭からの位置
+func readMP4Boxes(data []byte, offset int) ([]mp4Box, error) {
+	var boxes []mp4Box
+	for pos := 0; pos < len(data); {
+		if len(data)-pos < 8 {
+			return nil, errors.New("truncated mp4 box")
+		}
+		size := uint64(binary.BigEndian.Uint32(data[pos:]))
+		typ := string(data[pos+4 : pos+8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			// ファイルの最後まで
+			size = uint64(len(data) - pos)
+		case 1:
+			// 64bit のサイズ
+			if len(data)-pos < 16 {
+				return nil, errors.New("truncated mp4 box")
+			}
+			size = binary.BigEndian.Uint64(data[pos+8:])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)-pos) {
+			return nil, fmt.Errorf("invalid mp4 box size: %q", typ)
+		}
+		boxes = append(boxes, mp4Box{
+			typ:       typ,
+			start:     offset + pos,
+			bodyStart: offset + pos + int(header),
+			body:      data[pos+int(header) : pos+int(size)],
+		})
+		pos += int(size)
+	}
+	return boxes, nil
+}
+
+// children はボックスの中身を子ボックスとして読む
+func (b mp4Box) children() ([]mp4Box, error) {
+	return readMP4Boxes(b.body, b.bodyStart)
+}
+
+// findMP4Box は boxes から最初の typ のボックスを探す
+func findMP4Box(boxes []mp4Box, typ string) *mp4Box {
+	for i := range boxes {
+		if boxes[i].typ == typ {
+			return &boxes[i]
+		}
+	}
+	return nil
+}
+
+// findMP4Path は boxes から path を順に辿ってボックスを探す
+func findMP4Path(boxes []mp4Box, path ...string) (*mp4Box, error) {
+	var box *mp4Box
+	for i, typ := range path {
+		if i > 0 {
+			children, err := box.children()
+			if err != nil {
+				return nil, err
+			}
+			boxes = children
+		}
+		box = findMP4Box(boxes, typ)
+		if box == nil {
+			return nil, fmt.Errorf("missing %s box", typ)
+		}
+	}
+	return box, nil
+}
+
+// byteReader はビッグエンディアンの値を順に読む。データが足りない場合は err を設定して 0 を返す
+type byteReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *byteReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data)-r.pos < n {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *byteReader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *byteReader) rest() []byte {
+	return r.data[r.pos:]
+}
+
+func (r *byteReader) u8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *byteReader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *byteReader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *byteReader) u64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
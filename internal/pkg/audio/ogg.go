@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// oggStream は Ogg の最初の論理ストリームを読んだ結果
+type oggStream struct {
+	codec string
+	// packets はヘッダを含む全てのパケット
+	packets [][]byte
+	// lastGranule は最後のページのグラニュール位置 (Vorbis / Opus ではストリーム先頭からのサンプル数)
+	lastGranule int64
+	// sampleRate, channels は Vorbis の識別ヘッダの値
+	sampleRate int
+	channels   int
+	opus       opusHead
+}
+
+// oggPageHeaderSize はセグメントテーブルを除いた Ogg のページヘッダの長さ
+const oggPageHeaderSize = 27
+
+// readOgg は Ogg のページをパケットに組み立て、最初のパケットからコーデックを判定する。
+// 複数の論理ストリームが多重化されている場合は最初のストリームだけを読む。
+func readOgg(data []byte) (*oggStream, error) {
+	stream := &oggStream{lastGranule: -1}
+	var (
+		serial  uint32
+		started bool
+		packet  []byte
+	)
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < oggPageHeaderSize || !bytes.Equal(data[offset:offset+4], []byte("OggS")) {
+			return nil, errors.New("invalid ogg page")
+		}
+		header := data[offset : offset+oggPageHeaderSize]
+		granule := int64(binary.LittleEndian.Uint64(header[6:14]))
+		pageSerial := binary.LittleEndian.Uint32(header[14:18])
+		segments := int(header[26])
+		if len(data)-offset < oggPageHeaderSize+segments {
+			return nil, errors.New("truncated ogg page")
+		}
+		lacing := data[offset+oggPageHeaderSize : offset+oggPageHeaderSize+segments]
+		bodySize := 0
+		for _, l := range lacing {
+			bodySize += int(l)
+		}
+		bodyStart := offset + oggPageHeaderSize + segments
+		if len(data)-bodyStart < bodySize {
+			return nil, errors.New("truncated ogg page")
+		}
+		body := data[bodyStart : bodyStart+bodySize]
+		offset = bodyStart + bodySize
+
+		if !started {
+			serial, started = pageSerial, true
+		}
+		if pageSerial != serial {
+			continue
+		}
+
+		// 255 のセグメントは次のセグメントに続く。最後が 255 の場合はパケットが次のページに続く
+		pos := 0
+		for _, l := range lacing {
+			packet = append(packet, body[pos:pos+int(l)]...)
+			pos += int(l)
+			if l < 255 {
+				stream.packets = append(stream.packets, packet)
+				packet = nil
+			}
+		}
+		// -1 はこのページで終わるパケットが無いことを表す
+		if granule != -1 {
+			stream.lastGranule = granule
+		}
+	}
+	if len(stream.packets) == 0 {
+		return nil, errors.New("ogg stream has no packets")
+	}
+
+	first := stream.packets[0]
+	switch {
+	case len(first) >= 16 && bytes.Equal(first[:7], []byte("\x01vorbis")):
+		stream.codec = CodecVorbis
+		stream.channels = int(first[11])
+		stream.sampleRate = int(binary.LittleEndian.Uint32(first[12:16]))
+		if stream.channels == 0 || stream.sampleRate == 0 {
+			return nil, errors.New("invalid vorbis identification header")
+		}
+	case bytes.HasPrefix(first, []byte("OpusHead")):
+		head, err := parseOpusHead(first)
+		if err != nil {
+			return nil, err
+		}
+		if len(stream.packets) < 2 {
+			return nil, errors.New("missing opus comment header")
+		}
+		stream.codec = CodecOpus
+		stream.opus = head
+	case bytes.HasPrefix(first, []byte("\x7fFLAC")):
+		stream.codec = CodecFLAC
+	default:
+		return nil, fmt.Errorf("unknown ogg codec")
+	}
+	if stream.lastGranule < 0 {
+		return nil, errors.New("ogg stream has no granule position")
+	}
+	return stream, nil
+}
+
+// oggDuration は Ogg の長さを秒で返す
+func (s *oggStream) duration() float64 {
+	switch s.codec {
+	case CodecVorbis:
+		return float64(s.lastGranule) / float64(s.sampleRate)
+	case CodecOpus:
+		// Opus のグラニュール位置は常に 48kHz で、先頭の pre-skip 分を含む
+		return float64(max(0, s.lastGranule-int64(s.opus.preSkip))) / opusSampleRate
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,147 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOpusUnavailable は Opus のエンコーダ・デコーダ (libopus) を使わずにビルドされている時に返す
+var ErrOpusUnavailable = errors.New("opus codec is not available (build with -tags opus,nolibopusfile)")
+
+const (
+	// opusMaxPacketSize は Opus の1パケットの最大サイズ
+	opusMaxPacketSize = 4000
+	// opusSampleRate はデコードに使うサンプリングレート。Ogg や WebM のタイムスタンプもこのレートで数える
+	opusSampleRate = 48000
+	// opusMaxFrameSamples は1パケットをデコードした時のチャンネルあたりの最大サンプル数 (120ms)
+	opusMaxFrameSamples = opusSampleRate * 120 / 1000
+)
+
+// newOpusDecoder はテストでデコーダを差し替えられるようにするための変数
+var newOpusDecoder = NewOpusDecoder
+
+// OpusEncoder は PCM を Opus のパケットにエンコードする
+type OpusEncoder interface {
+	// Encode は1フレーム分のインターリーブされた PCM をエンコードして out に書き込み、書き込んだバイト数を返す
+	Encode(pcm []int16, out []byte) (int, error)
+}
+
+// OpusDecoder は Opus のパケットを PCM にデコードする
+type OpusDecoder interface {
+	// Decode は1パケットをデコードしてインターリーブされた PCM を pcm に書き込み、チャンネルあたりのサンプル数を返す
+	Decode(data []byte, pcm []int16) (int, error)
+}
+
+// EncodeOpusFrames は PCM を frameDuration ごとに区切って Opus のパケット列にエンコードする。
+// 最後のフレームが足りない場合は無音で埋める。
+func EncodeOpusFrames(enc OpusEncoder, pcm []int16, sampleRate, channels int, frameDuration time.Duration) ([][]byte, error) {
+	frameSize := int(int64(sampleRate)*int64(frameDuration)/int64(time.Second)) * channels
+	if frameSize <= 0 {
+		return nil, fmt.Errorf("invalid opus frame size: %d", frameSize)
+	}
+
+	frames := make([][]byte, 0, (len(pcm)+frameSize-1)/frameSize)
+	buf := make([]byte, opusMaxPacketSize)
+	frame := make([]int16, frameSize)
+	for offset := 0; offset < len(pcm); offset += frameSize {
+		n := copy(frame, pcm[offset:])
+		clear(frame[n:])
+		size, err := enc.Encode(frame, buf)
+		if err != nil {
+			return nil, fmt.Errorf("opus encode error: %w", err)
+		}
+		frames = append(frames, append([]byte(nil), buf[:size]...))
+	}
+	return frames, nil
+}
+
+// opusHead は Opus の識別ヘッダ (OpusHead) の内容
+type opusHead struct {
+	channels int
+	// preSkip はデコード結果の先頭から捨てるサンプル数 (48kHz)
+	preSkip int
+}
+
+// parseOpusHead は OpusHead (Ogg の最初のパケット、WebM の CodecPrivate) を読む
+func parseOpusHead(data []byte) (opusHead, error) {
+	if len(data) < 19 || string(data[:8]) != "OpusHead" {
+		return opusHead{}, errors.New("invalid opus header")
+	}
+	head := opusHead{
+		channels: int(data[9]),
+		preSkip:  int(binary.LittleEndian.Uint16(data[10:12])),
+	}
+	// チャンネルマッピング 0 (モノラル・ステレオ) 以外はマルチストリームのデコーダが必要になる
+	if mapping := data[18]; mapping != 0 || head.channels < 1 || head.channels > 2 {
+		return opusHead{}, fmt.Errorf("unsupported opus channel layout (channels: %d, mapping family: %d)", head.channels, mapping)
+	}
+	return head, nil
+}
+
+// opusPacketSamples はパケットの TOC から、デコードした時のチャンネルあたりのサンプル数 (48kHz) を返す
+func opusPacketSamples(packet []byte) (int, error) {
+	if len(packet) == 0 {
+		return 0, errors.New("empty opus packet")
+	}
+	toc := packet[0]
+	config := int(toc >> 3)
+	// 1フレームの長さ (1/10ms 単位)
+	var frameTenthMs int
+	switch {
+	case config < 12:
+		// SILK: 10, 20, 40, 60ms
+		frameTenthMs = []int{100, 200, 400, 600}[config%4]
+	case config < 16:
+		// Hybrid: 10, 20ms
+		frameTenthMs = []int{100, 200}[config%2]
+	default:
+		// CELT: 2.5, 5, 10, 20ms
+		frameTenthMs = []int{25, 50, 100, 200}[config%4]
+	}
+
+	var frames int
+	switch toc & 0x03 {
+	case 0:
+		frames = 1
+	case 1, 2:
+		frames = 2
+	default:
+		if len(packet) < 2 {
+			return 0, errors.New("invalid opus packet")
+		}
+		frames = int(packet[1] & 0x3F)
+	}
+	samples := frames * frameTenthMs * opusSampleRate / 10000
+	if samples > opusMaxFrameSamples {
+		return 0, errors.New("invalid opus packet duration")
+	}
+	return samples, nil
+}
+
+// decodeOpusPackets は Opus のパケット列を 48kHz の PCM にデコードし、先頭の pre-skip 分を取り除く。
+// total が 0 以上の場合は、pre-skip を除いた長さが total サンプルになるように末尾を切り詰める。
+func decodeOpusPackets(packets [][]byte, head opusHead, total int64) (*Clip, error) {
+	dec, err := newOpusDecoder(opusSampleRate, head.channels)
+	if err != nil {
+		return nil, err
+	}
+
+	pcm := make([]int16, 0, len(packets)*opusSampleRate/50*head.channels)
+	buf := make([]int16, opusMaxFrameSamples*head.channels)
+	for _, packet := range packets {
+		n, err := dec.Decode(packet, buf)
+		if err != nil {
+			return nil, fmt.Errorf("opus decode error: %w", err)
+		}
+		pcm = append(pcm, buf[:n*head.channels]...)
+	}
+
+	skip := min(head.preSkip*head.channels, len(pcm))
+	pcm = pcm[skip:]
+	if total >= 0 && int64(len(pcm)) > total*int64(head.channels) {
+		pcm = pcm[:total*int64(head.channels)]
+	}
+	return interleavedClip(pcm, opusSampleRate, head.channels), nil
+}
@@ -0,0 +1,28 @@
+//go:build opus
+
+package audio
+
+import "gopkg.in/hraban/opus.v2"
+
+// OpusAvailable は Opus のエンコーダ・デコーダを使えるかを返す
+func OpusAvailable() bool {
+	return true
+}
+
+// NewOpusEncoder は libopus のエンコーダを作る
+func NewOpusEncoder(sampleRate, channels int) (OpusEncoder, error) {
+	enc, err := opus.NewEncoder(sampleRate, channels, opus.AppAudio)
+	if err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+// NewOpusDecoder は libopus のデコーダを作る
+func NewOpusDecoder(sampleRate, channels int) (OpusDecoder, error) {
+	dec, err := opus.NewDecoder(sampleRate, channels)
+	if err != nil {
+		return nil, err
+	}
+	return dec, nil
+}
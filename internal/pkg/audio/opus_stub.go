@@ -0,0 +1,18 @@
+//go:build !opus
+
+package audio
+
+// OpusAvailable は Opus のエンコーダ・デコーダを使えるかを返す
+func OpusAvailable() bool {
+	return false
+}
+
+// NewOpusEncoder は -tags opus を付けずにビルドした場合は常に ErrOpusUnavailable を返す
+func NewOpusEncoder(int, int) (OpusEncoder, error) {
+	return nil, ErrOpusUnavailable
+}
+
+// NewOpusDecoder は -tags opus を付けずにビルドした場合は常に ErrOpusUnavailable を返す
+func NewOpusDecoder(int, int) (OpusDecoder, error) {
+	return nil, ErrOpusUnavailable
+}
@@ -0,0 +1,17 @@
+package audio
+
+import "math"
+
+// PCM16 は指定したサンプリングレート・チャンネル数に変換し、チャンネルをインターリーブした 16bit のサンプル列を返す
+func (c *Clip) PCM16(sampleRate, channels int) []int16 {
+	out := resample(remix(c, channels), sampleRate)
+	frames := out.Frames()
+	pcm := make([]int16, frames*channels)
+	for i := 0; i < frames; i++ {
+		for ch := 0; ch < channels; ch++ {
+			v := math.Max(-1, math.Min(1, out.Samples[ch][i]))
+			pcm[i*channels+ch] = int16(math.Round(v * math.MaxInt16))
+		}
+	}
+	return pcm
+}
@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// previewOpusBitrate は試聴用の Ogg Opus のビットレート
+	previewOpusBitrate = 32000
+	// previewOpusPreSkip は libopus のエンコーダの遅延 (48kHz のサンプル数)。デコーダはこの分を先頭から捨てる
+	previewOpusPreSkip = 312
+	// previewOpusFrameDuration は試聴用の Ogg Opus の1パケットの長さ
+	previewOpusFrameDuration = 20 * time.Millisecond
+	// previewOggPagePackets は1ページに入れるパケットの数 (1秒分)
+	previewOggPagePackets = 50
+	// previewWAVSampleRate は Opus が使えない時の試聴用の WAV のサンプリングレート
+	previewWAVSampleRate = 16000
+)
+
+// newOpusEncoder はテストでエンコーダを差し替えられるようにするための変数
+var newOpusEncoder = NewOpusEncoder
+
+// EncodePreview は試聴用に、音声をモノラルの小さなファイルにエンコードし、その拡張子 (".ogg" か ".wav") と一緒に返す。
+// Opus のエンコーダを使える場合は 32kbps の Ogg Opus、使えない場合は 16kHz 8bit の WAV にする。
+func EncodePreview(clip *Clip) ([]byte, string, error) {
+	if OpusAvailable() {
+		data, err := encodePreviewOpus(clip)
+		return data, ".ogg", err
+	}
+	return encodeWAV8(resample(remix(clip, 1), previewWAVSampleRate)), ".wav", nil
+}
+
+// encodePreviewOpus は音声を 48kHz モノラルの Ogg Opus にエンコードする
+func encodePreviewOpus(clip *Clip) ([]byte, error) {
+	enc, err := newOpusEncoder(opusSampleRate, 1)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := enc.(interface{ SetBitrate(int) error }); ok {
+		if err := e.SetBitrate(previewOpusBitrate); err != nil {
+			return nil, fmt.Errorf("failed to set opus bitrate: %w", err)
+		}
+	}
+	pcm := clip.PCM16(opusSampleRate, 1)
+	packets, err := EncodeOpusFrames(enc, pcm, opusSampleRate, 1, previewOpusFrameDuration)
+	if err != nil {
+		return nil, err
+	}
+	return encodeOggOpus(packets, 1, len(pcm), previewOpusFrameDuration), nil
+}
+
+// encodeOggOpus は Opus のパケット列を Ogg に格納する。
+// samples はチャンネルあたりの実際のサンプル数で、最後のページのグラニュール位置で末尾の埋め草を切り詰める。
+func encodeOggOpus(packets [][]byte, channels, samples int, frameDuration time.Duration) []byte {
+	frameSamples := int64(opusSampleRate * frameDuration / time.Second)
+	total := int64(previewOpusPreSkip + samples)
+
+	head := bytes.NewBuffer(make([]byte, 0, 19))
+	head.WriteString("OpusHead")
+	head.WriteByte(1)
+	head.WriteByte(byte(channels))
+	_ = binary.Write(head, binary.LittleEndian, uint16(previewOpusPreSkip))
+	_ = binary.Write(head, binary.LittleEndian, uint32(opusSampleRate))
+	_ = binary.Write(head, binary.LittleEndian, int16(0)) // output gain
+	head.WriteByte(0)                                     // channel mapping family
+
+	tags := bytes.NewBuffer(nil)
+	tags.WriteString("OpusTags")
+	vendor := "livekit-server"
+	_ = binary.Write(tags, binary.LittleEndian, uint32(len(vendor)))
+	tags.WriteString(vendor)
+	_ = binary.Write(tags, binary.LittleEndian, uint32(0))
+
+	w := &oggWriter{serial: 1}
+	w.writePage([][]byte{head.Bytes()}, 0, oggHeaderTypeBOS)
+	w.writePage([][]byte{tags.Bytes()}, 0, 0)
+	if len(packets) == 0 {
+		w.writePage(nil, total, oggHeaderTypeEOS)
+		return w.buf.Bytes()
+	}
+	for i := 0; i < len(packets); i += previewOggPagePackets {
+		end := min(len(packets), i+previewOggPagePackets)
+		granule := min(total, previewOpusPreSkip+int64(end)*frameSamples)
+		var headerType byte
+		if end == len(packets) {
+			granule, headerType = total, oggHeaderTypeEOS
+		}
+		w.writePage(packets[i:end], granule, headerType)
+	}
+	return w.buf.Bytes()
+}
+
+const (
+	oggHeaderTypeBOS = 0x02
+	oggHeaderTypeEOS = 0x04
+)
+
+// oggWriter は1つの論理ストリームの Ogg のページを書き出す
+type oggWriter struct {
+	buf      bytes.Buffer
+	serial   uint32
+	sequence uint32
+}
+
+// writePage は packets を1ページに書き込む。1ページのセグメントは 255 個までなので、呼び出し側で packets の数を抑える。
+func (w *oggWriter) writePage(packets [][]byte, granule int64, headerType byte) {
+	var lacing []byte
+	for _, p := range packets {
+		for n := len(p); ; n -= 255 {
+			if n < 255 {
+				lacing = append(lacing, byte(n))
+				break
+			}
+			lacing = append(lacing, 255)
+		}
+	}
+
+	page := make([]byte, oggPageHeaderSize, oggPageHeaderSize+len(lacing))
+	copy(page, "OggS")
+	page[5] = headerType
+	binary.LittleEndian.PutUint64(page[6:14], uint64(granule))
+	binary.LittleEndian.PutUint32(page[14:18], w.serial)
+	binary.LittleEndian.PutUint32(page[18:22], w.sequence)
+	page[26] = byte(len(lacing))
+	page = append(page, lacing...)
+	for _, p := range packets {
+		page = append(page, p...)
+	}
+	binary.LittleEndian.PutUint32(page[22:26], oggCRC(page))
+	w.buf.Write(page)
+	w.sequence++
+}
+
+// oggCRCTable は Ogg のページのチェックサム (多項式 0x04c11db7, 初期値 0, ビット反転なし) の表
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		table[i] = r
+	}
+	return table
+}()
+
+func oggCRC(page []byte) uint32 {
+	var crc uint32
+	for _, b := range page {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// encodeWAV8 は音声を 8bit PCM の WAV にエンコードする
+func encodeWAV8(clip *Clip) []byte {
+	channels := clip.Channels()
+	frames := clip.Frames()
+	dataSize := frames * channels
+
+	buf := bytes.NewBuffer(make([]byte, 0, 44+dataSize+dataSize%2))
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(36+dataSize+dataSize%2))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(1)) // PCM
+	_ = binary.Write(buf, binary.LittleEndian, uint16(channels))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(clip.SampleRate))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(clip.SampleRate*channels))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(channels))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(8))
+
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+	for i := 0; i < frames; i++ {
+		for ch := 0; ch < channels; ch++ {
+			// 8bit の WAV は符号なしで、128 が無音
+			v := math.Max(-1, math.Min(1, clip.Samples[ch][i]))
+			buf.WriteByte(byte(128 + math.Round(v*127)))
+		}
+	}
+	// チャンクの長さが奇数の場合は 1 バイト埋める
+	if dataSize%2 == 1 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
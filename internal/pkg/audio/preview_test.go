@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+// fakeOpusEncoder は1フレームを 20ms の CELT のパケット (TOC バイトのみ) にエンコードする
+type fakeOpusEncoder struct {
+	bitrate int
+}
+
+func (e *fakeOpusEncoder) Encode(_ []int16, out []byte) (int, error) {
+	out[0] = 31 << 3
+	return 1, nil
+}
+
+func (e *fakeOpusEncoder) SetBitrate(bitrate int) error {
+	e.bitrate = bitrate
+	return nil
+}
+
+func sineClip(sampleRate int, seconds float64) *Clip {
+	samples := make([]float64, int(float64(sampleRate)*seconds))
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(2*math.Pi*440*float64(i)/float64(sampleRate))
+	}
+	return &Clip{SampleRate: sampleRate, Samples: [][]float64{samples}}
+}
+
+func TestEncodePreviewOpus(t *testing.T) {
+	enc := &fakeOpusEncoder{}
+	orig := newOpusEncoder
+	t.Cleanup(func() { newOpusEncoder = orig })
+	newOpusEncoder = func(int, int) (OpusEncoder, error) { return enc, nil }
+
+	// 1.5秒 = 75 パケットで、2ページに分かれる
+	data, err := encodePreviewOpus(sineClip(48000, 1.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc.bitrate != previewOpusBitrate {
+		t.Errorf("bitrate = %d, want %d", enc.bitrate, previewOpusBitrate)
+	}
+	stream, err := readOgg(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stream.codec != CodecOpus || stream.opus.channels != 1 || stream.opus.preSkip != previewOpusPreSkip {
+		t.Errorf("stream = %s %d ch pre-skip %d", stream.codec, stream.opus.channels, stream.opus.preSkip)
+	}
+	if got := len(stream.packets) - 2; got != 75 {
+		t.Errorf("audio packets = %d, want 75", got)
+	}
+	if math.Abs(stream.duration()-1.5) > 1e-9 {
+		t.Errorf("duration = %f, want 1.5", stream.duration())
+	}
+}
+
+func TestOggCRC(t *testing.T) {
+	// CRC-32 (多項式 0x04c11db7, 初期値 0, 最後の反転なし) の "123456789" に対する値
+	if got := oggCRC([]byte("123456789")); got != 0x89a1897f {
+		t.Errorf("oggCRC() = %#x, want 0x89a1897f", got)
+	}
+}
+
+func TestEncodeWAV8(t *testing.T) {
+	clip := sineClip(16000, 0.25)
+	decoded, err := Decode(encodeWAV8(clip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SampleRate != 16000 || decoded.Channels() != 1 || decoded.Frames() != clip.Frames() {
+		t.Fatalf("Decode() = %d Hz %d ch %d frames", decoded.SampleRate, decoded.Channels(), decoded.Frames())
+	}
+	for i, v := range clip.Samples[0] {
+		if math.Abs(decoded.Samples[0][i]-v) > 1.0/64 {
+			t.Fatalf("sample %d = %f, want %f", i, decoded.Samples[0][i], v)
+		}
+	}
+}
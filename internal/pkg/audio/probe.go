@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/go-audio/wav"
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/mewkiz/flac"
+)
+
+// Info はデコードせずにヘッダ等から読み取った音声ファイルの情報
+type Info struct {
+	Format     Format
+	Codec      string
+	SampleRate int
+	Channels   int
+	// Duration は長さ (秒)。ファイルに書かれていない場合は 0
+	Duration float64
+}
+
+// Probe はファイルの形式を判定し、デコードせずに (Opus と AAC のデコーダが無いビルドでも) 長さ等を読み取る。
+// 長すぎるファイルをデコードする前に弾くために使う。
+func Probe(data []byte) (*Info, error) {
+	var (
+		info *Info
+		err  error
+	)
+	format := DetectFormat(data)
+	switch format {
+	case FormatMP3:
+		info, err = probeMp3(data)
+	case FormatWAV:
+		info, err = probeWav(data)
+	case FormatOgg:
+		info, err = probeOgg(data)
+	case FormatFLAC:
+		info, err = probeFlac(data)
+	case FormatMP4:
+		info, err = probeMP4(data)
+	case FormatWebM:
+		info, err = probeWebM(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s probe error: %w", format, err)
+	}
+	info.Format = format
+	return info, nil
+}
+
+func probeMp3(data []byte) (*Info, error) {
+	decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if decoder.SampleRate() <= 0 || decoder.Length() < 0 {
+		return nil, errors.New("invalid mp3 stream")
+	}
+	// go-mp3 の Length は 16bit ステレオに変換した後のバイト数
+	info := &Info{
+		Codec:      CodecMP3,
+		SampleRate: decoder.SampleRate(),
+		Channels:   2,
+		Duration:   float64(decoder.Length()/4) / float64(decoder.SampleRate()),
+	}
+	// フレームヘッダのチャンネルモードが 3 ならモノラル
+	if header := data[id3v2Size(data):]; isMP3FrameSync(header) && header[3]>>6 == 3 {
+		info.Channels = 1
+	}
+	return info, nil
+}
+
+func probeWav(data []byte) (*Info, error) {
+	decoder := wav.NewDecoder(bytes.NewReader(data))
+	if err := decoder.FwdToPCM(); err != nil {
+		return nil, err
+	}
+	frameBytes := int64(decoder.NumChans) * int64(decoder.BitDepth/8)
+	if decoder.SampleRate == 0 || frameBytes == 0 {
+		return nil, errors.New("invalid wav format")
+	}
+	return &Info{
+		Codec:      CodecPCM,
+		SampleRate: int(decoder.SampleRate),
+		Channels:   int(decoder.NumChans),
+		Duration:   float64(decoder.PCMLen()/frameBytes) / float64(decoder.SampleRate),
+	}, nil
+}
+
+func probeOgg(data []byte) (*Info, error) {
+	stream, err := readOgg(data)
+	if err != nil {
+		return nil, err
+	}
+	switch stream.codec {
+	case CodecVorbis:
+		return &Info{Codec: CodecVorbis, SampleRate: stream.sampleRate, Channels: stream.channels, Duration: stream.duration()}, nil
+	case CodecOpus:
+		return &Info{Codec: CodecOpus, SampleRate: opusSampleRate, Channels: stream.opus.channels, Duration: stream.duration()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ogg codec: %s", stream.codec)
+	}
+}
+
+func probeFlac(data []byte) (*Info, error) {
+	stream, err := flac.New(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	info := stream.Info
+	if info.SampleRate == 0 {
+		return nil, errors.New("invalid flac stream info")
+	}
+	// NSamples が 0 の場合は長さ不明
+	return &Info{
+		Codec:      CodecFLAC,
+		SampleRate: int(info.SampleRate),
+		Channels:   int(info.NChannels),
+		Duration:   float64(info.NSamples) / float64(info.SampleRate),
+	}, nil
+}
+
+func probeMP4(data []byte) (*Info, error) {
+	track, err := readMP4(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Codec: track.codec, SampleRate: track.sampleRate, Channels: track.channels, Duration: track.durationSeconds()}, nil
+}
+
+func probeWebM(data []byte) (*Info, error) {
+	track, err := readWebM(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Codec: CodecOpus, SampleRate: opusSampleRate, Channels: track.opus.channels, Duration: track.duration()}, nil
+}
@@ -0,0 +1,216 @@
+package audio
+
+import (
+	"math"
+)
+
+// Options は音声の加工方法
+type Options struct {
+	// SampleRate は出力のサンプリングレート
+	SampleRate int
+	// Channels は出力のチャンネル数 (1 か 2)
+	Channels int
+	// TargetLUFS は正規化後のラウドネス (EBU R128 の integrated loudness)
+	TargetLUFS float64
+	// MaxPeakDBFS はゲインを上げた結果サンプルのピークがこれを超える場合に、超えないようゲインを抑える
+	MaxPeakDBFS float64
+	// TrimSilence が true の場合は先頭と末尾の無音を取り除く
+	TrimSilence bool
+	// SilenceThresholdDBFS はこれ以下の振幅を無音とみなす
+	SilenceThresholdDBFS float64
+	// FadeIn, FadeOut は無音を取り除いた後の先頭と末尾にかけるフェードの長さ (秒)
+	FadeIn  float64
+	FadeOut float64
+	// WaveformPoints が正の場合は、加工後の波形のプレビュー (ピークの配列) をこの数だけ作る
+	WaveformPoints int
+}
+
+// DefaultOptions はサウンドボード用の標準の加工方法 (48kHz モノラル, -16 LUFS) を返す
+func DefaultOptions() Options {
+	return Options{
+		SampleRate:           48000,
+		Channels:             1,
+		TargetLUFS:           -16,
+		MaxPeakDBFS:          -1,
+		TrimSilence:          true,
+		SilenceThresholdDBFS: -50,
+	}
+}
+
+// Result は加工結果
+type Result struct {
+	// WAV は加工後の音声 (16bit PCM の WAV)
+	WAV []byte
+	// Clip は WAV にエンコードする前の加工後の音声
+	Clip *Clip
+	// Duration は加工後の長さ (秒)
+	Duration float64
+	// InputLUFS は加工前のラウドネス。無音の場合は -Inf
+	InputLUFS float64
+	// GainDB は正規化のためにかけたゲイン
+	GainDB float64
+	// Waveform は加工後の波形のプレビュー (0〜1 のピークの配列)。Options.WaveformPoints が 0 の場合は nil
+	Waveform []float64
+}
+
+// Process はチャンネル数の変換・無音の除去・フェード・リサンプリング・ラウドネスの正規化を行い、WAV にエンコードする
+func Process(clip *Clip, opts Options) *Result {
+	out := remix(clip, opts.Channels)
+	if opts.TrimSilence {
+		out = trimSilence(out, dbToLinear(opts.SilenceThresholdDBFS))
+	}
+	applyFade(out, opts.FadeIn, opts.FadeOut)
+	out = resample(out, opts.SampleRate)
+
+	loudness := IntegratedLoudness(out)
+	gainDB := 0.0
+	if !math.IsInf(loudness, -1) {
+		gainDB = opts.TargetLUFS - loudness
+		// ピークが上限を超えないようにゲインを抑える
+		if peak := samplePeak(out); peak > 0 {
+			maxGainDB := opts.MaxPeakDBFS - linearToDB(peak)
+			gainDB = math.Min(gainDB, maxGainDB)
+		}
+		applyGain(out, dbToLinear(gainDB))
+	}
+
+	result := &Result{
+		WAV:       EncodeWAV(out),
+		Clip:      out,
+		Duration:  out.Duration(),
+		InputLUFS: loudness,
+		GainDB:    gainDB,
+	}
+	if opts.WaveformPoints > 0 {
+		result.Waveform = Waveform(out, opts.WaveformPoints)
+	}
+	return result
+}
+
+// remix はチャンネル数を変換する。モノラルへは平均でダウンミックスし、それ以外は足りないチャンネルを複製する。
+func remix(clip *Clip, channels int) *Clip {
+	if channels <= 0 || clip.Channels() == channels {
+		return clip
+	}
+	frames := clip.Frames()
+	samples := make([][]float64, channels)
+	if channels == 1 {
+		mono := make([]float64, frames)
+		for _, ch := range clip.Samples {
+			for i, v := range ch {
+				mono[i] += v / float64(clip.Channels())
+			}
+		}
+		samples[0] = mono
+	} else {
+		for ch := range samples {
+			samples[ch] = append([]float64(nil), clip.Samples[ch%clip.Channels()]...)
+		}
+	}
+	return &Clip{SampleRate: clip.SampleRate, Samples: samples}
+}
+
+// trimSilence は先頭と末尾の threshold 以下の区間を取り除く (前後に 20ms の余白を残す)。
+// 全体が無音の場合はそのまま返す。
+func trimSilence(clip *Clip, threshold float64) *Clip {
+	frames := clip.Frames()
+	first, last := -1, -1
+	for i := 0; i < frames; i++ {
+		for _, ch := range clip.Samples {
+			if math.Abs(ch[i]) > threshold {
+				if first < 0 {
+					first = i
+				}
+				last = i
+				break
+			}
+		}
+	}
+	if first < 0 {
+		return clip
+	}
+
+	padding := clip.SampleRate / 50
+	start := max(0, first-padding)
+	end := min(frames, last+1+padding)
+	samples := make([][]float64, clip.Channels())
+	for ch := range samples {
+		samples[ch] = clip.Samples[ch][start:end]
+	}
+	return &Clip{SampleRate: clip.SampleRate, Samples: samples}
+}
+
+// resampleHalfTaps はリサンプリングに使う窓付き sinc 関数の片側のタップ数
+const resampleHalfTaps = 16
+
+// resample は窓付き sinc 補間でサンプリングレートを変換する。
+// ダウンサンプリングの場合はエイリアシングを防ぐためにカットオフを出力のナイキスト周波数まで下げる。
+func resample(clip *Clip, rate int) *Clip {
+	if rate <= 0 || clip.SampleRate == rate {
+		return clip
+	}
+	ratio := float64(rate) / float64(clip.SampleRate)
+	cutoff := math.Min(1, ratio)
+	inFrames := clip.Frames()
+	outFrames := int(math.Round(float64(inFrames) * ratio))
+	halfWidth := float64(resampleHalfTaps) / cutoff
+
+	samples := make([][]float64, clip.Channels())
+	for ch, in := range clip.Samples {
+		out := make([]float64, outFrames)
+		for i := range out {
+			center := float64(i) / ratio
+			lo := max(0, int(math.Ceil(center-halfWidth)))
+			hi := min(inFrames-1, int(math.Floor(center+halfWidth)))
+			var sum float64
+			for j := lo; j <= hi; j++ {
+				x := float64(j) - center
+				sum += in[j] * cutoff * sinc(cutoff*x) * hann(x/halfWidth)
+			}
+			out[i] = sum
+		}
+		samples[ch] = out
+	}
+	return &Clip{SampleRate: rate, Samples: samples}
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// hann は -1〜1 の範囲の Hann 窓
+func hann(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return 0.5 + 0.5*math.Cos(math.Pi*x)
+}
+
+func samplePeak(clip *Clip) float64 {
+	peak := 0.0
+	for _, ch := range clip.Samples {
+		for _, v := range ch {
+			peak = math.Max(peak, math.Abs(v))
+		}
+	}
+	return peak
+}
+
+func applyGain(clip *Clip, gain float64) {
+	for _, ch := range clip.Samples {
+		for i := range ch {
+			ch[i] *= gain
+		}
+	}
+}
+
+func dbToLinear(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+func linearToDB(v float64) float64 {
+	return 20 * math.Log10(v)
+}
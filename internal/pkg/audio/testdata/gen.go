@@ -0,0 +1,388 @@
+//go:build ignore
+
+// gen.go はテスト用の小さな音声ファイルを生成する。
+// audio パッケージのディレクトリで go run testdata/gen.go を実行する。
+//
+// Opus と AAC のエンコーダは無いので、Opus のパケットは 20ms の無音 (CELT, TOC 0xF8) を並べ、
+// AAC のフレームは中身の無いダミーにしている (テストではデコーダを差し替えてデコードする)。
+// silence.m4a だけは実際のデコーダで読めるように、手で組み立てた無音の AAC-LC フレームを入れている。
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+)
+
+const (
+	toneRate   = 8000
+	toneFrames = 2000
+	opusSkip   = 312
+	aacFrames  = 10
+	aacRate    = 44100
+)
+
+// opusSilence は 20ms (960 サンプル) の無音の Opus パケット (モノラル)
+var opusSilence = []byte{0xF8, 0xFF, 0xFE}
+
+// aacSilence は無音の AAC-LC のフレーム (モノラル)。SCE (global_gain 160, max_sfb 0) と END だけからなる
+var aacSilence = []byte{0x01, 0x40, 0x20, 0x07}
+
+// aacConfig は AAC-LC, 44.1kHz, モノラルの AudioSpecificConfig
+var aacConfig = []byte{0x12, 0x08}
+
+func main() {
+	files := map[string][]byte{
+		"tone.wav":       toneWAV(),
+		"tone.flac":      toneFLAC(),
+		"silence.opus":   oggOpus(),
+		"silence.webm":   webm(),
+		"aac.m4a":        aacMP4(aacFrame),
+		"silence.m4a":    aacMP4(func(int) []byte { return aacSilence }),
+		"fragmented.m4a": fragmentedMP4(),
+		"opus.mp4":       opusMP4(),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join("testdata", name), data, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func tone() []float64 {
+	samples := make([]float64, toneFrames)
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(2*math.Pi*440*float64(i)/toneRate)
+	}
+	return samples
+}
+
+func toneWAV() []byte {
+	return audio.EncodeWAV(&audio.Clip{SampleRate: toneRate, Samples: [][]float64{tone()}})
+}
+
+// toneFLAC は 16bit モノラルの FLAC (verbatim のサブフレーム, 1000 サンプルずつ2フレーム)
+func toneFLAC() []byte {
+	var buf bytes.Buffer
+	info := &meta.StreamInfo{
+		BlockSizeMin:  1000,
+		BlockSizeMax:  1000,
+		SampleRate:    toneRate,
+		NChannels:     1,
+		BitsPerSample: 16,
+		NSamples:      toneFrames,
+	}
+	enc, err := flac.NewEncoder(&buf, info)
+	if err != nil {
+		log.Fatal(err)
+	}
+	samples := tone()
+	for i := 0; i < toneFrames/1000; i++ {
+		block := make([]int32, 1000)
+		for j := range block {
+			block[j] = int32(math.Round(samples[i*1000+j] * 32767))
+		}
+		f := &frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         1000,
+				SampleRate:        toneRate,
+				Channels:          frame.ChannelsMono,
+				BitsPerSample:     16,
+				Num:               uint64(i),
+			},
+			Subframes: []*frame.Subframe{{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   block,
+				NSamples:  len(block),
+			}},
+		}
+		if err := enc.WriteFrame(f); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		log.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func opusHead() []byte {
+	head := []byte("OpusHead")
+	head = append(head, 1, 1)
+	head = binary.LittleEndian.AppendUint16(head, opusSkip)
+	head = binary.LittleEndian.AppendUint32(head, 48000)
+	head = binary.LittleEndian.AppendUint16(head, 0)
+	return append(head, 0)
+}
+
+// oggOpus は 25 パケット (0.5秒) の Ogg Opus。最後のグラニュール位置で 20ms 分を切り詰め、長さは 0.48 秒になる
+func oggOpus() []byte {
+	var buf bytes.Buffer
+	tags := append([]byte("OpusTags"), 0, 0, 0, 0, 0, 0, 0, 0)
+	oggPage(&buf, 0x02, 0, 0, [][]byte{opusHead()})
+	oggPage(&buf, 0, 0, 1, [][]byte{tags})
+	oggPage(&buf, 0, 12*960, 2, repeat(opusSilence, 12))
+	oggPage(&buf, 0x04, opusSkip+24*960, 3, repeat(opusSilence, 13))
+	return buf.Bytes()
+}
+
+func oggPage(buf *bytes.Buffer, flags byte, granule uint64, seq uint32, packets [][]byte) {
+	var lacing, body []byte
+	for _, p := range packets {
+		n := len(p)
+		for ; n >= 255; n -= 255 {
+			lacing = append(lacing, 255)
+		}
+		lacing = append(lacing, byte(n))
+		body = append(body, p...)
+	}
+	page := []byte("OggS")
+	page = append(page, 0, flags)
+	page = binary.LittleEndian.AppendUint64(page, granule)
+	page = binary.LittleEndian.AppendUint32(page, 1)
+	page = binary.LittleEndian.AppendUint32(page, seq)
+	page = binary.LittleEndian.AppendUint32(page, 0)
+	page = append(page, byte(len(lacing)))
+	page = append(page, lacing...)
+	page = append(page, body...)
+	binary.LittleEndian.PutUint32(page[22:], oggCRC(page))
+	buf.Write(page)
+}
+
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func repeat(packet []byte, n int) [][]byte {
+	packets := make([][]byte, n)
+	for i := range packets {
+		packets[i] = packet
+	}
+	return packets
+}
+
+// webm は MediaRecorder と同じくサイズ不明の Segment と Cluster を持つ WebM。
+// 25 パケットを Xiph, EBML, 固定長のレーシングと BlockGroup に分けて入れ、最後に 10ms の DiscardPadding を付ける。
+func webm() []byte {
+	var buf bytes.Buffer
+	buf.Write(ebml(0x1A45DFA3, concat(
+		ebml(0x4282, []byte("webm")),
+	)))
+	// Segment (サイズ不明)
+	buf.Write([]byte{0x18, 0x53, 0x80, 0x67, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	buf.Write(ebml(0x1549A966, ebml(0x2AD7B1, []byte{0x0F, 0x42, 0x40})))
+	buf.Write(ebml(0x1654AE6B, ebml(0xAE, concat(
+		ebml(0xD7, []byte{1}),
+		ebml(0x83, []byte{2}),
+		ebml(0x86, []byte("A_OPUS")),
+		ebml(0x63A2, opusHead()),
+		ebml(0xE1, concat(
+			ebml(0xB5, binary.BigEndian.AppendUint64(nil, math.Float64bits(48000))),
+			ebml(0x9F, []byte{1}),
+		)),
+	))))
+	// Cluster (サイズ不明)
+	buf.Write([]byte{0x1F, 0x43, 0xB6, 0x75, 0xFF})
+	buf.Write(ebml(0xE7, []byte{0}))
+	// Xiph レーシング (2 フレーム)
+	buf.Write(ebml(0xA3, concat([]byte{0x81, 0, 0, 0x82, 1, byte(len(opusSilence))}, opusSilence, opusSilence)))
+	// EBML レーシング (3 フレーム, 2 番目のサイズは差分 0)
+	buf.Write(ebml(0xA3, concat([]byte{0x81, 0, 0, 0x86, 2, 0x80 | byte(len(opusSilence)), 0xBF}, opusSilence, opusSilence, opusSilence)))
+	// 固定長レーシング (2 フレーム)
+	buf.Write(ebml(0xA3, concat([]byte{0x81, 0, 0, 0x84, 1}, opusSilence, opusSilence)))
+	for i := 0; i < 17; i++ {
+		buf.Write(ebml(0xA3, concat([]byte{0x81, 0, 0, 0x80}, opusSilence)))
+	}
+	buf.Write(ebml(0xA0, concat(
+		ebml(0xA1, concat([]byte{0x81, 0, 0, 0}, opusSilence)),
+		ebml(0x75A2, []byte{0x00, 0x98, 0x96, 0x80}),
+	)))
+	return buf.Bytes()
+}
+
+// ebml は要素 ID と中身から要素を作る (サイズは 8 バイトで書く)
+func ebml(id uint32, body []byte) []byte {
+	var out []byte
+	switch {
+	case id > 0xFFFFFF:
+		out = binary.BigEndian.AppendUint32(out, id)
+	case id > 0xFFFF:
+		out = append(out, byte(id>>16), byte(id>>8), byte(id))
+	case id > 0xFF:
+		out = binary.BigEndian.AppendUint16(out, uint16(id))
+	default:
+		out = append(out, byte(id))
+	}
+	out = binary.BigEndian.AppendUint64(out, 0x01<<56|uint64(len(body)))
+	return append(out, body...)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func box(typ string, parts ...[]byte) []byte {
+	body := concat(parts...)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	out = append(out, typ...)
+	return append(out, body...)
+}
+
+// fullBox は version と flags を持つボックス
+func fullBox(typ string, version byte, flags uint32, parts ...[]byte) []byte {
+	header := binary.BigEndian.AppendUint32(nil, uint32(version)<<24|flags)
+	return box(typ, append([][]byte{header}, parts...)...)
+}
+
+func u16(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
+func u32(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+
+// aacFrame は i 番目のダミーの AAC フレーム (長さが異なるようにしている)
+func aacFrame(i int) []byte {
+	return bytes.Repeat([]byte{byte(i)}, 4+i)
+}
+
+func ftyp() []byte {
+	return box("ftyp", []byte("M4A "), u32(0), []byte("M4A isomiso2"))
+}
+
+func tkhd(trackID uint32) []byte {
+	return fullBox("tkhd", 0, 3, u32(0), u32(0), u32(trackID), make([]byte, 68))
+}
+
+func mdhd(timescale, duration uint32) []byte {
+	return fullBox("mdhd", 0, 0, u32(0), u32(0), u32(timescale), u32(duration), u16(0x55C4), u16(0))
+}
+
+func hdlr(handler string) []byte {
+	return fullBox("hdlr", 0, 0, u32(0), []byte(handler), make([]byte, 12), []byte("\x00"))
+}
+
+// audioSampleEntry は AudioSampleEntry (v0) を作る
+func audioSampleEntry(typ string, channels uint16, sampleRate uint32, children ...[]byte) []byte {
+	return box(typ, append([][]byte{make([]byte, 6), u16(1), make([]byte, 8), u16(channels), u16(16), u32(0), u32(sampleRate << 16)}, children...)...)
+}
+
+func esds() []byte {
+	dsi := concat([]byte{0x05, byte(len(aacConfig))}, aacConfig)
+	dcd := concat([]byte{0x04, byte(13 + len(dsi)), 0x40, 0x15, 0, 0, 0}, u32(0), u32(0), dsi)
+	es := concat([]byte{0x03, byte(3 + len(dcd) + 3)}, u16(1), []byte{0}, dcd, []byte{0x06, 0x01, 0x02})
+	return fullBox("esds", 0, 0, es)
+}
+
+func dOps() []byte {
+	return box("dOps", []byte{0, 1}, u16(opusSkip), u32(48000), u16(0), []byte{0})
+}
+
+// stbl はサンプルテーブルを作る。chunks はチャンクごとのサンプル数で、offsets はそれぞれの位置
+func stbl(entry []byte, sizes []uint32, delta uint32, chunks []uint32, offsets []uint32) []byte {
+	stts := fullBox("stts", 0, 0, u32(0))
+	if len(sizes) > 0 {
+		stts = fullBox("stts", 0, 0, u32(1), u32(uint32(len(sizes))), u32(delta))
+	}
+	stsz := []byte{}
+	for _, s := range sizes {
+		stsz = append(stsz, u32(s)...)
+	}
+	stsc := []byte{}
+	for i, n := range chunks {
+		stsc = append(stsc, concat(u32(uint32(i+1)), u32(n), u32(1))...)
+	}
+	stco := []byte{}
+	for _, o := range offsets {
+		stco = append(stco, u32(o)...)
+	}
+	return box("stbl",
+		fullBox("stsd", 0, 0, u32(1), entry),
+		stts,
+		fullBox("stsc", 0, 0, u32(uint32(len(chunks))), stsc),
+		fullBox("stsz", 0, 0, u32(0), u32(uint32(len(sizes))), stsz),
+		fullBox("stco", 0, 0, u32(uint32(len(offsets))), stco),
+	)
+}
+
+func trak(trackID uint32, handler string, timescale, duration uint32, stbl []byte) []byte {
+	return box("trak", tkhd(trackID), box("mdia", mdhd(timescale, duration), hdlr(handler), box("minf", stbl)))
+}
+
+// videoTrak は音声トラックより前に置く、サンプルの無い映像トラック
+func videoTrak() []byte {
+	return trak(1, "vide", 90000, 0, box("stbl"))
+}
+
+// aacMP4 は frame(i) の AAC のフレームを 6 個と 4 個の2チャンクに分けて入れた m4a (mdat が moov より前)
+func aacMP4(frame func(int) []byte) []byte {
+	var sizes []uint32
+	var mdat []byte
+	for i := 0; i < aacFrames; i++ {
+		sizes = append(sizes, uint32(len(frame(i))))
+		mdat = append(mdat, frame(i)...)
+	}
+	head := ftyp()
+	first := uint32(len(head) + 8)
+	second := first + sizes[0] + sizes[1] + sizes[2] + sizes[3] + sizes[4] + sizes[5]
+	moov := box("moov", videoTrak(), trak(2, "soun", aacRate, aacFrames*1024,
+		stbl(audioSampleEntry("mp4a", 1, aacRate, esds()), sizes, 1024, []uint32{6, 4}, []uint32{first, second})))
+	return concat(head, box("mdat", mdat), moov)
+}
+
+// fragmentedMP4 は 5 フレームずつ2つの moof に分けた m4a。サンプルの長さは trex の既定値を使う
+func fragmentedMP4() []byte {
+	out := concat(ftyp(), box("moov",
+		trak(1, "soun", aacRate, 0, stbl(audioSampleEntry("mp4a", 1, aacRate, esds()), nil, 0, nil, nil)),
+		box("mvex", fullBox("trex", 0, 0, u32(1), u32(1), u32(1024), u32(0), u32(0))),
+	))
+	for f := 0; f < 2; f++ {
+		var sizes, mdat []byte
+		for i := f * 5; i < f*5+5; i++ {
+			sizes = append(sizes, u32(uint32(len(aacFrame(i))))...)
+			mdat = append(mdat, aacFrame(i)...)
+		}
+		moof := func(dataOffset uint32) []byte {
+			return box("moof",
+				fullBox("mfhd", 0, 0, u32(uint32(f+1))),
+				box("traf",
+					fullBox("tfhd", 0, 0x020000, u32(1)),
+					fullBox("trun", 0, 0x000201, u32(5), u32(dataOffset), sizes),
+				),
+			)
+		}
+		size := uint32(len(moof(0)))
+		out = concat(out, moof(size+8), box("mdat", mdat))
+	}
+	return out
+}
+
+// opusMP4 は Opus のパケットを 10 個入れた mp4
+func opusMP4() []byte {
+	var sizes []uint32
+	var mdat []byte
+	for i := 0; i < 10; i++ {
+		sizes = append(sizes, uint32(len(opusSilence)))
+		mdat = append(mdat, opusSilence...)
+	}
+	head := ftyp()
+	moov := box("moov", trak(1, "soun", 48000, 10*960,
+		stbl(audioSampleEntry("Opus", 1, 48000, dOps()), sizes, 960, []uint32{10}, []uint32{uint32(len(head) + 8)})))
+	return concat(head, box("mdat", mdat), moov)
+}
@@ -0,0 +1,40 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// EncodeWAV は音声を 16bit PCM の WAV にエンコードする
+func EncodeWAV(clip *Clip) []byte {
+	channels := clip.Channels()
+	frames := clip.Frames()
+	dataSize := frames * channels * 2
+
+	buf := bytes.NewBuffer(make([]byte, 0, 44+dataSize))
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(1)) // PCM
+	_ = binary.Write(buf, binary.LittleEndian, uint16(channels))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(clip.SampleRate))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(clip.SampleRate*channels*2))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(channels*2))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(16))
+
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+	sample := make([]byte, 2)
+	for i := 0; i < frames; i++ {
+		for ch := 0; ch < channels; ch++ {
+			v := math.Max(-1, math.Min(1, clip.Samples[ch][i]))
+			binary.LittleEndian.PutUint16(sample, uint16(int16(math.Round(v*32767))))
+			buf.Write(sample)
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,316 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+// WebM (Matroska) の要素 ID
+const (
+	ebmlIDSegment        = 0x18538067
+	ebmlIDInfo           = 0x1549A966
+	ebmlIDTracks         = 0x1654AE6B
+	ebmlIDTrackEntry     = 0xAE
+	ebmlIDTrackNumber    = 0xD7
+	ebmlIDTrackType      = 0x83
+	ebmlIDCodecID        = 0x86
+	ebmlIDCodecPrivate   = 0x63A2
+	ebmlIDAudio          = 0xE1
+	ebmlIDCluster        = 0x1F43B675
+	ebmlIDSimpleBlock    = 0xA3
+	ebmlIDBlockGroup     = 0xA0
+	ebmlIDBlock          = 0xA1
+	ebmlIDDiscardPadding = 0x75A2
+
+	webmTrackTypeAudio = 2
+	webmCodecOpus      = "A_OPUS"
+)
+
+// webmMasterIDs は中の要素を読む必要があるマスター要素。
+// ブラウザの MediaRecorder が出力する WebM は Segment と Cluster のサイズが不明 (全ビット 1) なので、
+// 入れ子を追わずに、これらの要素の中身もトップレベルと同じ並びとして読む。
+var webmMasterIDs = map[uint32]bool{
+	ebmlIDSegment:    true,
+	ebmlIDInfo:       true,
+	ebmlIDTracks:     true,
+	ebmlIDTrackEntry: true,
+	ebmlIDAudio:      true,
+	ebmlIDCluster:    true,
+	ebmlIDBlockGroup: true,
+}
+
+// webmTrack は WebM の音声トラックを読んだ結果
+type webmTrack struct {
+	number    uint64
+	trackType uint64
+	codecID   string
+	opus      opusHead
+	packets   [][]byte
+	// discardPadding は末尾で捨てる長さ (ナノ秒)
+	discardPadding int64
+	// totalSamples は pre-skip と discardPadding を除いた長さ (48kHz のサンプル数)
+	totalSamples int64
+}
+
+// readWebM は WebM の最初の音声トラックの Opus のパケットを取り出す
+func readWebM(data []byte) (*webmTrack, error) {
+	var (
+		tracks []*webmTrack
+		// current は読んでいる途中の TrackEntry
+		current *webmTrack
+		blocks  = map[uint64][][]byte{}
+		discard = map[uint64]int64{}
+		// BlockGroup の中の Block のトラック番号と、Block より前に出てきた DiscardPadding
+		groupTrack   uint64
+		groupDiscard int64
+	)
+	for pos := 0; pos < len(data); {
+		id, idLen, err := readEBMLID(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+		size, sizeLen, unknown, err := readEBMLSize(data[pos+idLen:])
+		if err != nil {
+			return nil, err
+		}
+		bodyStart := pos + idLen + sizeLen
+
+		if webmMasterIDs[id] {
+			switch id {
+			case ebmlIDTrackEntry:
+				current = &webmTrack{}
+				tracks = append(tracks, current)
+			case ebmlIDBlockGroup:
+				groupTrack, groupDiscard = 0, 0
+			}
+			pos = bodyStart
+			continue
+		}
+		if unknown || size > uint64(len(data)-bodyStart) {
+			return nil, fmt.Errorf("invalid webm element size: 0x%X", id)
+		}
+		body := data[bodyStart : bodyStart+int(size)]
+		pos = bodyStart + int(size)
+
+		switch id {
+		case ebmlIDTrackNumber, ebmlIDTrackType, ebmlIDCodecID, ebmlIDCodecPrivate:
+			if current == nil {
+				continue
+			}
+			switch id {
+			case ebmlIDTrackNumber:
+				current.number = ebmlUint(body)
+			case ebmlIDTrackType:
+				current.trackType = ebmlUint(body)
+			case ebmlIDCodecID:
+				current.codecID = strings.TrimRight(string(body), "\x00")
+			case ebmlIDCodecPrivate:
+				if current.codecID == webmCodecOpus || strings.HasPrefix(string(body), "OpusHead") {
+					head, err := parseOpusHead(body)
+					if err != nil {
+						return nil, err
+					}
+					current.opus = head
+				}
+			}
+		case ebmlIDSimpleBlock, ebmlIDBlock:
+			track, frames, err := readWebMBlock(body)
+			if err != nil {
+				return nil, err
+			}
+			blocks[track] = append(blocks[track], frames...)
+			if id == ebmlIDBlock {
+				groupTrack = track
+				discard[track] += groupDiscard
+				groupDiscard = 0
+			}
+		case ebmlIDDiscardPadding:
+			padding := ebmlInt(body)
+			if groupTrack != 0 {
+				discard[groupTrack] += padding
+			} else {
+				groupDiscard += padding
+			}
+		}
+	}
+
+	var track *webmTrack
+	for _, t := range tracks {
+		if t.trackType == webmTrackTypeAudio {
+			track = t
+			break
+		}
+	}
+	if track == nil {
+		return nil, errors.New("no audio track")
+	}
+	if track.codecID != webmCodecOpus {
+		return nil, fmt.Errorf("unsupported webm audio codec: %q", track.codecID)
+	}
+	if track.opus.channels == 0 {
+		return nil, errors.New("missing opus header")
+	}
+	track.packets = blocks[track.number]
+	if len(track.packets) == 0 {
+		return nil, errors.New("audio track has no packets")
+	}
+	track.discardPadding = discard[track.number]
+
+	for _, packet := range track.packets {
+		samples, err := opusPacketSamples(packet)
+		if err != nil {
+			return nil, err
+		}
+		track.totalSamples += int64(samples)
+	}
+	track.totalSamples -= int64(track.opus.preSkip) + track.discardPadding*opusSampleRate/int64(time.Second)
+	track.totalSamples = max(0, track.totalSamples)
+	return track, nil
+}
+
+// duration は長さを秒で返す
+func (t *webmTrack) duration() float64 {
+	return float64(t.totalSamples) / opusSampleRate
+}
+
+// readWebMBlock は SimpleBlock / Block からトラック番号とフレーム (レーシングされている場合は複数) を取り出す
+func readWebMBlock(body []byte) (uint64, [][]byte, error) {
+	track, n, err := readEBMLVint(body)
+	if err != nil {
+		return 0, nil, err
+	}
+	// タイムコード (2 バイト) とフラグ
+	if len(body) < n+3 {
+		return 0, nil, errors.New("truncated webm block")
+	}
+	flags := body[n+2]
+	payload := body[n+3:]
+
+	lacing := (flags >> 1) & 0x03
+	if lacing == 0 {
+		if len(payload) == 0 {
+			return track, nil, nil
+		}
+		return track, [][]byte{payload}, nil
+	}
+	if len(payload) == 0 {
+		return 0, nil, errors.New("truncated webm block")
+	}
+	count := int(payload[0]) + 1
+	payload = payload[1:]
+
+	sizes := make([]int, count)
+	switch lacing {
+	case 1:
+		// Xiph: 255 が続く限り足していく
+		for i := 0; i < count-1; i++ {
+			for {
+				if len(payload) == 0 {
+					return 0, nil, errors.New("truncated webm lacing")
+				}
+				b := payload[0]
+				payload = payload[1:]
+				sizes[i] += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+	case 3:
+		// EBML: 最初のサイズは符号なし、以降は前のフレームとの差 (符号付き)
+		first, n, err := readEBMLVint(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+		payload = payload[n:]
+		sizes[0] = int(first)
+		for i := 1; i < count-1; i++ {
+			diff, n, err := readEBMLVint(payload)
+			if err != nil {
+				return 0, nil, err
+			}
+			payload = payload[n:]
+			sizes[i] = sizes[i-1] + int(int64(diff)-(int64(1)<<(7*n-1)-1))
+		}
+	case 2:
+		// 固定長
+		if len(payload)%count != 0 {
+			return 0, nil, errors.New("invalid webm fixed-size lacing")
+		}
+		for i := range sizes {
+			sizes[i] = len(payload) / count
+		}
+	}
+
+	frames := make([][]byte, 0, count)
+	for i := 0; i < count-1; i++ {
+		if sizes[i] < 0 || sizes[i] > len(payload) {
+			return 0, nil, errors.New("invalid webm lacing")
+		}
+		frames = append(frames, payload[:sizes[i]])
+		payload = payload[sizes[i]:]
+	}
+	// 最後のフレームは残り全部
+	return track, append(frames, payload), nil
+}
+
+// readEBMLID は要素 ID (先頭の長さを表すビットを含めたまま) を読む
+func readEBMLID(data []byte) (uint32, int, error) {
+	if len(data) == 0 {
+		return 0, 0, errors.New("truncated webm element")
+	}
+	n := bits.LeadingZeros8(data[0]) + 1
+	if n > 4 || len(data) < n {
+		return 0, 0, errors.New("invalid webm element id")
+	}
+	var id uint32
+	for _, b := range data[:n] {
+		id = id<<8 | uint32(b)
+	}
+	return id, n, nil
+}
+
+// readEBMLSize は要素のサイズを読む。全ビットが 1 の場合はサイズ不明
+func readEBMLSize(data []byte) (uint64, int, bool, error) {
+	size, n, err := readEBMLVint(data)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return size, n, size == 1<<(7*n)-1, nil
+}
+
+// readEBMLVint は可変長の整数 (先頭の長さを表すビットを除いた値) を読む
+func readEBMLVint(data []byte) (uint64, int, error) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0, errors.New("invalid webm variable-size integer")
+	}
+	n := bits.LeadingZeros8(data[0]) + 1
+	if len(data) < n {
+		return 0, 0, errors.New("truncated webm variable-size integer")
+	}
+	v := uint64(data[0] & (0xFF >> n))
+	for _, b := range data[1:n] {
+		v = v<<8 | uint64(b)
+	}
+	return v, n, nil
+}
+
+// ebmlUint はビッグエンディアンの符号なし整数の要素の値を返す
+func ebmlUint(body []byte) uint64 {
+	var v uint64
+	for _, b := range body {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+// ebmlInt はビッグエンディアンの符号付き整数の要素の値を返す
+func ebmlInt(body []byte) int64 {
+	if len(body) == 0 || len(body) > 8 {
+		return 0
+	}
+	return int64(ebmlUint(body)<<(64-8*len(body))) >> (64 - 8*len(body))
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AuthConfig は traQ のトークン検証の設定
+type AuthConfig struct {
+	// JWKSURL を指定すると公開鍵を JWKS から取得する
+	JWKSURL string
+	// JWKSCacheTTL は取得した JWKS を使い回す期間
+	JWKSCacheTTL time.Duration
+	// PublicKeysFile は PEM 形式の公開鍵 (複数可) を書いたファイルのパス
+	PublicKeysFile string
+	// PublicKeys は PEM 形式の公開鍵 (複数可)
+	PublicKeys string
+	// DevMode が true の場合のみ開発用の鍵で署名されたトークンを受け付ける
+	DevMode bool
+	// Issuer / Audience は iss / aud クレームの期待値。空の場合は検証しない (DevMode でなければ起動時に警告する)
+	Issuer   string
+	Audience string
+}
+
+func LoadAuthConfig() *AuthConfig {
+	ttl, err := time.ParseDuration(getEnv("TRAQ_JWKS_CACHE_TTL", "1h"))
+	if err != nil || ttl <= 0 {
+		fmt.Println("Invalid TRAQ_JWKS_CACHE_TTL, using default 1h")
+		ttl = time.Hour
+	}
+	return &AuthConfig{
+		JWKSURL:        getEnv("TRAQ_JWKS_URL", ""),
+		JWKSCacheTTL:   ttl,
+		PublicKeysFile: getEnv("TRAQ_PUBLIC_KEYS_FILE", ""),
+		PublicKeys:     getEnv("TRAQ_PUBLIC_KEYS", ""),
+		DevMode:        getEnv("AUTH_DEV_MODE", "false") == "true",
+		Issuer:         getEnv("TRAQ_JWT_ISSUER", ""),
+		Audience:       getEnv("TRAQ_JWT_AUDIENCE", ""),
+	}
+}
+
+// Validate は設定を検証する。他のサービス向けに発行されたトークンを受け付けないよう、
+// DevMode でない場合は iss / aud の期待値を指定するべきなので、無ければエラーを返す
+func (c *AuthConfig) Validate() error {
+	if c.DevMode {
+		return nil
+	}
+	if c.Issuer == "" || c.Audience == "" {
+		return errors.New("TRAQ_JWT_ISSUER and TRAQ_JWT_AUDIENCE should be set unless AUTH_DEV_MODE=true")
+	}
+	return nil
+}
+
+// MetricsToken は /api/metrics を traQ のトークン無しで取得するための Bearer トークン (Prometheus 用)。
+// 空の場合は他の API と同じく traQ のトークンが必要
+func MetricsToken() string {
+	return getEnv("METRICS_TOKEN", "")
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 )
 
 func getEnv(key, defaultValue string) string {
@@ -10,3 +11,24 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvList はカンマ区切りの環境変数を空要素を除いて返す
+func getEnvList(key, defaultValue string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// AllowedOrigins は CORS と WebSocket で許可するオリジンを返す ("*" はサブドメインのワイルドカード)
+func AllowedOrigins() []string {
+	return getEnvList("ALLOWED_ORIGINS", "http://localhost:8080,https://*.traq-preview.trapti.tech,https://*.livekit.trap.show,https://*.trap.jp")
+}
+
+// SoundboardAdmins は全てのサウンドを編集・削除できる traQ ユーザ名を返す
+func SoundboardAdmins() []string {
+	return getEnvList("SOUNDBOARD_ADMINS", "")
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RoomStateReconcileInterval はルーム状態をLiveKitと突き合わせる間隔を返す。
+// "0" もしくは "off" を指定すると突き合わせを行わない (0 を返す)。
+func RoomStateReconcileInterval() time.Duration {
+	value := getEnv("ROOM_STATE_RECONCILE_INTERVAL", "30s")
+	if value == "off" || value == "0" {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		fmt.Println("Invalid ROOM_STATE_RECONCILE_INTERVAL, using default 30s: " + value)
+		return 30 * time.Second
+	}
+	return interval
+}
@@ -6,6 +6,9 @@ type S3Config struct {
 	AccessKeyID     string
 	AccessKeySecret string
 	S3Endpoint      string
+	Region          string
+	// UsePathStyle が true の場合は https://endpoint/bucket/key 形式でアクセスする (MinIO 等)
+	UsePathStyle bool
 }
 
 // NewS3Config は環境変数等から設定を組み立てて返す
@@ -15,6 +18,31 @@ func NewS3Config() *S3Config {
 		AccountID:       getEnv("AWS_ACCOUNT_ID", ""),
 		AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
 		AccessKeySecret: getEnv("AWS_ACCESS_KEY_SECRET", ""),
-		S3Endpoint:      getEnv("AWS_ENDPOINT", "http://localhost:9000"),
+		S3Endpoint:      getEnv("AWS_ENDPOINT", "https://s3.isk01.sakurastorage.jp"),
+		Region:          getEnv("AWS_REGION", "jp-north-1"),
+		UsePathStyle:    getEnv("AWS_S3_USE_PATH_STYLE", "false") == "true",
+	}
+}
+
+// StorageConfig はサウンド等のファイルの保存先の設定
+type StorageConfig struct {
+	// Backend は保存先 ("s3" / "local" / "memory")
+	Backend string
+	S3      *S3Config
+	// LocalDir は Backend が "local" の場合にファイルを置くディレクトリ
+	LocalDir string
+	// PublicBaseURL は "local" / "memory" の場合にファイルを配信する URL の接頭辞 (例: http://localhost:8080/api)
+	PublicBaseURL string
+	// SigningSecret は配信用 URL の署名に使う鍵。空の場合は起動ごとにランダムに生成する
+	SigningSecret string
+}
+
+func NewStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Backend:       getEnv("STORAGE_BACKEND", "s3"),
+		S3:            NewS3Config(),
+		LocalDir:      getEnv("STORAGE_LOCAL_DIR", "./data/blobs"),
+		PublicBaseURL: getEnv("STORAGE_PUBLIC_BASE_URL", "http://localhost:8080/api"),
+		SigningSecret: getEnv("STORAGE_SIGNING_SECRET", ""),
 	}
 }
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pikachu0310/livekit-server/internal/pkg/audio"
+)
+
+// SoundProcessingOptions はアップロードされたサウンドの加工方法を返す
+func SoundProcessingOptions() audio.Options {
+	opts := audio.DefaultOptions()
+	if value := getEnv("SOUND_TARGET_LUFS", ""); value != "" {
+		lufs, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			fmt.Println("Invalid SOUND_TARGET_LUFS, using default: " + value)
+		} else {
+			opts.TargetLUFS = lufs
+		}
+	}
+	opts.TrimSilence = getEnv("SOUND_TRIM_SILENCE", "true") == "true"
+	return opts
+}
+
+// SoundUploadMaxBytes はアップロードできる音声ファイルのサイズの上限 (バイト) を返す
+func SoundUploadMaxBytes() int64 {
+	value := getEnv("SOUND_UPLOAD_MAX_BYTES", "10485760")
+	maxBytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || maxBytes <= 0 {
+		fmt.Println("Invalid SOUND_UPLOAD_MAX_BYTES, using default 10MiB: " + value)
+		return 10 << 20
+	}
+	return maxBytes
+}
+
+// SoundUploadExpiry はアップロード用の署名付き URL の有効期間を返す。
+// この期間内に完了しなかったアップロードはスイーパーが削除する。
+func SoundUploadExpiry() time.Duration {
+	value := getEnv("SOUND_UPLOAD_EXPIRY", "15m")
+	expiry, err := time.ParseDuration(value)
+	if err != nil || expiry <= 0 {
+		fmt.Println("Invalid SOUND_UPLOAD_EXPIRY, using default 15m: " + value)
+		return 15 * time.Minute
+	}
+	return expiry
+}
+
+// SoundUploadSweepInterval は期限切れのアップロードを掃除する間隔を返す。
+// "0" もしくは "off" を指定すると掃除を行わない (0 を返す)。
+func SoundUploadSweepInterval() time.Duration {
+	value := getEnv("SOUND_UPLOAD_SWEEP_INTERVAL", "5m")
+	if value == "off" || value == "0" {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		fmt.Println("Invalid SOUND_UPLOAD_SWEEP_INTERVAL, using default 5m: " + value)
+		return 5 * time.Minute
+	}
+	return interval
+}
+
+// SoundboardQueuePolicy はルームごとに設定されていない場合のキューポリシー (overlap / queue / replace) を返す
+func SoundboardQueuePolicy() string {
+	return getEnv("SOUNDBOARD_QUEUE_POLICY", "overlap")
+}
+
+// SoundboardPlayback はサウンドの再生方法を返す。
+// "ingress" は LiveKit Ingress を使い、"native" はサーバがボットとしてルームに参加して再生する (-tags opus でのビルドが必要)。
+func SoundboardPlayback() string {
+	return getEnv("SOUNDBOARD_PLAYBACK", "ingress")
+}
+
+// RateLimit はトークンバケットによる回数制限の設定。Burst 回まで続けて許可し、Cooldown ごとに1回分回復する
+type RateLimit struct {
+	Burst    int
+	Cooldown time.Duration
+}
+
+// SoundboardUserRateLimit はユーザごとのサウンドの再生回数の制限を返す (SOUNDBOARD_USER_BURST=0 で無効)
+func SoundboardUserRateLimit() RateLimit {
+	return rateLimit("SOUNDBOARD_USER", RateLimit{Burst: 3, Cooldown: 5 * time.Second})
+}
+
+// SoundboardRoomRateLimit はルームごとのサウンドの再生回数の制限を返す (SOUNDBOARD_ROOM_BURST=0 で無効)
+func SoundboardRoomRateLimit() RateLimit {
+	return rateLimit("SOUNDBOARD_ROOM", RateLimit{Burst: 10, Cooldown: 2 * time.Second})
+}
+
+// rateLimit は <prefix>_BURST と <prefix>_COOLDOWN から回数制限の設定を読み込む
+func rateLimit(prefix string, defaults RateLimit) RateLimit {
+	limit := defaults
+	if value := getEnv(prefix+"_BURST", ""); value != "" {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 0 {
+			fmt.Printf("Invalid %s_BURST, using default %d: %s\n", prefix, defaults.Burst, value)
+		} else {
+			limit.Burst = burst
+		}
+	}
+	if value := getEnv(prefix+"_COOLDOWN", ""); value != "" {
+		cooldown, err := time.ParseDuration(value)
+		if err != nil || cooldown < 0 {
+			fmt.Printf("Invalid %s_COOLDOWN, using default %s: %s\n", prefix, defaults.Cooldown, value)
+		} else {
+			limit.Cooldown = cooldown
+		}
+	}
+	return limit
+}
+
+// SoundQuota はサウンドの登録数・サイズの上限。0 は無制限
+type SoundQuota struct {
+	// MaxSoundsPerUser はユーザごとのサウンドの数の上限
+	MaxSoundsPerUser int64
+	// MaxBytesPerUser はユーザごとの保存しているファイル (加工後・元・試聴用) の合計サイズ (バイト) の上限
+	MaxBytesPerUser int64
+	// MaxTotalBytes は全てのサウンドの保存しているファイルの合計サイズ (バイト) の上限
+	MaxTotalBytes int64
+}
+
+// SoundboardQuota はサウンドの登録数・サイズの上限を返す。
+// SOUNDBOARD_MAX_SOUNDS_PER_USER, SOUNDBOARD_MAX_BYTES_PER_USER, SOUNDBOARD_MAX_TOTAL_BYTES で指定し、0 で無制限にする。
+func SoundboardQuota() SoundQuota {
+	return SoundQuota{
+		MaxSoundsPerUser: quotaLimit("SOUNDBOARD_MAX_SOUNDS_PER_USER", 100),
+		MaxBytesPerUser:  quotaLimit("SOUNDBOARD_MAX_BYTES_PER_USER", 100<<20),
+		MaxTotalBytes:    quotaLimit("SOUNDBOARD_MAX_TOTAL_BYTES", 0),
+	}
+}
+
+// quotaLimit は key から 0 以上の値を読み込む
+func quotaLimit(key string, defaultValue int64) int64 {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit < 0 {
+		fmt.Printf("Invalid %s, using default %d: %s\n", key, defaultValue, value)
+		return defaultValue
+	}
+	return limit
+}
+
+// SoundboardReportThreshold はサウンドを自動で非表示にする未対応の通報の数を返す (SOUNDBOARD_REPORT_THRESHOLD=0 で自動では非表示にしない)
+func SoundboardReportThreshold() int64 {
+	return quotaLimit("SOUNDBOARD_REPORT_THRESHOLD", 3)
+}
+
+// SoundboardReportNotifyFirst は未対応の通報が無かったサウンドが通報された時にも管理者へ通知するかを返す
+// (SOUNDBOARD_REPORT_NOTIFY_FIRST=false で非表示になった時だけ通知する)
+func SoundboardReportNotifyFirst() bool {
+	return getEnv("SOUNDBOARD_REPORT_NOTIFY_FIRST", "true") != "false"
+}
+
+// SoundboardModerationChannelID はサウンドが通報された時や、通報により非表示になった時に管理者へ通知する traQ のチャンネルを返す。
+// 指定されていない場合は通常の通知チャンネル (TRAQ_NOTIFICATION_CHANNEL_ID) に送る。
+func SoundboardModerationChannelID() string {
+	return getEnv("SOUNDBOARD_MODERATION_CHANNEL_ID", getEnv("TRAQ_NOTIFICATION_CHANNEL_ID", ""))
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Prometheus のテキスト形式で公開するカウンタ類
+var (
+	// WsConnections は接続中の WebSocket クライアント数
+	WsConnections atomic.Int64
+	// WsMessagesSent は WebSocket クライアントへ送ったメッセージ数
+	WsMessagesSent atomic.Int64
+	// WsMessagesDropped は送信キューが溢れて捨てたメッセージ数
+	WsMessagesDropped atomic.Int64
+	// WsSlowConsumerDisconnects は送信キューが溢れて切断したクライアント数
+	WsSlowConsumerDisconnects atomic.Int64
+	// SseConnections は接続中の SSE クライアント数
+	SseConnections atomic.Int64
+	// SseMessagesSent は SSE クライアントへ送ったイベント数
+	SseMessagesSent atomic.Int64
+	// SseMessagesDropped は送信キューが溢れて捨てたイベント数
+	SseMessagesDropped atomic.Int64
+	// SseSlowConsumerDisconnects は送信キューが溢れて切断した SSE クライアント数
+	SseSlowConsumerDisconnects atomic.Int64
+)
+
+type metric struct {
+	name       string
+	help       string
+	metricType string
+	value      *atomic.Int64
+}
+
+func all() []metric {
+	return []metric{
+		{"qall_ws_connections", "Number of connected WebSocket clients.", "gauge", &WsConnections},
+		{"qall_ws_messages_sent_total", "Number of messages sent to WebSocket clients.", "counter", &WsMessagesSent},
+		{"qall_ws_messages_dropped_total", "Number of messages dropped because a client's send queue was full.", "counter", &WsMessagesDropped},
+		{"qall_ws_slow_consumer_disconnects_total", "Number of WebSocket clients disconnected for being too slow.", "counter", &WsSlowConsumerDisconnects},
+		{"qall_sse_connections", "Number of connected Server-Sent Events clients.", "gauge", &SseConnections},
+		{"qall_sse_messages_sent_total", "Number of events sent to Server-Sent Events clients.", "counter", &SseMessagesSent},
+		{"qall_sse_messages_dropped_total", "Number of events dropped because an SSE client's send queue was full.", "counter", &SseMessagesDropped},
+		{"qall_sse_slow_consumer_disconnects_total", "Number of SSE clients disconnected for being too slow.", "counter", &SseSlowConsumerDisconnects},
+	}
+}
+
+// WritePrometheus は全メトリクスを Prometheus のテキスト形式で書き出す
+func WritePrometheus(w io.Writer) error {
+	for _, m := range all() {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", m.name, m.help, m.name, m.metricType, m.name, m.value.Load()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,14 +14,29 @@ func AuthTraQMiddlewareWithPathSkipper(next echo.HandlerFunc) echo.HandlerFunc {
 			"/api/ping":    true,
 			"/api/webhook": true,
 			"/api/rooms":   true,
-			"/api/ws":      true,
+			// 署名付き URL で認証する (LiveKit の Ingress から取得される)
+			"/api/files/:key": true,
 		}
 		if skipPaths[c.Path()] {
 			return next(c)
 		}
+		// メトリクスは Prometheus 用のトークン (METRICS_TOKEN) でも取得できる
+		if c.Path() == "/api/metrics" && util.IsMetricsToken(c) {
+			return next(c)
+		}
+
+		// ヘッダを付けられない WebSocket / EventSource はクエリ・サブプロトコルのトークンも受け付ける
+		streamPaths := map[string]bool{
+			"/api/ws":           true,
+			"/api/rooms/stream": true,
+		}
+		authenticate := util.AuthTraQClient
+		if streamPaths[c.Path()] {
+			authenticate = util.AuthTraQStreamClient
+		}
 
-		// AuthTraQClient で Bearerトークンを検証し、ユーザ名を取得
-		userName, err := util.AuthTraQClient(c)
+		// Bearerトークンを検証し、ユーザ名を取得
+		userName, err := authenticate(c)
 		if err != nil {
 			// HTTPError が返ってきた場合はそのまま返す
 			return err
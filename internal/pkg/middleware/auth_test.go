@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestMetricsAuth は /api/metrics が METRICS_TOKEN か traQ のトークン無しでは取得できないことを確認する
+func TestMetricsAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		metricsToken  string
+		authorization string
+		want          int
+	}{
+		{"no token configured, no header", "", "", http.StatusUnauthorized},
+		{"no token configured, any bearer", "", "Bearer secret", http.StatusUnauthorized},
+		{"token configured, no header", "secret", "", http.StatusUnauthorized},
+		{"token configured, wrong token", "secret", "Bearer wrong", http.StatusUnauthorized},
+		{"token configured, matching token", "secret", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("METRICS_TOKEN", tt.metricsToken)
+
+			e := echo.New()
+			e.Use(AuthTraQMiddlewareWithPathSkipper)
+			e.GET("/api/metrics", func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
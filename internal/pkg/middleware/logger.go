@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// redactedQueryParams はログに値を残さないクエリパラメータ
+var redactedQueryParams = []string{"access_token"}
+
+// Logger は echo の標準のリクエストログと同じ形式で、URI のトークンを伏せて記録するミドルウェア。
+// WebSocket / イベントストリームはクエリパラメータ access_token でもトークンを受け付けるため、そのままではログにトークンが残る。
+var Logger = middleware.LoggerWithConfig(middleware.LoggerConfig{
+	Format: strings.Replace(middleware.DefaultLoggerConfig.Format, "${uri}", "${custom}", 1),
+	CustomTagFunc: func(c echo.Context, buf *bytes.Buffer) (int, error) {
+		return buf.WriteString(RedactURI(c.Request().RequestURI))
+	},
+})
+
+// RedactURI は URI のクエリのうち redactedQueryParams の値を "REDACTED" に置き換える。
+// 他のパラメータは順序も含めてそのまま残す。
+func RedactURI(uri string) string {
+	path, query, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		for _, redacted := range redactedQueryParams {
+			if key == redacted {
+				params[i] = key + "=REDACTED"
+			}
+		}
+	}
+	return path + "?" + strings.Join(params, "&")
+}
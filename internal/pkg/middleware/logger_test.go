@@ -0,0 +1,23 @@
+package middleware
+
+import "testing"
+
+func TestRedactURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"/api/ping", "/api/ping"},
+		{"/api/ws?global=true", "/api/ws?global=true"},
+		{"/api/ws?access_token=eyJhbGciOi.abc.def", "/api/ws?access_token=REDACTED"},
+		{"/api/rooms/stream?rooms=a&access_token=secret&global=true", "/api/rooms/stream?rooms=a&access_token=REDACTED&global=true"},
+		{"/api/ws?access_token=a&access_token=b", "/api/ws?access_token=REDACTED&access_token=REDACTED"},
+		{"/api/ws?access_token", "/api/ws?access_token=REDACTED"},
+		{"/api/ws?my_access_token=x", "/api/ws?my_access_token=x"},
+	}
+	for _, tt := range tests {
+		if got := RedactURI(tt.uri); got != tt.want {
+			t.Errorf("RedactURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
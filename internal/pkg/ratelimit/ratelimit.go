@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneInterval は満タンに戻ったバケツを掃除する間隔
+const pruneInterval = time.Minute
+
+// Limiter はキー (ユーザ・ルーム等) ごとのトークンバケット。
+// 最大 Burst 回まで続けて許可し、その後は Cooldown ごとに1回分ずつ回復する。
+type Limiter struct {
+	mu        sync.Mutex
+	burst     float64
+	cooldown  time.Duration
+	buckets   map[string]*bucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// New は Limiter を作る。burst が 0 以下の場合は常に許可する
+func New(burst int, cooldown time.Duration) *Limiter {
+	return &Limiter{
+		burst:    float64(burst),
+		cooldown: cooldown,
+		buckets:  make(map[string]*bucket),
+		now:      time.Now,
+	}
+}
+
+// Allow は key のトークンを1つ使う。足りない場合は使わずに false と次に許可されるまでの時間を返す
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l.burst <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.pruneLocked(now)
+	b := l.refillLocked(key, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) * float64(l.cooldown))
+}
+
+// Refund は Allow で使ったトークンを1つ返す (他の制限に引っかかって実行しなかった場合等)
+func (l *Limiter) Refund(key string) {
+	if l.burst <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refillLocked(key, l.now())
+	b.tokens = min(l.burst, b.tokens+1)
+}
+
+// refillLocked は最後に使ってからの経過時間分だけトークンを回復したバケツを返す
+func (l *Limiter) refillLocked(key string, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+		return b
+	}
+	if l.cooldown <= 0 {
+		b.tokens = l.burst
+	} else if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+float64(elapsed)/float64(l.cooldown))
+	}
+	b.updated = now
+	return b
+}
+
+// pruneLocked は満タンに戻ったバケツを消して、キーが増え続けないようにする
+func (l *Limiter) pruneLocked(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if l.cooldown <= 0 || b.tokens+float64(now.Sub(b.updated))/float64(l.cooldown) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
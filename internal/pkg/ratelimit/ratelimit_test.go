@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock は Limiter に注入する時計
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestLimiter(burst int, cooldown time.Duration) (*Limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(burst, cooldown)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestLimiter(t *testing.T) {
+	// step は時計を advance 進めてから key に対して操作する
+	type step struct {
+		advance time.Duration
+		key     string
+		refund  bool
+		// Allow の結果 (refund の場合は無視)
+		allowed    bool
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name     string
+		burst    int
+		cooldown time.Duration
+		steps    []step
+	}{
+		{
+			name:     "burst then reject",
+			burst:    3,
+			cooldown: 10 * time.Second,
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: 10 * time.Second},
+				{advance: 4 * time.Second, key: "a", allowed: false, retryAfter: 6 * time.Second},
+			},
+		},
+		{
+			name:     "refill one token per cooldown",
+			burst:    2,
+			cooldown: 10 * time.Second,
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{advance: 10 * time.Second, key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: 10 * time.Second},
+				{advance: 5 * time.Second, key: "a", allowed: false, retryAfter: 5 * time.Second},
+				{advance: 5 * time.Second, key: "a", allowed: true},
+			},
+		},
+		{
+			name:     "refill does not exceed burst",
+			burst:    2,
+			cooldown: time.Second,
+			steps: []step{
+				{key: "a", allowed: true},
+				{advance: time.Hour, key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Second},
+			},
+		},
+		{
+			name:     "keys are independent",
+			burst:    1,
+			cooldown: time.Minute,
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+				{key: "b", allowed: true},
+				{key: "b", allowed: false, retryAfter: time.Minute},
+			},
+		},
+		{
+			name:     "refund returns a token",
+			burst:    1,
+			cooldown: time.Minute,
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", refund: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+			},
+		},
+		{
+			name:     "refund does not exceed burst",
+			burst:    2,
+			cooldown: time.Minute,
+			steps: []step{
+				{key: "a", refund: true},
+				{key: "a", refund: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+			},
+		},
+		{
+			name:     "partial refill plus refund",
+			burst:    1,
+			cooldown: 10 * time.Second,
+			steps: []step{
+				{key: "a", allowed: true},
+				{advance: 5 * time.Second, key: "a", refund: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: 10 * time.Second},
+			},
+		},
+		{
+			name:     "zero burst always allows",
+			burst:    0,
+			cooldown: time.Minute,
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", refund: true},
+				{key: "a", allowed: true},
+			},
+		},
+		{
+			name:     "zero cooldown refills immediately",
+			burst:    1,
+			cooldown: 0,
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, clock := newTestLimiter(tt.burst, tt.cooldown)
+			for i, s := range tt.steps {
+				clock.t = clock.t.Add(s.advance)
+				if s.refund {
+					l.Refund(s.key)
+					continue
+				}
+				allowed, retryAfter := l.Allow(s.key)
+				if allowed != s.allowed || retryAfter != s.retryAfter {
+					t.Errorf("step %d: Allow(%q) = %v, %v, want %v, %v", i, s.key, allowed, retryAfter, s.allowed, s.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+// TestLimiterPrune は満タンに戻ったバケツだけが掃除されることを確認する
+func TestLimiterPrune(t *testing.T) {
+	l, clock := newTestLimiter(2, time.Minute)
+	l.Allow("full")
+	l.Allow("empty")
+	l.Allow("empty")
+
+	// full は1分で満タンに戻るが、empty は2分かかる
+	clock.t = clock.t.Add(pruneInterval + time.Second)
+	l.Allow("other")
+	if _, ok := l.buckets["full"]; ok {
+		t.Error("refilled bucket was not pruned")
+	}
+	if _, ok := l.buckets["empty"]; !ok {
+		t.Error("bucket that is not refilled yet was pruned")
+	}
+	if allowed, retryAfter := l.Allow("empty"); !allowed || retryAfter != 0 {
+		t.Errorf("Allow(empty) = %v, %v, want true, 0", allowed, retryAfter)
+	}
+}
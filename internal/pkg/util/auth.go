@@ -1,21 +1,26 @@
 package util
 
 import (
-	"crypto/ecdsa"
-	"crypto/x509"
-	"encoding/pem"
+	"context"
+	"crypto/subtle"
 	"fmt"
+	"github.com/go-jose/go-jose/v3"
 	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
 	"net/http"
+	"strings"
 	"time"
 )
 
-// サンプルの公開鍵(本番では適切に管理)
+// 鍵を設定しなかった場合に使う本番の公開鍵 (TRAQ_JWKS_URL / TRAQ_PUBLIC_KEYS で差し替えられる)
 const publicKeyPEM = `-----BEGIN PUBLIC KEY-----
 MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAErNkbjzyMz81Np8sBb8Jr3bUOkLW4
 H41Ugac0eSzPyemDvmaCIDpRofi3Rb0EgaSRSqC3IoBgVmQ+bPLtueUtUg==
 -----END PUBLIC KEY-----`
+
+// 開発用の公開鍵 (AUTH_DEV_MODE=true の場合のみ受け付ける)
 const devPublicKeyPEM = `-----BEGIN PUBLIC KEY-----
 MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEsif3xPZ/ObY12BCB2SfC3045eSkq
 G9Kw2nD2DYgoJHFCPTzCLUqOKDpig4H0tYXH4RaSy6+apfgfeE/TJagHuw==
@@ -30,12 +35,111 @@ func GetTraqUserID(c echo.Context) (string, error) {
 	return userName, nil
 }
 
+// tokenLeeway は exp / nbf の検証で許容する traQ とのずれ
+const tokenLeeway = 30 * time.Second
+
+var (
+	traQKeyProvider KeyProvider = mustNewStaticKeyProvider(publicKeyPEM)
+	traQExpected    jwt.Expected
+)
+
+// SetTraQAuth はトークン検証に使う鍵と、検証する iss / aud を設定する。
+// iss / aud が未設定でも既存の環境が起動できるよう、警告を出して検証せずに続ける
+func SetTraQAuth(cfg *config.AuthConfig) error {
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("WARNING: %v: tokens are accepted regardless of their iss / aud\n", err)
+	}
+	provider, err := NewTraQKeyProvider(cfg)
+	if err != nil {
+		return err
+	}
+	traQKeyProvider = provider
+	// 空の iss / aud は検証せず、空でなければ検証する
+	traQExpected = jwt.Expected{Issuer: cfg.Issuer}
+	if cfg.Audience != "" {
+		traQExpected.Audience = jwt.Audience{cfg.Audience}
+	}
+	return nil
+}
+
+func mustNewStaticKeyProvider(pemData string) KeyProvider {
+	p, err := NewStaticKeyProvider(pemData)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
 func AuthTraQClient(c echo.Context) (string, *echo.HTTPError) {
 	authHeader := c.Request().Header.Get("Authorization")
 	if authHeader == "" {
 		return "", echo.NewHTTPError(http.StatusUnauthorized, "Authorization header is required")
 	}
-	tokenString := authHeader[len("Bearer "):]
+	tokenString, ok := parseBearerToken(authHeader)
+	if !ok {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "Authorization header must be in the form \"Bearer {token}\"")
+	}
+	return verifyTraQToken(c.Request().Context(), tokenString)
+}
+
+// IsMetricsToken は Authorization ヘッダのトークンが METRICS_TOKEN と一致するかを返す。METRICS_TOKEN が空なら常に false
+func IsMetricsToken(c echo.Context) bool {
+	expected := config.MetricsToken()
+	if expected == "" {
+		return false
+	}
+	token, ok := parseBearerToken(c.Request().Header.Get("Authorization"))
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// parseBearerToken は "Bearer {token}" 形式のヘッダからトークンを取り出す (スキーム名は大文字小文字を区別しない)
+func parseBearerToken(authHeader string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authHeader), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", false
+	}
+	return token, true
+}
+
+// wsBearerProtocolPrefix は WebSocket のサブプロトコルでトークンを渡す際の接頭辞 (例: "bearer.eyJhbGciOi...")
+const wsBearerProtocolPrefix = "bearer."
+
+// AuthTraQStreamClient は WebSocket / イベントストリーム用の認証を行う。
+// ブラウザはこれらの接続にヘッダを付けられないため、Authorization ヘッダに加えて
+// クエリパラメータ access_token と Sec-WebSocket-Protocol の "bearer.{token}" も受け付ける。
+// WebSocket では URL がログに残らないサブプロトコルを推奨し、access_token はアクセスログで伏せる (middleware.Logger)。
+func AuthTraQStreamClient(c echo.Context) (string, *echo.HTTPError) {
+	if c.Request().Header.Get("Authorization") != "" {
+		return AuthTraQClient(c)
+	}
+
+	tokenString := c.QueryParam("access_token")
+	if tokenString == "" {
+		for _, protocol := range websocket.Subprotocols(c.Request()) {
+			if strings.HasPrefix(protocol, wsBearerProtocolPrefix) {
+				tokenString = strings.TrimPrefix(protocol, wsBearerProtocolPrefix)
+				break
+			}
+		}
+	}
+	if tokenString == "" {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "access token is required")
+	}
+	return verifyTraQToken(c.Request().Context(), tokenString)
+}
+
+// traQClaims は traQ のトークンのクレーム
+type traQClaims struct {
+	jwt.Claims
+	Name string `json:"name"`
+}
+
+// verifyTraQToken は traQ の ES256 トークンを検証し、name クレームを返す
+func verifyTraQToken(ctx context.Context, tokenString string) (string, *echo.HTTPError) {
 	parsedToken, err := jwt.ParseSigned(tokenString)
 	if err != nil {
 		return "", echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
@@ -46,55 +150,46 @@ func AuthTraQClient(c echo.Context) (string, *echo.HTTPError) {
 		return "", echo.NewHTTPError(http.StatusUnauthorized, "Invalid token algorithm")
 	}
 
-	// 4) 署名検証 (本番key / dev key)
-	var claims map[string]interface{}
-	if err := verifyWithECDSA(parsedToken, publicKeyPEM, devPublicKeyPEM, &claims); err != nil {
+	// 4) 署名検証 (kid に対応する鍵)
+	keys, err := traQKeyProvider.Keys(ctx, parsedToken.Headers[0].KeyID)
+	if err != nil {
+		fmt.Printf("Failed to get public keys: %v\n", err)
+		return "", echo.NewHTTPError(http.StatusServiceUnavailable, "Failed to get public keys")
+	}
+	var claims traQClaims
+	if err := verifyWithKeys(parsedToken, keys, &claims); err != nil {
 		return "", echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 	}
 
-	// 5) exp と name クレームをチェック
-	exp, ok := claims["exp"].(float64)
-	if !ok {
+	// 5) exp / nbf / iss / aud と name クレームをチェック
+	if claims.Expiry == nil {
 		return "", echo.NewHTTPError(http.StatusUnauthorized, "Token missing expiration")
 	}
-	if time.Unix(int64(exp), 0).Before(time.Now()) {
-		return "", echo.NewHTTPError(http.StatusUnauthorized, "Token has expired")
+	if err := claims.ValidateWithLeeway(traQExpected.WithTime(time.Now()), tokenLeeway); err != nil {
+		switch err {
+		case jwt.ErrExpired:
+			return "", echo.NewHTTPError(http.StatusUnauthorized, "Token has expired")
+		case jwt.ErrNotValidYet:
+			return "", echo.NewHTTPError(http.StatusUnauthorized, "Token is not valid yet")
+		default:
+			return "", echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
 	}
-	name, ok := claims["name"].(string)
-	if !ok || name == "" {
+	if claims.Name == "" {
 		return "", echo.NewHTTPError(http.StatusBadRequest, "name claim is required in JWT")
 	}
-	return name, nil
-}
-
-// verifyWithECDSA は ECDSA 公開鍵2種類(本番鍵 / 開発用鍵)で検証を試みるユーティリティ
-func verifyWithECDSA(parsedToken *jwt.JSONWebToken, primaryKey, devKey string, claims interface{}) error {
-	// 1) primary key
-	if err := verifyECDSA(parsedToken, primaryKey, claims); err == nil {
-		return nil // 成功
-	}
-	// 2) dev key
-	if err := verifyECDSA(parsedToken, devKey, claims); err == nil {
-		return nil
-	}
-	return fmt.Errorf("failed to verify with both primary & dev public key")
+	return claims.Name, nil
 }
 
-func verifyECDSA(parsedToken *jwt.JSONWebToken, keyPEM string, claims interface{}) error {
-	block, _ := pem.Decode([]byte(keyPEM))
-	if block == nil {
-		return fmt.Errorf("failed to decode PEM block")
+// verifyWithKeys は候補の公開鍵で順に署名の検証を試みる
+func verifyWithKeys(parsedToken *jwt.JSONWebToken, keys []jose.JSONWebKey, claims interface{}) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no public key matches the token")
 	}
-	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
-	if err != nil {
-		return fmt.Errorf("failed to parse PKIX public key: %v", err)
-	}
-	ecdsaPubKey, ok := pubKey.(*ecdsa.PublicKey)
-	if !ok {
-		return fmt.Errorf("not an ECDSA public key")
+	for _, key := range keys {
+		if err := parsedToken.Claims(key.Key, claims); err == nil {
+			return nil
+		}
 	}
-	if err := parsedToken.Claims(ecdsaPubKey, claims); err != nil {
-		return err
-	}
-	return nil
+	return fmt.Errorf("failed to verify token signature")
 }
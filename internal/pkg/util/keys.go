@@ -0,0 +1,208 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+)
+
+// jwksMinRefreshInterval は未知の kid を理由に JWKS を取り直す最短の間隔 (不正なトークンで取得を繰り返させないため)
+const jwksMinRefreshInterval = time.Minute
+
+// KeyProvider は JWT の署名検証に使う公開鍵を返す
+type KeyProvider interface {
+	// Keys は kid に対応する鍵を返す。kid が空の場合は全ての鍵を返す
+	Keys(ctx context.Context, kid string) ([]jose.JSONWebKey, error)
+}
+
+// NewTraQKeyProvider は設定に従って traQ のトークン検証用の KeyProvider を作る。
+// JWKS の URL・公開鍵ファイル・公開鍵のいずれも指定されていない場合は組み込みの本番鍵を使う。
+// 開発用の鍵は DevMode の場合のみ追加する。
+func NewTraQKeyProvider(cfg *config.AuthConfig) (KeyProvider, error) {
+	providers := make(multiKeyProvider, 0)
+
+	if cfg.JWKSURL != "" {
+		providers = append(providers, NewJWKSKeyProvider(cfg.JWKSURL, cfg.JWKSCacheTTL))
+	}
+	if cfg.PublicKeysFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("read public keys file: %w", err)
+		}
+		p, err := NewStaticKeyProvider(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("load public keys file: %w", err)
+		}
+		providers = append(providers, p)
+	}
+	if cfg.PublicKeys != "" {
+		p, err := NewStaticKeyProvider(cfg.PublicKeys)
+		if err != nil {
+			return nil, fmt.Errorf("load public keys: %w", err)
+		}
+		providers = append(providers, p)
+	}
+	if len(providers) == 0 {
+		p, err := NewStaticKeyProvider(publicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	if cfg.DevMode {
+		p, err := NewStaticKeyProvider(devPublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// StaticKeyProvider は起動時に読み込んだ PEM 形式の公開鍵を返す
+type StaticKeyProvider struct {
+	keys []jose.JSONWebKey
+}
+
+// NewStaticKeyProvider は PEM 形式の ECDSA 公開鍵 (複数ブロック可) から KeyProvider を作る
+func NewStaticKeyProvider(pemData string) (*StaticKeyProvider, error) {
+	keys := make([]jose.JSONWebKey, 0)
+	rest := []byte(pemData)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKIX public key: %w", err)
+		}
+		ecdsaPubKey, ok := pubKey.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("not an ECDSA public key")
+		}
+		keys = append(keys, jose.JSONWebKey{
+			Key:       ecdsaPubKey,
+			KeyID:     block.Headers["kid"],
+			Algorithm: "ES256",
+			Use:       "sig",
+		})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	return &StaticKeyProvider{keys: keys}, nil
+}
+
+// Keys は kid が一致する鍵と kid の無い鍵を返す
+func (p *StaticKeyProvider) Keys(_ context.Context, kid string) ([]jose.JSONWebKey, error) {
+	if kid == "" {
+		return p.keys, nil
+	}
+	keys := make([]jose.JSONWebKey, 0, len(p.keys))
+	for _, key := range p.keys {
+		if key.KeyID == "" || key.KeyID == kid {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// JWKSKeyProvider は JWKS の URL から公開鍵を取得し、一定期間キャッシュする。
+// キャッシュに無い kid が来た場合は鍵がローテーションされたとみなして取り直す。
+type JWKSKeyProvider struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	set       *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+func NewJWKSKeyProvider(url string, ttl time.Duration) *JWKSKeyProvider {
+	return &JWKSKeyProvider{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *JWKSKeyProvider) Keys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	expired := p.set == nil || time.Since(p.fetchedAt) > p.ttl
+	unknownKid := p.set != nil && kid != "" && len(p.set.Key(kid)) == 0 && time.Since(p.fetchedAt) > jwksMinRefreshInterval
+	if expired || unknownKid {
+		set, err := p.fetch(ctx)
+		if err != nil {
+			// 取得に失敗しても、期限切れのキャッシュがあればそれで検証を続ける
+			if p.set == nil {
+				return nil, err
+			}
+			fmt.Printf("Failed to refresh JWKS, using cached keys: %v\n", err)
+		} else {
+			p.set = set
+			p.fetchedAt = time.Now()
+		}
+	}
+
+	if kid == "" {
+		return p.set.Keys, nil
+	}
+	return p.set.Key(kid), nil
+}
+
+func (p *JWKSKeyProvider) fetch(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create JWKS request: %w", err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS: unexpected status %d", res.StatusCode)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode JWKS: %w", err)
+	}
+	return &set, nil
+}
+
+// multiKeyProvider は複数の KeyProvider の鍵をまとめて返す
+type multiKeyProvider []KeyProvider
+
+func (m multiKeyProvider) Keys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	keys := make([]jose.JSONWebKey, 0)
+	var lastErr error
+	for _, p := range m {
+		k, err := p.Keys(ctx, kid)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		keys = append(keys, k...)
+	}
+	if len(keys) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return keys, nil
+}
@@ -0,0 +1,373 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+)
+
+const (
+	testIssuer   = "https://q.example.com"
+	testAudience = "qall"
+)
+
+// testKey は kid 付きの ES256 の鍵
+type testKey struct {
+	kid  string
+	priv *ecdsa.PrivateKey
+}
+
+func newTestKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, priv: priv}
+}
+
+func (k testKey) jwk() jose.JSONWebKey {
+	return jose.JSONWebKey{Key: &k.priv.PublicKey, KeyID: k.kid, Algorithm: "ES256", Use: "sig"}
+}
+
+// sign は kid ヘッダを付けて claims に署名する
+func (k testKey) sign(t *testing.T, kid string, claims any) string {
+	t.Helper()
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		opts = opts.WithHeader("kid", kid)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: k.priv}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// jwksServer は差し替え可能な JWKS を返し、取得された回数を数えるテスト用のサーバ
+type jwksServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	keys     []jose.JSONWebKey
+	status   int
+	requests int
+}
+
+func newJWKSServer(t *testing.T, keys ...testKey) *jwksServer {
+	t.Helper()
+	s := &jwksServer{status: http.StatusOK}
+	s.setKeys(keys...)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		if s.status != http.StatusOK {
+			w.WriteHeader(s.status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...testKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = make([]jose.JSONWebKey, 0, len(keys))
+	for _, key := range keys {
+		s.keys = append(s.keys, key.jwk())
+	}
+}
+
+func (s *jwksServer) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *jwksServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func keyIDs(keys []jose.JSONWebKey) []string {
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, key.KeyID)
+	}
+	return ids
+}
+
+// ageCache は取得済みの JWKS を d だけ前に取得したことにする
+func ageCache(p *JWKSKeyProvider, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetchedAt = p.fetchedAt.Add(-d)
+}
+
+func TestJWKSKeyProviderCache(t *testing.T) {
+	k1, k2 := newTestKey(t, "k1"), newTestKey(t, "k2")
+	srv := newJWKSServer(t, k1)
+	p := NewJWKSKeyProvider(srv.URL, time.Hour)
+	ctx := context.Background()
+
+	keys, err := p.Keys(ctx, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := keyIDs(keys); len(ids) != 1 || ids[0] != "k1" {
+		t.Fatalf("Keys(k1) = %v, want [k1]", ids)
+	}
+	if _, err := p.Keys(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.requestCount(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (cached)", n)
+	}
+
+	// TTL が切れると取り直し、ローテーションされた鍵を使う
+	srv.setKeys(k1, k2)
+	ageCache(p, 2*time.Hour)
+	keys, err = p.Keys(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := keyIDs(keys); len(ids) != 2 {
+		t.Errorf("Keys(\"\") = %v, want [k1 k2]", ids)
+	}
+	if n := srv.requestCount(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2 (refreshed after TTL)", n)
+	}
+}
+
+func TestJWKSKeyProviderUnknownKid(t *testing.T) {
+	k1, k2 := newTestKey(t, "k1"), newTestKey(t, "k2")
+	srv := newJWKSServer(t, k1)
+	p := NewJWKSKeyProvider(srv.URL, time.Hour)
+	ctx := context.Background()
+
+	if _, err := p.Keys(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 取得した直後は未知の kid でも取り直さない
+	srv.setKeys(k1, k2)
+	keys, err := p.Keys(ctx, "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Keys(k2) = %v, want none before the refresh interval", keyIDs(keys))
+	}
+	if n := srv.requestCount(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+
+	// jwksMinRefreshInterval が過ぎていれば、TTL 内でも未知の kid で取り直す
+	ageCache(p, jwksMinRefreshInterval+time.Second)
+	keys, err = p.Keys(ctx, "k2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := keyIDs(keys); len(ids) != 1 || ids[0] != "k2" {
+		t.Errorf("Keys(k2) = %v, want [k2] after rotation", ids)
+	}
+
+	// 取り直しても見つからない kid は鍵を返さない
+	ageCache(p, jwksMinRefreshInterval+time.Second)
+	keys, err = p.Keys(ctx, "k3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Keys(k3) = %v, want none", keyIDs(keys))
+	}
+	if n := srv.requestCount(); n != 3 {
+		t.Errorf("JWKS fetched %d times, want 3", n)
+	}
+}
+
+func TestJWKSKeyProviderFetchError(t *testing.T) {
+	k1 := newTestKey(t, "k1")
+	srv := newJWKSServer(t, k1)
+	srv.setStatus(http.StatusInternalServerError)
+	p := NewJWKSKeyProvider(srv.URL, time.Hour)
+	ctx := context.Background()
+
+	// キャッシュが無ければエラー
+	if _, err := p.Keys(ctx, "k1"); err == nil {
+		t.Fatal("Keys succeeded without JWKS")
+	}
+
+	// 期限切れのキャッシュがあれば取得に失敗してもそれを使う
+	srv.setStatus(http.StatusOK)
+	if _, err := p.Keys(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	srv.setStatus(http.StatusInternalServerError)
+	ageCache(p, 2*time.Hour)
+	keys, err := p.Keys(ctx, "k1")
+	if err != nil {
+		t.Fatalf("Keys with stale cache: %v", err)
+	}
+	if ids := keyIDs(keys); len(ids) != 1 || ids[0] != "k1" {
+		t.Errorf("Keys(k1) = %v, want [k1] from the stale cache", ids)
+	}
+}
+
+// useTraQAuth はテストの間だけ traQ のトークン検証に JWKS サーバの鍵と iss / aud を使う
+func useTraQAuth(t *testing.T, jwksURL string) {
+	t.Helper()
+	prevProvider, prevExpected := traQKeyProvider, traQExpected
+	t.Cleanup(func() {
+		traQKeyProvider, traQExpected = prevProvider, prevExpected
+	})
+	if err := SetTraQAuth(&config.AuthConfig{
+		JWKSURL:      jwksURL,
+		JWKSCacheTTL: time.Hour,
+		Issuer:       testIssuer,
+		Audience:     testAudience,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyTraQTokenWithJWKS(t *testing.T) {
+	k1, k2, other := newTestKey(t, "k1"), newTestKey(t, "k2"), newTestKey(t, "k2")
+	srv := newJWKSServer(t, k1, k2)
+	useTraQAuth(t, srv.URL)
+
+	now := time.Now()
+	claims := func(modify func(c *traQClaims)) traQClaims {
+		c := traQClaims{
+			Claims: jwt.Claims{
+				Issuer:   testIssuer,
+				Audience: jwt.Audience{testAudience},
+				Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt: jwt.NewNumericDate(now),
+			},
+			Name: "traP",
+		}
+		if modify != nil {
+			modify(&c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"k1", k1.sign(t, "k1", claims(nil)), 0},
+		{"k2", k2.sign(t, "k2", claims(nil)), 0},
+		{"kid of another key", k1.sign(t, "k2", claims(nil)), http.StatusUnauthorized},
+		{"unknown signer with known kid", other.sign(t, "k2", claims(nil)), http.StatusUnauthorized},
+		{"unknown kid", k1.sign(t, "k3", claims(nil)), http.StatusUnauthorized},
+		{"wrong issuer", k1.sign(t, "k1", claims(func(c *traQClaims) { c.Issuer = "https://evil.example.com" })), http.StatusUnauthorized},
+		{"wrong audience", k1.sign(t, "k1", claims(func(c *traQClaims) { c.Audience = jwt.Audience{"other"} })), http.StatusUnauthorized},
+		{"missing audience", k1.sign(t, "k1", claims(func(c *traQClaims) { c.Audience = nil })), http.StatusUnauthorized},
+		{"expired", k1.sign(t, "k1", claims(func(c *traQClaims) { c.Expiry = jwt.NewNumericDate(now.Add(-time.Hour)) })), http.StatusUnauthorized},
+		{"no name", k1.sign(t, "k1", claims(func(c *traQClaims) { c.Name = "" })), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, httpErr := verifyTraQToken(context.Background(), tt.token)
+			if tt.status == 0 {
+				if httpErr != nil {
+					t.Fatalf("verifyTraQToken: %v", httpErr)
+				}
+				if name != "traP" {
+					t.Errorf("name = %q, want %q", name, "traP")
+				}
+				return
+			}
+			if httpErr == nil {
+				t.Fatalf("verifyTraQToken accepted the token (name %q)", name)
+			}
+			if httpErr.Code != tt.status {
+				t.Errorf("status = %d, want %d (%v)", httpErr.Code, tt.status, httpErr.Message)
+			}
+		})
+	}
+}
+
+// TestSetTraQAuthIssuerAndAudience は iss / aud が未設定でも (警告を出して) 起動でき、
+// DevMode でなければ Validate が未設定を報告することを確認する
+func TestSetTraQAuthIssuerAndAudience(t *testing.T) {
+	prevProvider, prevExpected := traQKeyProvider, traQExpected
+	t.Cleanup(func() {
+		traQKeyProvider, traQExpected = prevProvider, prevExpected
+	})
+
+	tests := []struct {
+		name        string
+		cfg         config.AuthConfig
+		wantInvalid bool
+	}{
+		{"both set", config.AuthConfig{Issuer: testIssuer, Audience: testAudience}, false},
+		{"no issuer", config.AuthConfig{Audience: testAudience}, true},
+		{"no audience", config.AuthConfig{Issuer: testIssuer}, true},
+		{"neither", config.AuthConfig{}, true},
+		{"dev mode", config.AuthConfig{DevMode: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantInvalid {
+				t.Errorf("Validate() error = %v, wantInvalid %v", err, tt.wantInvalid)
+			}
+			if err := SetTraQAuth(&tt.cfg); err != nil {
+				t.Errorf("SetTraQAuth() error = %v, want nil", err)
+			}
+			if traQExpected.Issuer != tt.cfg.Issuer {
+				t.Errorf("expected issuer = %q, want %q", traQExpected.Issuer, tt.cfg.Issuer)
+			}
+			if tt.cfg.Audience == "" && len(traQExpected.Audience) != 0 {
+				t.Errorf("expected audience = %v, want none", traQExpected.Audience)
+			}
+		})
+	}
+}
+
+func TestParseBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+		ok     bool
+	}{
+		{"Bearer abc.def.ghi", "abc.def.ghi", true},
+		{"bearer abc", "abc", true},
+		{"BEARER abc", "abc", true},
+		{"  Bearer   abc  ", "abc", true},
+		{"Bearer", "", false},
+		{"Bearer ", "", false},
+		{"Basic abc", "", false},
+		{"abc", "", false},
+		{"Bearer abc def", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		token, ok := parseBearerToken(tt.header)
+		if token != tt.token || ok != tt.ok {
+			t.Errorf("parseBearerToken(%q) = %q, %v, want %q, %v", tt.header, token, ok, tt.token, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrBlobNotFound は指定したキーのファイルが存在しない時に返す
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrBlobTooLarge はファイルが許容するサイズを超えている時に返す
+	ErrBlobTooLarge = errors.New("blob is too large")
+)
+
+// BlobInfo はファイルの情報
+type BlobInfo struct {
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// BlobStore はファイルの保存先 (S3 / ローカルディスク / メモリ)
+type BlobStore interface {
+	// Put はファイルを保存する。同じキーのファイルがあれば上書きする
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete はファイルを削除する。存在しない場合もエラーにしない
+	Delete(ctx context.Context, key string) error
+	// URL は expires の間ファイルをダウンロードできる URL を返す
+	URL(ctx context.Context, key string, expires time.Duration) (string, error)
+	// UploadURL は expires の間 PUT でファイルをアップロードできる URL を返す。
+	// アップロード時には contentType と同じ Content-Type ヘッダを付ける必要がある
+	UploadURL(ctx context.Context, key string, contentType string, expires time.Duration) (string, error)
+	// Get はファイルを読み込み用に開く。存在しない場合は ErrBlobNotFound を返す
+	Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error)
+	// Stat はファイルの情報を返す。存在しない場合は ErrBlobNotFound を返す
+	Stat(ctx context.Context, key string) (BlobInfo, error)
+}
+
+// servedBlobStore は署名付き URL を使ってこのサーバから直接配信・アップロードさせる BlobStore (ローカルディスク / メモリ)
+type servedBlobStore interface {
+	BlobStore
+	// Open はファイルをシーク可能な形で開く。存在しない場合は ErrBlobNotFound を返す
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, BlobInfo, error)
+}
+
+// blobKeyPattern はローカルに保存できるキー (パスの区切りや .. を含ませない)
+var blobKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+func validateBlobKey(key string) error {
+	if !blobKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid blob key: %q", key)
+	}
+	return nil
+}
+
+// urlSigner はこのサーバから配信するファイルの URL に期限と署名を付ける
+type urlSigner struct {
+	baseURL string
+	secret  []byte
+}
+
+// newURLSigner は baseURL (例: http://localhost:8080/api) 以下の /files/{key} を指す URL の署名器を作る。
+// secret が空の場合はランダムな鍵を使う (再起動すると発行済みの URL は無効になる)。
+func newURLSigner(baseURL, secret string) (*urlSigner, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generate signing secret: %w", err)
+		}
+	}
+	return &urlSigner{baseURL: strings.TrimSuffix(baseURL, "/"), secret: key}, nil
+}
+
+// sign はメソッド (GET / PUT) ごとに署名する。ダウンロード用の URL でアップロードできないようにするため。
+func (s *urlSigner) sign(method, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *urlSigner) url(method, key string, expires time.Duration) string {
+	exp := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(exp, 10))
+	query.Set("signature", s.sign(method, key, exp))
+	return fmt.Sprintf("%s/files/%s?%s", s.baseURL, url.PathEscape(key), query.Encode())
+}
+
+// verify は URL の期限と署名を検証する
+func (s *urlSigner) verify(method, key string, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(s.sign(method, key, expires)), []byte(signature))
+}
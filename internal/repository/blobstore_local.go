@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// localBlobStore はローカルディスクにファイルを保存し、署名付き URL でこのサーバから配信する。
+// クラウドの認証情報なしで開発するためのもの。
+type localBlobStore struct {
+	dir    string
+	signer *urlSigner
+}
+
+func newLocalBlobStore(dir string, signer *urlSigner) (*localBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage directory: %w", err)
+	}
+	return &localBlobStore{dir: dir, signer: signer}, nil
+}
+
+func (s *localBlobStore) path(key string) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+func (s *localBlobStore) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	// 書き込み途中のファイルを読まれないよう、一時ファイルに書いてから置き換える
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localBlobStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localBlobStore) URL(_ context.Context, key string, expires time.Duration) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+	return s.signer.url(http.MethodGet, key, expires), nil
+}
+
+func (s *localBlobStore) UploadURL(_ context.Context, key string, _ string, expires time.Duration) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+	return s.signer.url(http.MethodPut, key, expires), nil
+}
+
+func (s *localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	return s.Open(ctx, key)
+}
+
+func (s *localBlobStore) Stat(_ context.Context, key string) (BlobInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return BlobInfo{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	return BlobInfo{
+		Size:        info.Size(),
+		ContentType: contentTypeByKey(key),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+func (s *localBlobStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, BlobInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	path, _ := s.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	return f, info, nil
+}
+
+// contentTypeByKey はキーの拡張子から Content-Type を推測する
+func contentTypeByKey(key string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// memoryBlobStore はメモリ上にファイルを保存する。テストや一時的な動作確認用で、再起動すると消える。
+type memoryBlobStore struct {
+	mu     sync.RWMutex
+	blobs  map[string]memoryBlob
+	signer *urlSigner
+}
+
+type memoryBlob struct {
+	data []byte
+	info BlobInfo
+}
+
+func newMemoryBlobStore(signer *urlSigner) *memoryBlobStore {
+	return &memoryBlobStore{
+		blobs:  make(map[string]memoryBlob),
+		signer: signer,
+	}
+}
+
+func (s *memoryBlobStore) Put(_ context.Context, key string, body io.Reader, _ int64, contentType string) error {
+	if err := validateBlobKey(key); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = memoryBlob{
+		data: data,
+		info: BlobInfo{Size: int64(len(data)), ContentType: contentType, ModTime: time.Now()},
+	}
+	return nil
+}
+
+func (s *memoryBlobStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blobs, key)
+	return nil
+}
+
+func (s *memoryBlobStore) URL(_ context.Context, key string, expires time.Duration) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+	return s.signer.url(http.MethodGet, key, expires), nil
+}
+
+func (s *memoryBlobStore) UploadURL(_ context.Context, key string, _ string, expires time.Duration) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+	return s.signer.url(http.MethodPut, key, expires), nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	return s.Open(ctx, key)
+}
+
+func (s *memoryBlobStore) Stat(_ context.Context, key string) (BlobInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blob, ok := s.blobs[key]
+	if !ok {
+		return BlobInfo{}, ErrBlobNotFound
+	}
+	return blob.info, nil
+}
+
+func (s *memoryBlobStore) Open(_ context.Context, key string) (io.ReadSeekCloser, BlobInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blob, ok := s.blobs[key]
+	if !ok {
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	return nopReadSeekCloser{bytes.NewReader(blob.data)}, blob.info, nil
+}
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+)
+
+// newTestFileServices はこのサーバから配信する保存先 (local / memory) の FileService を返す
+func newTestFileServices(t *testing.T) map[string]*FileService {
+	t.Helper()
+	services := make(map[string]*FileService)
+	for _, backend := range []string{"local", "memory"} {
+		fs, err := NewFileService(&config.StorageConfig{
+			Backend:       backend,
+			LocalDir:      t.TempDir(),
+			PublicBaseURL: "http://localhost:8080/api/",
+			SigningSecret: "secret",
+		})
+		if err != nil {
+			t.Fatalf("NewFileService(%s): %v", backend, err)
+		}
+		services[backend] = fs
+	}
+	return services
+}
+
+// signedParams は署名付き URL のパスのキーと expires / signature を取り出す
+func signedParams(t *testing.T, rawURL string) (string, int64, string) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, ok := strings.CutPrefix(u.Path, "/api/files/")
+	if !ok {
+		t.Fatalf("unexpected URL path: %s", u.Path)
+	}
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, expires, u.Query().Get("signature")
+}
+
+func TestFileServiceRoundTrip(t *testing.T) {
+	for backend, fs := range newTestFileServices(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			data := []byte("RIFF....WAVEfmt ")
+			if err := fs.UploadFile(ctx, data, "sound.wav"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := fs.ReadFile(ctx, "sound.wav", int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("ReadFile = %q, want %q", got, data)
+			}
+			if _, err := fs.ReadFile(ctx, "sound.wav", int64(len(data))-1); !errors.Is(err, ErrBlobTooLarge) {
+				t.Errorf("ReadFile with a smaller limit: err = %v, want ErrBlobTooLarge", err)
+			}
+
+			info, err := fs.StatFile(ctx, "sound.wav")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size != int64(len(data)) || info.ContentType != contentTypeByKey("sound.wav") {
+				t.Errorf("StatFile = %+v, want size %d and content type %q", info, len(data), contentTypeByKey("sound.wav"))
+			}
+
+			// 上書き
+			if err := fs.UploadFile(ctx, []byte("new"), "sound.wav"); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := fs.ReadFile(ctx, "sound.wav", 100); err != nil || string(got) != "new" {
+				t.Errorf("ReadFile after overwrite = %q, %v, want %q", got, err, "new")
+			}
+
+			if err := fs.DeleteFile(ctx, "sound.wav"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := fs.StatFile(ctx, "sound.wav"); !errors.Is(err, ErrBlobNotFound) {
+				t.Errorf("StatFile after delete: err = %v, want ErrBlobNotFound", err)
+			}
+			if err := fs.DeleteFile(ctx, "sound.wav"); err != nil {
+				t.Errorf("deleting a missing file: %v", err)
+			}
+
+			for _, key := range []string{"../escape", "a/b", ".hidden", ""} {
+				if err := fs.UploadFile(ctx, data, key); err == nil {
+					t.Errorf("UploadFile accepted the invalid key %q", key)
+				}
+			}
+		})
+	}
+}
+
+func TestFileServiceSignedURL(t *testing.T) {
+	for backend, fs := range newTestFileServices(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			if err := fs.UploadFile(ctx, []byte("data"), "sound.wav"); err != nil {
+				t.Fatal(err)
+			}
+			downloadURL, err := fs.GeneratePresignedURL(ctx, "sound.wav")
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, expires, signature := signedParams(t, downloadURL)
+			if key != "sound.wav" {
+				t.Fatalf("key = %q, want %q", key, "sound.wav")
+			}
+
+			f, _, err := fs.OpenSignedFile(ctx, key, expires, signature)
+			if err != nil {
+				t.Fatalf("OpenSignedFile: %v", err)
+			}
+			got, _ := io.ReadAll(f)
+			f.Close()
+			if string(got) != "data" {
+				t.Errorf("OpenSignedFile read %q, want %q", got, "data")
+			}
+
+			tampered := []byte(signature)
+			tampered[0] ^= 1
+			expired := time.Now().Add(-time.Minute).Unix()
+			tests := []struct {
+				name      string
+				key       string
+				expires   int64
+				signature string
+			}{
+				{"tampered signature", key, expires, string(tampered)},
+				{"empty signature", key, expires, ""},
+				{"extended expiry", key, expires + 3600, signature},
+				{"another key", "other.wav", expires, signature},
+				{"expired", key, expired, fs.signer.sign(http.MethodGet, key, expired)},
+			}
+			for _, tt := range tests {
+				if _, _, err := fs.OpenSignedFile(ctx, tt.key, tt.expires, tt.signature); !errors.Is(err, ErrBlobNotFound) {
+					t.Errorf("%s: OpenSignedFile err = %v, want ErrBlobNotFound", tt.name, err)
+				}
+			}
+
+			// ダウンロード用の署名ではアップロードできない
+			if err := fs.VerifyUploadURL(key, expires, signature); !errors.Is(err, ErrBlobNotFound) {
+				t.Errorf("VerifyUploadURL with a download signature: err = %v, want ErrBlobNotFound", err)
+			}
+		})
+	}
+}
+
+func TestFileServiceSignedUpload(t *testing.T) {
+	for backend, fs := range newTestFileServices(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			uploadURL, err := fs.GenerateUploadURL(ctx, "upload.original.mp3", "audio/mpeg", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, expires, signature := signedParams(t, uploadURL)
+			if err := fs.VerifyUploadURL(key, expires, signature); err != nil {
+				t.Fatalf("VerifyUploadURL: %v", err)
+			}
+			if err := fs.PutSignedFile(ctx, key, expires, signature, strings.NewReader("mp3"), 3, "audio/mpeg"); err != nil {
+				t.Fatalf("PutSignedFile: %v", err)
+			}
+			if got, err := fs.ReadFile(ctx, key, 100); err != nil || string(got) != "mp3" {
+				t.Errorf("ReadFile = %q, %v, want %q", got, err, "mp3")
+			}
+
+			tampered := signature[:len(signature)-1] + "0"
+			if tampered == signature {
+				tampered = signature[:len(signature)-1] + "1"
+			}
+			expired := time.Now().Add(-time.Minute).Unix()
+			tests := []struct {
+				name      string
+				expires   int64
+				signature string
+			}{
+				{"tampered signature", expires, tampered},
+				{"expired", expired, fs.signer.sign(http.MethodPut, key, expired)},
+				{"download signature", expires, fs.signer.sign(http.MethodGet, key, expires)},
+			}
+			for _, tt := range tests {
+				if err := fs.VerifyUploadURL(key, tt.expires, tt.signature); !errors.Is(err, ErrBlobNotFound) {
+					t.Errorf("%s: VerifyUploadURL err = %v, want ErrBlobNotFound", tt.name, err)
+				}
+				if err := fs.PutSignedFile(ctx, key, tt.expires, tt.signature, strings.NewReader("evil"), 4, "audio/mpeg"); !errors.Is(err, ErrBlobNotFound) {
+					t.Errorf("%s: PutSignedFile err = %v, want ErrBlobNotFound", tt.name, err)
+				}
+			}
+			if got, _ := fs.ReadFile(ctx, key, 100); string(got) != "mp3" {
+				t.Errorf("file was overwritten by a rejected upload: %q", got)
+			}
+		})
+	}
+}
+
+func TestFileServiceStoredBytes(t *testing.T) {
+	for backend, fs := range newTestFileServices(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			files := map[string]int{"sound": 1000, "sound.original.mp3": 300, "sound.preview.wav": 20}
+			for key, size := range files {
+				if err := fs.UploadFile(ctx, make([]byte, size), key); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := fs.StoredBytes(ctx, "sound", "sound.original.mp3", "sound.preview.wav")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != 1320 {
+				t.Errorf("StoredBytes = %d, want 1320", got)
+			}
+			// 空のキー (試聴用のファイルが無い等) と存在しないファイルは数えない
+			got, err = fs.StoredBytes(ctx, "sound", "", "missing.wav")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != 1000 {
+				t.Errorf("StoredBytes = %d, want 1000", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/migration"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+)
+
+var (
+	testDBOnce sync.Once
+	testDB     *sqlx.DB
+	testDBErr  error
+)
+
+// newTestRepository は MariaDB (DB_HOST 等、データベース名は DB_TEST_NAME か app_test) に接続してマイグレーションした Repository を返す。
+// DB_TEST=1 が指定されていない場合はテストをスキップする (make test-db で実行する)。
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	if os.Getenv("DB_TEST") != "1" {
+		t.Skip("DB_TEST=1 is not set")
+	}
+	testDBOnce.Do(func() {
+		cfg := config.MySQL()
+		name := os.Getenv("DB_TEST_NAME")
+		if name == "" {
+			name = "app_test"
+		}
+		cfg.DBName = ""
+		admin, err := sqlx.Connect("mysql", cfg.FormatDSN())
+		if err != nil {
+			testDBErr = err
+			return
+		}
+		defer admin.Close()
+		if _, err := admin.Exec("CREATE DATABASE IF NOT EXISTS `" + name + "`"); err != nil {
+			testDBErr = err
+			return
+		}
+
+		cfg.DBName = name
+		testDB, testDBErr = sqlx.Connect("mysql", cfg.FormatDSN())
+		if testDBErr != nil {
+			return
+		}
+		testDBErr = migration.MigrateTables(testDB.DB)
+	})
+	if testDBErr != nil {
+		t.Fatalf("set up test database: %v", testDBErr)
+	}
+	return New(testDB, &config.LivekitConfig{})
+}
+
+// insertTestSound はテスト用のサウンドを登録し、テストの終わりに通報ごと削除する
+func insertTestSound(t *testing.T, r *Repository, creatorID string) Sound {
+	t.Helper()
+	sound := Sound{
+		SoundID:   uuid.NewString(),
+		SoundName: "test sound",
+		CreatorID: creatorID,
+	}
+	if err := insertSound(r.db, sound); err != nil {
+		t.Fatalf("insert sound: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := r.DeleteSoundboardItem(sound.SoundID); err != nil {
+			t.Errorf("delete sound: %v", err)
+		}
+	})
+	return sound
+}
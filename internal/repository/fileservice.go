@@ -3,81 +3,142 @@ package repository
 import (
 	"bytes"
 	"context"
-	"log"
-	"mime"
-	"strings"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"time"
 
 	"github.com/pikachu0310/livekit-server/internal/pkg/config"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	awsconfig "github.com/aws/aws-sdk-go-v2/config" // ★別名インポート
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-// FileService は自作の S3Config を参照
+// presignedURLExpires は再生等のために発行するダウンロード用 URL の有効期間
+const presignedURLExpires = 3 * time.Hour
+
+// FileService はサウンド等のファイルを BlobStore に保存・配信する
 type FileService struct {
-	s3Client        *s3.Client
-	s3PresignClient *s3.PresignClient
-	cfg             *config.S3Config // ★ 自作のS3Config
+	store BlobStore
+	// signer は store がこのサーバから配信する場合だけ設定される
+	signer *urlSigner
 }
 
-var (
-	baseEndpoint = "https://s3.isk01.sakurastorage.jp"
-)
-
-// NewFileService は FileService を初期化
-func NewFileService(cfg *config.S3Config) *FileService {
-	s3Client := connectS3()
-	return &FileService{
-		cfg:             cfg,
-		s3Client:        s3Client,
-		s3PresignClient: s3.NewPresignClient(s3Client),
+// NewFileService は設定された保存先 (s3 / local / memory) の FileService を初期化
+func NewFileService(cfg *config.StorageConfig) (*FileService, error) {
+	switch cfg.Backend {
+	case "s3", "":
+		store, err := newS3BlobStore(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		return &FileService{store: store}, nil
+	case "local":
+		signer, err := newURLSigner(cfg.PublicBaseURL, cfg.SigningSecret)
+		if err != nil {
+			return nil, err
+		}
+		store, err := newLocalBlobStore(cfg.LocalDir, signer)
+		if err != nil {
+			return nil, err
+		}
+		return &FileService{store: store, signer: signer}, nil
+	case "memory":
+		signer, err := newURLSigner(cfg.PublicBaseURL, cfg.SigningSecret)
+		if err != nil {
+			return nil, err
+		}
+		return &FileService{store: newMemoryBlobStore(signer), signer: signer}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %q", cfg.Backend)
 	}
 }
 
-func connectS3() *s3.Client {
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO())
-	if err != nil {
-		log.Fatalln(err)
-	}
+// UploadFile は音声バイト列を保存先にアップロード
+func (fs *FileService) UploadFile(ctx context.Context, file []byte, fileName string) error {
+	return fs.store.Put(ctx, fileName, bytes.NewReader(file), int64(len(file)), contentTypeByKey(fileName))
+}
 
-	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
-		options.BaseEndpoint = &baseEndpoint
-		options.Region = "jp-north-1"
-	})
+// DeleteFile は保存先からファイルを削除 (存在しない場合もエラーにしない)
+func (fs *FileService) DeleteFile(ctx context.Context, fileName string) error {
+	return fs.store.Delete(ctx, fileName)
+}
 
-	return client
+// StatFile はファイルのサイズ等を返す。存在しない場合は ErrBlobNotFound を返す
+func (fs *FileService) StatFile(ctx context.Context, fileName string) (BlobInfo, error) {
+	return fs.store.Stat(ctx, fileName)
 }
 
-// UploadFile は音声バイト列を S3 バケットにアップロード
-func (fs *FileService) UploadFile(ctx context.Context, file []byte, fileName string) error {
-	parts := strings.Split(fileName, ".")
-	ext := "." + parts[len(parts)-1]
-	contentType := mime.TypeByExtension(ext)
-	if contentType == "" {
-		contentType = "application/octet-stream"
+// StoredBytes は keys のファイルの合計サイズを返す。空のキーと存在しないファイルは 0 として数える
+func (fs *FileService) StoredBytes(ctx context.Context, keys ...string) (int64, error) {
+	var total int64
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		info, err := fs.store.Stat(ctx, key)
+		if errors.Is(err, ErrBlobNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("stat %s: %w", key, err)
+		}
+		total += info.Size
 	}
-
-	_, err := fs.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(fs.cfg.BucketName),
-		Key:         aws.String(fileName),
-		Body:        bytes.NewReader(file),
-		ContentType: aws.String(contentType),
-	})
-	return err
+	return total, nil
 }
 
 // GeneratePresignedURL はダウンロード用の署名付きURLを生成
 func (fs *FileService) GeneratePresignedURL(ctx context.Context, fileName string) (string, error) {
-	res, err := fs.s3PresignClient.PresignGetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(fs.cfg.BucketName),
-		Key:    aws.String(fileName),
-	}, func(opts *s3.PresignOptions) {
-		opts.Expires = 3 * time.Hour
-	})
+	return fs.store.URL(ctx, fileName, presignedURLExpires)
+}
+
+// GenerateUploadURL はクライアントが直接アップロードするための署名付きURLを生成
+func (fs *FileService) GenerateUploadURL(ctx context.Context, fileName string, contentType string, expires time.Duration) (string, error) {
+	return fs.store.UploadURL(ctx, fileName, contentType, expires)
+}
+
+// ReadFile はファイルを最大 maxBytes まで読み込む。それより大きい場合はエラーを返す
+func (fs *FileService) ReadFile(ctx context.Context, fileName string, maxBytes int64) ([]byte, error) {
+	body, _, err := fs.store.Get(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxBytes+1))
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrBlobTooLarge
+	}
+	return data, nil
+}
+
+// OpenSignedFile は署名付き URL で要求されたファイルを開く。
+// このサーバから配信しない保存先 (S3) の場合や、署名が正しくない場合は ErrBlobNotFound を返す。
+func (fs *FileService) OpenSignedFile(ctx context.Context, fileName string, expires int64, signature string) (io.ReadSeekCloser, BlobInfo, error) {
+	served, ok := fs.store.(servedBlobStore)
+	if !ok || fs.signer == nil || !fs.signer.verify(http.MethodGet, fileName, expires, signature) {
+		return nil, BlobInfo{}, ErrBlobNotFound
+	}
+	return served.Open(ctx, fileName)
+}
+
+// VerifyUploadURL はアップロード用の署名付き URL の期限と署名を検証する。
+// このサーバに保存しない保存先 (S3) の場合や、署名が正しくない場合は ErrBlobNotFound を返す。
+// リクエストボディを読む前に呼び、署名の無いリクエストにボディを読ませないようにする。
+func (fs *FileService) VerifyUploadURL(fileName string, expires int64, signature string) error {
+	if _, ok := fs.store.(servedBlobStore); !ok || fs.signer == nil || !fs.signer.verify(http.MethodPut, fileName, expires, signature) {
+		return ErrBlobNotFound
+	}
+	return nil
+}
+
+// PutSignedFile は署名付き URL でアップロードされたファイルを保存する。
+// このサーバに保存しない保存先 (S3) の場合や、署名が正しくない場合は ErrBlobNotFound を返す。
+func (fs *FileService) PutSignedFile(ctx context.Context, fileName string, expires int64, signature string, body io.Reader, size int64, contentType string) error {
+	if err := fs.VerifyUploadURL(fileName, expires, signature); err != nil {
+		return err
 	}
-	return res.URL, nil
+	return fs.store.Put(ctx, fileName, body, size, contentType)
 }
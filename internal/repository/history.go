@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RoomSession は DB上の room_sessions テーブルに対応する構造体です (1回の Qall)
+type RoomSession struct {
+	SessionID        string       `db:"session_id"`
+	RoomID           string       `db:"room_id"`
+	StartedAt        time.Time    `db:"started_at"`
+	EndedAt          sql.NullTime `db:"ended_at"`
+	IsWebinar        bool         `db:"is_webinar"`
+	PeakParticipants int          `db:"peak_participants"`
+}
+
+// ParticipantStint は DB上の participant_stints テーブルに対応する構造体です (1人が1回入室してから退出するまで)
+type ParticipantStint struct {
+	StintID   string       `db:"stint_id"`
+	SessionID string       `db:"session_id"`
+	RoomID    string       `db:"room_id"`
+	UserID    string       `db:"user_id"`
+	Identity  string       `db:"identity"`
+	JoinedAt  time.Time    `db:"joined_at"`
+	LeftAt    sql.NullTime `db:"left_at"`
+}
+
+// StartRoomSession はルームの通話セッションを開始します。既に開いているセッションがあればそのIDを返します
+func (r *Repository) StartRoomSession(roomID string, isWebinar bool, startedAt time.Time) (string, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sessionID, err := ensureRoomSession(tx, roomID, isWebinar, startedAt)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit room session: %w", err)
+	}
+	return sessionID, nil
+}
+
+// RecordParticipantJoined は参加者の入室を記録し、セッションの最大同時接続数を更新します。
+// 同じ identity の入室が既に記録されている場合は何もしません (Webhookの再送対策)
+func (r *Repository) RecordParticipantJoined(roomID, userID, identity string, isWebinar bool, joinedAt time.Time, participants int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sessionID, err := ensureRoomSession(tx, roomID, isWebinar, joinedAt)
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := tx.Get(&exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM participant_stints
+			WHERE session_id = ? AND identity = ? AND left_at IS NULL
+		)
+	`, sessionID, identity); err != nil {
+		return fmt.Errorf("select participant stint: %w", err)
+	}
+	if !exists {
+		if _, err := tx.Exec(`
+			INSERT INTO participant_stints (stint_id, session_id, room_id, user_id, identity, joined_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, uuid.NewString(), sessionID, roomID, userID, identity, joinedAt); err != nil {
+			return fmt.Errorf("insert participant stint: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE room_sessions
+		SET peak_participants = GREATEST(peak_participants, ?)
+		WHERE session_id = ?
+	`, participants, sessionID); err != nil {
+		return fmt.Errorf("update peak participants: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit participant joined: %w", err)
+	}
+	return nil
+}
+
+// RecordParticipantLeft は参加者の退出時刻を記録します
+func (r *Repository) RecordParticipantLeft(roomID, identity string, leftAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE participant_stints
+		SET left_at = ?
+		WHERE room_id = ? AND identity = ? AND left_at IS NULL
+	`, leftAt, roomID, identity)
+	if err != nil {
+		return fmt.Errorf("update participant stint: %w", err)
+	}
+	return nil
+}
+
+// EndRoomSession はルームの通話セッションと、退出が記録されていない参加者を終了させます
+func (r *Repository) EndRoomSession(roomID string, endedAt time.Time) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		UPDATE participant_stints
+		SET left_at = ?
+		WHERE room_id = ? AND left_at IS NULL
+	`, endedAt, roomID); err != nil {
+		return fmt.Errorf("close participant stints: %w", err)
+	}
+	if _, err := tx.Exec(`
+		UPDATE room_sessions
+		SET ended_at = ?
+		WHERE room_id = ? AND ended_at IS NULL
+	`, endedAt, roomID); err != nil {
+		return fmt.Errorf("close room session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit room session end: %w", err)
+	}
+	return nil
+}
+
+// GetRoomSessions は指定ルームの通話セッションを新しい順に取得します
+func (r *Repository) GetRoomSessions(roomID string, limit, offset int) ([]RoomSession, error) {
+	var sessions []RoomSession
+	if err := r.db.Select(&sessions, `
+		SELECT session_id, room_id, started_at, ended_at, is_webinar, peak_participants
+		FROM room_sessions
+		WHERE room_id = ?
+		ORDER BY started_at DESC
+		LIMIT ? OFFSET ?
+	`, roomID, limit, offset); err != nil {
+		return nil, fmt.Errorf("select room sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// GetParticipantStintsBySessionIDs は指定セッションの参加記録を入室順に取得します
+func (r *Repository) GetParticipantStintsBySessionIDs(sessionIDs []string) ([]ParticipantStint, error) {
+	if len(sessionIDs) == 0 {
+		return []ParticipantStint{}, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT stint_id, session_id, room_id, user_id, identity, joined_at, left_at
+		FROM participant_stints
+		WHERE session_id IN (?)
+		ORDER BY joined_at
+	`, sessionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build participant stints query: %w", err)
+	}
+	var stints []ParticipantStint
+	if err := r.db.Select(&stints, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("select participant stints: %w", err)
+	}
+	return stints, nil
+}
+
+// GetParticipantStintsByUserID は指定ユーザの参加記録を新しい順に取得します
+func (r *Repository) GetParticipantStintsByUserID(userID string, limit, offset int) ([]ParticipantStint, error) {
+	var stints []ParticipantStint
+	if err := r.db.Select(&stints, `
+		SELECT stint_id, session_id, room_id, user_id, identity, joined_at, left_at
+		FROM participant_stints
+		WHERE user_id = ?
+		ORDER BY joined_at DESC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("select participant stints by user_id: %w", err)
+	}
+	return stints, nil
+}
+
+// ensureRoomSession は開いているセッションのIDを返し、無ければ新しく作成します
+func ensureRoomSession(tx *sqlx.Tx, roomID string, isWebinar bool, startedAt time.Time) (string, error) {
+	var sessionID string
+	err := tx.Get(&sessionID, `
+		SELECT session_id
+		FROM room_sessions
+		WHERE room_id = ? AND ended_at IS NULL
+		ORDER BY started_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`, roomID)
+	if err == nil {
+		return sessionID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("select open room session: %w", err)
+	}
+
+	sessionID = uuid.NewString()
+	if _, err := tx.Exec(`
+		INSERT INTO room_sessions (session_id, room_id, started_at, is_webinar)
+		VALUES (?, ?, ?, ?)
+	`, sessionID, roomID, startedAt, isWebinar); err != nil {
+		return "", fmt.Errorf("insert room session: %w", err)
+	}
+	return sessionID, nil
+}
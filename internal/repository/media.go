@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/livekit/protocol/livekit"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// AddTrackToRoomState は参加者が公開したトラックをルーム状態に追加する。
+// 同じ sid のトラックが既にある場合は置き換える。参加者が見つからない場合は false を返す。
+func (r *Repository) AddTrackToRoomState(roomId string, identity string, track *livekit.TrackInfo) (models.Track, bool) {
+	t := trackFromLiveKit(track)
+	roomID, err := uuid.Parse(roomId)
+	if err != nil {
+		return t, false
+	}
+	ok := r.RoomState.UpdateParticipant(roomID, identity, func(p *models.Participant) {
+		tracks := removeTrack(p.Tracks, t.Sid)
+		tracks = append(tracks, t)
+		p.Tracks = &tracks
+	})
+	return t, ok
+}
+
+// RemoveTrackFromRoomState は参加者が公開をやめたトラックをルーム状態から削除する
+func (r *Repository) RemoveTrackFromRoomState(roomId string, identity string, track *livekit.TrackInfo) (models.Track, bool) {
+	t := trackFromLiveKit(track)
+	roomID, err := uuid.Parse(roomId)
+	if err != nil {
+		return t, false
+	}
+	ok := r.RoomState.UpdateParticipant(roomID, identity, func(p *models.Participant) {
+		tracks := removeTrack(p.Tracks, t.Sid)
+		p.Tracks = &tracks
+	})
+	return t, ok
+}
+
+// UpdateEgressInRoomState は egress の状態をルーム状態に反映する。ルームが見つからない場合は false を返す。
+func (r *Repository) UpdateEgressInRoomState(info *livekit.EgressInfo) (models.Egress, bool) {
+	egress := egressFromLiveKit(info)
+	roomID, err := uuid.Parse(info.GetRoomName())
+	if err != nil {
+		return egress, false
+	}
+	ok := r.RoomState.UpdateRoom(roomID, func(room *models.RoomWithParticipants) {
+		egresses := withoutEgress(room.Egresses, egress.EgressId)
+		egresses = append(egresses, egress)
+		room.Egresses = &egresses
+	})
+	return egress, ok
+}
+
+// RemoveEgressFromRoomState は終了した egress をルーム状態から削除する
+func (r *Repository) RemoveEgressFromRoomState(info *livekit.EgressInfo) (models.Egress, bool) {
+	egress := egressFromLiveKit(info)
+	roomID, err := uuid.Parse(info.GetRoomName())
+	if err != nil {
+		return egress, false
+	}
+	ok := r.RoomState.UpdateRoom(roomID, func(room *models.RoomWithParticipants) {
+		egresses := withoutEgress(room.Egresses, egress.EgressId)
+		room.Egresses = &egresses
+	})
+	return egress, ok
+}
+
+// UpdateIngressInRoomState は ingress の状態をルーム状態に反映する。ルームが見つからない場合は false を返す。
+func (r *Repository) UpdateIngressInRoomState(info *livekit.IngressInfo) (models.Ingress, bool) {
+	ingress := ingressFromLiveKit(info)
+	roomID, err := uuid.Parse(info.GetRoomName())
+	if err != nil {
+		return ingress, false
+	}
+	ok := r.RoomState.UpdateRoom(roomID, func(room *models.RoomWithParticipants) {
+		ingresses := withoutIngress(room.Ingresses, ingress.IngressId)
+		ingresses = append(ingresses, ingress)
+		room.Ingresses = &ingresses
+	})
+	return ingress, ok
+}
+
+// RemoveIngressFromRoomState は終了した ingress をルーム状態から削除する
+func (r *Repository) RemoveIngressFromRoomState(info *livekit.IngressInfo) (models.Ingress, bool) {
+	ingress := ingressFromLiveKit(info)
+	roomID, err := uuid.Parse(info.GetRoomName())
+	if err != nil {
+		return ingress, false
+	}
+	ok := r.RoomState.UpdateRoom(roomID, func(room *models.RoomWithParticipants) {
+		ingresses := withoutIngress(room.Ingresses, ingress.IngressId)
+		room.Ingresses = &ingresses
+	})
+	return ingress, ok
+}
+
+// trackFromLiveKit は LiveKit のトラック情報を API のモデルに変換する
+func trackFromLiveKit(t *livekit.TrackInfo) models.Track {
+	muted := t.GetMuted()
+	return models.Track{
+		Sid:    t.GetSid(),
+		Source: trackSourceFromLiveKit(t.GetSource()),
+		Muted:  &muted,
+	}
+}
+
+func trackSourceFromLiveKit(source livekit.TrackSource) models.TrackSource {
+	switch source {
+	case livekit.TrackSource_MICROPHONE:
+		return models.Microphone
+	case livekit.TrackSource_CAMERA:
+		return models.Camera
+	case livekit.TrackSource_SCREEN_SHARE:
+		return models.ScreenShare
+	case livekit.TrackSource_SCREEN_SHARE_AUDIO:
+		return models.ScreenShareAudio
+	default:
+		return models.Unknown
+	}
+}
+
+// tracksFromLiveKit は参加者が公開中のトラック一覧を変換する
+func tracksFromLiveKit(tracks []*livekit.TrackInfo) []models.Track {
+	result := make([]models.Track, 0, len(tracks))
+	for _, t := range tracks {
+		result = append(result, trackFromLiveKit(t))
+	}
+	return result
+}
+
+// removeTrack は sid のトラックを除いた新しいスライスを返す (元のスライスは書き換えない)
+func removeTrack(tracks *[]models.Track, sid string) []models.Track {
+	result := make([]models.Track, 0)
+	if tracks == nil {
+		return result
+	}
+	for _, t := range *tracks {
+		if t.Sid != sid {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// withoutEgress は egressId の egress を除いた新しいスライスを返す
+func withoutEgress(egresses *[]models.Egress, egressID string) []models.Egress {
+	result := make([]models.Egress, 0)
+	if egresses == nil {
+		return result
+	}
+	for _, e := range *egresses {
+		if e.EgressId != egressID {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// withoutIngress は ingressId の ingress を除いた新しいスライスを返す
+func withoutIngress(ingresses *[]models.Ingress, ingressID string) []models.Ingress {
+	result := make([]models.Ingress, 0)
+	if ingresses == nil {
+		return result
+	}
+	for _, i := range *ingresses {
+		if i.IngressId != ingressID {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func egressFromLiveKit(info *livekit.EgressInfo) models.Egress {
+	egress := models.Egress{
+		EgressId: info.GetEgressId(),
+		Status:   info.GetStatus().String(),
+	}
+	if info.GetStartedAt() != 0 {
+		startedAt := time.Unix(0, info.GetStartedAt()).In(time.FixedZone("Asia/Tokyo", 9*60*60))
+		egress.StartedAt = &startedAt
+	}
+	return egress
+}
+
+func ingressFromLiveKit(info *livekit.IngressInfo) models.Ingress {
+	name := info.GetName()
+	identity := info.GetParticipantIdentity()
+	return models.Ingress{
+		IngressId:           info.GetIngressId(),
+		Name:                &name,
+		ParticipantIdentity: &identity,
+		Status:              info.GetState().GetStatus().String(),
+	}
+}
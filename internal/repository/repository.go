@@ -3,7 +3,6 @@ package repository
 import (
 	"github.com/jmoiron/sqlx"
 	"github.com/pikachu0310/livekit-server/internal/pkg/config"
-	"github.com/pikachu0310/livekit-server/openapi/models"
 )
 
 type Repository struct {
@@ -11,7 +10,9 @@ type Repository struct {
 	LiveKitHost string
 	ApiKey      string
 	ApiSecret   string
-	RoomState   []models.RoomWithParticipants
+	RoomState   *RoomStateStore
+
+	webhookOrder *webhookOrder
 }
 
 func New(db *sqlx.DB, liveKitCfg *config.LivekitConfig) *Repository {
@@ -20,6 +21,8 @@ func New(db *sqlx.DB, liveKitCfg *config.LivekitConfig) *Repository {
 		LiveKitHost: liveKitCfg.LiveKitHost,
 		ApiKey:      liveKitCfg.ApiKey,
 		ApiSecret:   liveKitCfg.ApiSecret,
-		RoomState:   make([]models.RoomWithParticipants, 0),
+		RoomState:   NewRoomStateStore(),
+
+		webhookOrder: newWebhookOrder(),
 	}
 }
@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,70 +13,64 @@ import (
 	lksdk "github.com/livekit/server-sdk-go/v2"
 	"github.com/pikachu0310/livekit-server/internal/pkg/util"
 	"github.com/pikachu0310/livekit-server/openapi/models"
+	"github.com/twitchtv/twirp"
 )
 
 // InitializeRoomState LiveKit APIから現在のルーム状態を取得 (初期化時に利用)
 func (r *Repository) InitializeRoomState() error {
-	roomWithParticipants, err := r.GetRoomsWithParticipantsByLiveKitServer(context.Background())
-	r.RoomState = roomWithParticipants
-	return err
+	return r.GetRoomsWithParticipantsByLiveKitServerAndSave(context.Background())
 }
 
+// AddParticipantToRoomState は参加者をルーム状態に追加する。
+// 同じ identity の参加者が既にいる場合は置き換えるので、Webhookが再送されても重複しない。
 func (r *Repository) AddParticipantToRoomState(room *livekit.Room, participant *livekit.ParticipantInfo) {
-	for i, roomState := range r.RoomState {
-		if roomState.RoomId.String() == room.Name {
-			t := time.Unix(participant.JoinedAt, 0).In(time.FixedZone("Asia/Tokyo", 9*60*60))
-			r.RoomState[i].Participants = append(r.RoomState[i].Participants, models.Participant{
-				Identity:   &participant.Identity,
-				JoinedAt:   &t,
-				Name:       &participant.Name,
-				Attributes: &participant.Attributes,
-				CanPublish: &participant.Permission.CanPublish,
-			})
-		}
+	roomID, err := uuid.Parse(room.Name)
+	if err != nil {
+		return
+	}
+	if !r.RoomState.Exists(roomID) {
+		r.RoomState.PutRoom(roomFromLiveKit(room, roomID))
+	}
+	r.RoomState.UpsertParticipant(roomID, participantFromLiveKit(participant))
+}
+
+// AddRoomToRoomState は LiveKit のルームをルーム状態に追加する。既にある場合は参加者を残したままメタデータを更新する。
+// 新規に追加した場合は true を返す。
+func (r *Repository) AddRoomToRoomState(room *livekit.Room) bool {
+	roomID, err := uuid.Parse(room.Name)
+	if err != nil {
+		return false
 	}
+	return r.RoomState.PutRoom(roomFromLiveKit(room, roomID))
 }
 
 func (r *Repository) UpdateParticipantCanPublish(roomId string, participantId string, canPublish bool) {
-	for i, roomState := range r.RoomState {
-		if roomState.RoomId.String() == roomId {
-			for j, participant := range roomState.Participants {
-				if *participant.Identity == participantId {
-					r.RoomState[i].Participants[j].CanPublish = &canPublish
-				}
-			}
-		}
+	roomID, err := uuid.Parse(roomId)
+	if err != nil {
+		return
 	}
+	r.RoomState.UpdateParticipant(roomID, participantId, func(p *models.Participant) {
+		p.CanPublish = &canPublish
+	})
 }
 
 func (r *Repository) UpdateParticipant(roomId string, participant *livekit.ParticipantInfo) {
-	for i, roomState := range r.RoomState {
-		if roomState.RoomId.String() == roomId {
-			for j, p := range roomState.Participants {
-				if *p.Identity == participant.Identity {
-					t := time.Unix(participant.JoinedAt, 0).In(time.FixedZone("Asia/Tokyo", 9*60*60))
-					r.RoomState[i].Participants[j] = models.Participant{
-						Identity:   &participant.Identity,
-						JoinedAt:   &t,
-						Name:       &participant.Name,
-						Attributes: &participant.Attributes,
-					}
-				}
-			}
-		}
+	roomID, err := uuid.Parse(roomId)
+	if err != nil {
+		return
 	}
+	updated := participantFromLiveKit(participant)
+	r.RoomState.UpdateParticipant(roomID, participant.Identity, func(p *models.Participant) {
+		*p = updated
+	})
 }
 
 func (r *Repository) RemoveParticipant(roomId string, participantId string) {
-	for i, roomState := range r.RoomState {
-		if roomState.RoomId.String() == roomId {
-			for j, participant := range roomState.Participants {
-				if *participant.Identity == participantId {
-					r.RoomState[i].Participants = append(r.RoomState[i].Participants[:j], r.RoomState[i].Participants[j+1:]...)
-				}
-			}
-		}
+	roomID, err := uuid.Parse(roomId)
+	if err != nil {
+		return
 	}
+	r.RoomState.RemoveParticipant(roomID, participantId)
 }
 
 func (r *Repository) GetRoomsWithParticipantsByLiveKitServerAndSave(ctx context.Context) error {
@@ -81,12 +78,37 @@ func (r *Repository) GetRoomsWithParticipantsByLiveKitServerAndSave(ctx context.
 	if err != nil {
 		return err
 	}
-	r.RoomState = roomWithParticipants
+	r.RoomState.Replace(roomWithParticipants)
 	return nil
 }
 
+// reconcileMaxAttempts は LiveKit から取得している間に Webhook で状態が変わった場合に、突き合わせを取り直す最大の回数
+const reconcileMaxAttempts = 3
+
+// ReconcileRoomState は LiveKit 上の実際のルーム・参加者一覧とルーム状態を突き合わせ、
+// 取りこぼしたWebhookによるズレを修正する。修正した内容を返す。
+func (r *Repository) ReconcileRoomState(ctx context.Context) ([]string, error) {
+	return r.reconcileRoomState(ctx, r.GetRoomsWithParticipantsByLiveKitServer)
+}
+
+// reconcileRoomState は fetch で取得した状態とルーム状態を突き合わせる。
+// 取得中にWebhookで状態が変わった場合は巻き戻さないよう取り直し、reconcileMaxAttempts 回続いたらエラーを返す。
+func (r *Repository) reconcileRoomState(ctx context.Context, fetch func(context.Context) ([]models.RoomWithParticipants, error)) ([]string, error) {
+	for attempt := 1; attempt <= reconcileMaxAttempts; attempt++ {
+		version := r.RoomState.Version()
+		roomWithParticipants, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if changes, ok := r.RoomState.Reconcile(roomWithParticipants, version); ok {
+			return changes, nil
+		}
+	}
+	return nil, fmt.Errorf("room state changed during each of %d fetches from LiveKit, giving up until the next run", reconcileMaxAttempts)
+}
+
 func (r *Repository) AddRoomState(room models.RoomWithParticipants) {
-	r.RoomState = append(r.RoomState, room)
+	r.RoomState.PutRoom(room)
 }
 
 func (r *Repository) CreateRoomState(roomId string) error {
@@ -102,11 +124,101 @@ func (r *Repository) CreateRoomState(roomId string) error {
 }
 
 func (r *Repository) RemoveRoomState(roomId string) {
-	for i, roomState := range r.RoomState {
-		if roomState.RoomId.String() == roomId {
-			r.RoomState = append(r.RoomState[:i], r.RoomState[i+1:]...)
+	roomID, err := uuid.Parse(roomId)
+	if err != nil {
+		return
+	}
+	r.RoomState.RemoveRoom(roomID)
+}
+
+// participantFromLiveKit は LiveKit の参加者情報を API のモデルに変換する。
+// protobuf のフィールドを直接指さないように値をコピーしてから詰める。
+func participantFromLiveKit(p *livekit.ParticipantInfo) models.Participant {
+	identity := p.Identity
+	name := p.Name
+	joinedAt := time.Unix(p.JoinedAt, 0).In(time.FixedZone("Asia/Tokyo", 9*60*60))
+	attributes := make(map[string]string, len(p.Attributes))
+	for k, v := range p.Attributes {
+		attributes[k] = v
+	}
+	canPublish := p.Permission != nil && p.Permission.CanPublish
+	tracks := tracksFromLiveKit(p.Tracks)
+	return models.Participant{
+		Identity:   &identity,
+		JoinedAt:   &joinedAt,
+		Name:       &name,
+		Attributes: &attributes,
+		CanPublish: &canPublish,
+		Tracks:     &tracks,
+	}
+}
+
+// roomFromLiveKit は LiveKit のルーム情報から参加者なしのルームを作る
+func roomFromLiveKit(rm *livekit.Room, roomID uuid.UUID) models.RoomWithParticipants {
+	metadata := parseRoomMetadata(rm.Metadata)
+	return models.RoomWithParticipants{
+		Metadata:     &metadata.Status,
+		IsWebinar:    &metadata.IsWebinar,
+		RoomId:       roomID,
+		Participants: make([]models.Participant, 0),
+	}
+}
+
+// parseRoomMetadata はルームのメタデータ(JSON)を読み取る。空や不正な値はゼロ値として扱う。
+func parseRoomMetadata(raw string) util.Metadata {
+	var metadata util.Metadata
+	if raw == "" {
+		return metadata
+	}
+	_ = json.Unmarshal([]byte(raw), &metadata)
+	return metadata
+}
+
+// IsUserInRoom はユーザがルームに参加しているかを返す。
+// ルーム状態に見つからない場合は、Webhook の遅れを考えて LiveKit にも問い合わせる。
+func (r *Repository) IsUserInRoom(ctx context.Context, roomId uuid.UUID, userId string) (bool, error) {
+	if room, ok := r.RoomState.Get(roomId); ok {
+		for _, participant := range room.Participants {
+			if participant.Identity != nil && IsIdentityOfUser(*participant.Identity, userId) {
+				return true, nil
+			}
+		}
+	}
+
+	res, err := r.GetParticipantsByLiveKitServer(ctx, roomId.String())
+	if err != nil {
+		var twirpErr twirp.Error
+		if errors.As(err, &twirpErr) && twirpErr.Code() == twirp.NotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("list participants: %w", err)
+	}
+	for _, participant := range res.Participants {
+		if IsIdentityOfUser(participant.Identity, userId) {
+			return true, nil
 		}
 	}
+	return false, nil
+}
+
+// IsIdentityOfUser は LiveKit の identity ("ユーザID_RandomUUID") がユーザのものかを返す。
+// 表示名は参加者自身が変更できるので、トークンの発行時に決まる identity で判定する。
+func IsIdentityOfUser(identity, userId string) bool {
+	owner, ok := UserIDOfIdentity(identity)
+	return ok && owner == userId
+}
+
+// UserIDOfIdentity は LiveKit の identity ("ユーザID_RandomUUID") からユーザIDを取り出す。
+// サウンドボードの Ingress などユーザのトークンで参加していない identity の場合は false を返す。
+func UserIDOfIdentity(identity string) (string, bool) {
+	i := strings.LastIndex(identity, "_")
+	if i <= 0 {
+		return "", false
+	}
+	if _, err := uuid.Parse(identity[i+1:]); err != nil {
+		return "", false
+	}
+	return identity[:i], true
 }
 
 func (r *Repository) NewLiveKitRoomServiceClient() *lksdk.RoomServiceClient {
@@ -131,22 +243,16 @@ func (r *Repository) GetRoomsWithParticipantsByLiveKitServer(ctx context.Context
 		return nil, err
 	}
 
-	var roomWithParticipants []models.RoomWithParticipants
+	roomWithParticipants := make([]models.RoomWithParticipants, 0, len(roomResp.Rooms))
 	for _, rm := range roomResp.Rooms {
 		partResp, err := r.GetParticipantsByLiveKitServer(ctx, rm.Name)
 		if err != nil {
 			return nil, err
 		}
 
-		var Participants []models.Participant
+		participants := make([]models.Participant, 0, len(partResp.Participants))
 		for _, p := range partResp.Participants {
-			t := time.Unix(p.JoinedAt, 0).In(time.FixedZone("Asia/Tokyo", 9*60*60))
-			Participants = append(Participants, models.Participant{
-				Identity:   &p.Identity,
-				JoinedAt:   &t,
-				Name:       &p.Name,
-				Attributes: &p.Attributes,
-			})
+			participants = append(participants, participantFromLiveKit(p))
 		}
 
 		roomId, err := uuid.Parse(rm.Name)
@@ -154,19 +260,9 @@ func (r *Repository) GetRoomsWithParticipantsByLiveKitServer(ctx context.Context
 			return nil, err
 		}
 
-		var metadata *util.Metadata
-		// rm.MetadataをJSON文字列としてunmarshalする
-		err = json.Unmarshal([]byte(rm.Metadata), &metadata)
-		if err != nil {
-			return nil, err
-		}
-
-		roomWithParticipants = append(roomWithParticipants, models.RoomWithParticipants{
-			Metadata:     &metadata.Status,
-			IsWebinar:    &metadata.IsWebinar,
-			RoomId:       roomId,
-			Participants: Participants,
-		})
+		room := roomFromLiveKit(rm, roomId)
+		room.Participants = participants
+		roomWithParticipants = append(roomWithParticipants, room)
 	}
 
 	return roomWithParticipants, nil
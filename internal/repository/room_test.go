@@ -0,0 +1,32 @@
+package repository
+
+import "testing"
+
+func TestUserIDOfIdentity(t *testing.T) {
+	tests := []struct {
+		identity string
+		want     string
+		wantOK   bool
+	}{
+		{"alice_0b8e8b34-6a4e-4b8b-9f5e-2f1f3c4d5e6f", "alice", true},
+		// traQ のユーザIDには "_" を含められる
+		{"bob_smith_0b8e8b34-6a4e-4b8b-9f5e-2f1f3c4d5e6f", "bob_smith", true},
+		{"soundboard-0b8e8b34-6a4e-4b8b-9f5e-2f1f3c4d5e6f", "", false},
+		{"alice_not-a-uuid", "", false},
+		{"_0b8e8b34-6a4e-4b8b-9f5e-2f1f3c4d5e6f", "", false},
+		{"alice", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := UserIDOfIdentity(tt.identity)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("UserIDOfIdentity(%q) = %q, %v, want %q, %v", tt.identity, got, ok, tt.want, tt.wantOK)
+		}
+		if tt.wantOK && !IsIdentityOfUser(tt.identity, tt.want) {
+			t.Errorf("IsIdentityOfUser(%q, %q) = false, want true", tt.identity, tt.want)
+		}
+	}
+	if IsIdentityOfUser("bob_smith_0b8e8b34-6a4e-4b8b-9f5e-2f1f3c4d5e6f", "bob") {
+		t.Error("IsIdentityOfUser matched a user whose id is a prefix of the owner's")
+	}
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// RoomStateStore はルームごとの状態をルームID(UUID)で索引して保持する。
+// Webhook・トークン発行・メタデータ更新など複数のgoroutineから同時に触られるため、
+// 全ての読み書きをRWMutexで直列化し、外部へは必ずコピーを返す。
+type RoomStateStore struct {
+	mu    sync.RWMutex
+	rooms map[uuid.UUID]*models.RoomWithParticipants
+	// version は状態が書き換わるたびに増える (Reconcile の競合検出に使う)
+	version uint64
+}
+
+func NewRoomStateStore() *RoomStateStore {
+	return &RoomStateStore{
+		rooms: make(map[uuid.UUID]*models.RoomWithParticipants),
+	}
+}
+
+// Snapshot は全ルームのコピーをルームID順で返す (ブロードキャスト用)
+func (s *RoomStateStore) Snapshot() []models.RoomWithParticipants {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rooms := make([]models.RoomWithParticipants, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, copyRoom(room))
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return bytes.Compare(rooms[i].RoomId[:], rooms[j].RoomId[:]) < 0
+	})
+	return rooms
+}
+
+// Get は指定ルームのコピーを返す
+func (s *RoomStateStore) Get(roomID uuid.UUID) (models.RoomWithParticipants, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return models.RoomWithParticipants{}, false
+	}
+	return copyRoom(room), true
+}
+
+// Exists はルームが存在するかを返す
+func (s *RoomStateStore) Exists(roomID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.rooms[roomID]
+	return ok
+}
+
+// Version は現在の状態のバージョンを返す
+func (s *RoomStateStore) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.version
+}
+
+// Replace は全ルームの状態を丸ごと置き換える
+func (s *RoomStateStore) Replace(rooms []models.RoomWithParticipants) {
+	next := make(map[uuid.UUID]*models.RoomWithParticipants, len(rooms))
+	for _, room := range rooms {
+		room := copyRoom(&room)
+		next[room.RoomId] = &room
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	s.rooms = next
+}
+
+// PutRoom はルームを追加し、既に存在する場合は参加者を残したままメタデータ等を上書きする。
+// 新規に作成した場合は true を返す。
+// version は状態が変わった場合だけ進める (Reconcile が重複した Webhook で中断されないように。以下同様)
+func (s *RoomStateStore) PutRoom(room models.RoomWithParticipants) bool {
+	room = copyRoom(&room)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.rooms[room.RoomId]
+	if !ok {
+		s.version++
+		s.rooms[room.RoomId] = &room
+		return true
+	}
+	if room.IsWebinar != nil && boolValue(current.IsWebinar) != *room.IsWebinar {
+		s.version++
+		current.IsWebinar = room.IsWebinar
+	}
+	if room.Metadata != nil && stringValue(current.Metadata) != *room.Metadata {
+		s.version++
+		current.Metadata = room.Metadata
+	}
+	return false
+}
+
+// RemoveRoom はルームを削除し、削除できたかを返す
+func (s *RoomStateStore) RemoveRoom(roomID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rooms[roomID]; !ok {
+		return false
+	}
+	s.version++
+	delete(s.rooms, roomID)
+	return true
+}
+
+// SetMetadata はルームのメタデータ(status)を更新する
+func (s *RoomStateStore) SetMetadata(roomID uuid.UUID, metadata string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return false
+	}
+	if room.Metadata == nil || *room.Metadata != metadata {
+		s.version++
+		room.Metadata = &metadata
+	}
+	return true
+}
+
+// UpdateRoom はルームを fn で書き換える。ルームが見つかった場合は true を返す。
+func (s *RoomStateStore) UpdateRoom(roomID uuid.UUID, fn func(room *models.RoomWithParticipants)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return false
+	}
+	before := copyRoom(room)
+	fn(room)
+	if !reflect.DeepEqual(before, *room) {
+		s.version++
+	}
+	return true
+}
+
+// UpsertParticipant は参加者を identity 単位で追加または置き換える。
+// ルームが無ければ空のルームを作成してから追加する。新規追加の場合は true を返す。
+func (s *RoomStateStore) UpsertParticipant(roomID uuid.UUID, participant models.Participant) bool {
+	participant = copyParticipant(participant)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		room = &models.RoomWithParticipants{
+			RoomId:       roomID,
+			Participants: make([]models.Participant, 0),
+		}
+		s.rooms[roomID] = room
+	}
+
+	for i, p := range room.Participants {
+		if sameIdentity(p, participant) {
+			if !reflect.DeepEqual(p, participant) {
+				s.version++
+				room.Participants[i] = participant
+			}
+			return false
+		}
+	}
+	s.version++
+	room.Participants = append(room.Participants, participant)
+	return true
+}
+
+// UpdateParticipant は参加者を fn で書き換える。対象が見つかった場合は true を返す。
+func (s *RoomStateStore) UpdateParticipant(roomID uuid.UUID, identity string, fn func(p *models.Participant)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return false
+	}
+	for i := range room.Participants {
+		if room.Participants[i].Identity != nil && *room.Participants[i].Identity == identity {
+			before := copyParticipant(room.Participants[i])
+			fn(&room.Participants[i])
+			if !reflect.DeepEqual(before, room.Participants[i]) {
+				s.version++
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveParticipant は参加者を削除し、削除できたかを返す
+func (s *RoomStateStore) RemoveParticipant(roomID uuid.UUID, identity string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return false
+	}
+	for i, p := range room.Participants {
+		if p.Identity != nil && *p.Identity == identity {
+			s.version++
+			room.Participants = append(room.Participants[:i], room.Participants[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Reconcile は LiveKit から取得した実際の状態(actual)との差分を修正し、修正内容を返す。
+// actual の取得を始めた時点の version から状態が書き換わっていた場合は、
+// Webhookによる更新を巻き戻さないよう何もせず false を返す。
+func (s *RoomStateStore) Reconcile(actual []models.RoomWithParticipants, version uint64) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.version != version {
+		return nil, false
+	}
+
+	changes := make([]string, 0)
+	next := make(map[uuid.UUID]*models.RoomWithParticipants, len(actual))
+	for _, room := range actual {
+		room := copyRoom(&room)
+		next[room.RoomId] = &room
+
+		current, ok := s.rooms[room.RoomId]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("room added: room=%s, participants=%d", room.RoomId, len(room.Participants)))
+			continue
+		}
+		// egress / ingress はルーム一覧からは取れないので Webhook で得たものを引き継ぐ
+		room.Egresses = current.Egresses
+		room.Ingresses = current.Ingresses
+		if stringValue(current.Metadata) != stringValue(room.Metadata) || boolValue(current.IsWebinar) != boolValue(room.IsWebinar) {
+			changes = append(changes, fmt.Sprintf("metadata updated: room=%s", room.RoomId))
+		}
+		changes = append(changes, diffParticipants(room.RoomId, current.Participants, room.Participants)...)
+	}
+	for roomID, current := range s.rooms {
+		if _, ok := next[roomID]; !ok {
+			changes = append(changes, fmt.Sprintf("room removed: room=%s, participants=%d", roomID, len(current.Participants)))
+		}
+	}
+
+	if len(changes) > 0 {
+		s.version++
+		s.rooms = next
+	}
+	return changes, true
+}
+
+// diffParticipants は参加者一覧の差分を人が読める形で返す
+func diffParticipants(roomID uuid.UUID, current, actual []models.Participant) []string {
+	changes := make([]string, 0)
+	currentByIdentity := make(map[string]models.Participant, len(current))
+	for _, p := range current {
+		currentByIdentity[stringValue(p.Identity)] = p
+	}
+	for _, p := range actual {
+		identity := stringValue(p.Identity)
+		c, ok := currentByIdentity[identity]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("participant added: room=%s, identity=%s", roomID, identity))
+			continue
+		}
+		delete(currentByIdentity, identity)
+		if stringValue(c.Name) != stringValue(p.Name) || boolValue(c.CanPublish) != boolValue(p.CanPublish) {
+			changes = append(changes, fmt.Sprintf("participant updated: room=%s, identity=%s", roomID, identity))
+		}
+	}
+	for identity := range currentByIdentity {
+		changes = append(changes, fmt.Sprintf("participant removed: room=%s, identity=%s", roomID, identity))
+	}
+	return changes
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
+
+func sameIdentity(a, b models.Participant) bool {
+	return a.Identity != nil && b.Identity != nil && *a.Identity == *b.Identity
+}
+
+// copyRoom は参加者・egress・ingress のスライスまで含めてルームを複製する
+func copyRoom(room *models.RoomWithParticipants) models.RoomWithParticipants {
+	c := *room
+	c.Participants = make([]models.Participant, 0, len(room.Participants))
+	for _, p := range room.Participants {
+		c.Participants = append(c.Participants, copyParticipant(p))
+	}
+	if room.Egresses != nil {
+		egresses := make([]models.Egress, len(*room.Egresses))
+		copy(egresses, *room.Egresses)
+		c.Egresses = &egresses
+	}
+	if room.Ingresses != nil {
+		ingresses := make([]models.Ingress, len(*room.Ingresses))
+		copy(ingresses, *room.Ingresses)
+		c.Ingresses = &ingresses
+	}
+	return c
+}
+
+// copyParticipant は参加者を複製する。ポインタ先は書き換えずに差し替える前提なので、
+// 共有されうる attributes の map と tracks のスライスだけを複製する。
+// 変更の有無を reflect.DeepEqual で比べるので、空のスライスは nil にせず空のまま複製する。
+func copyParticipant(p models.Participant) models.Participant {
+	if p.Tracks != nil {
+		tracks := make([]models.Track, len(*p.Tracks))
+		copy(tracks, *p.Tracks)
+		p.Tracks = &tracks
+	}
+	if p.Attributes != nil {
+		attributes := make(map[string]string, len(*p.Attributes))
+		for k, v := range *p.Attributes {
+			attributes[k] = v
+		}
+		p.Attributes = &attributes
+	}
+	return p
+}
@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// checkRoomsConsistent はスナップショットのルームがID順に並び、同じ identity の参加者が重複していないことを確認する
+func checkRoomsConsistent(t *testing.T, rooms []models.RoomWithParticipants) {
+	t.Helper()
+	for i, room := range rooms {
+		if i > 0 && rooms[i-1].RoomId.String() >= room.RoomId.String() {
+			t.Errorf("rooms are not sorted by id: %s before %s", rooms[i-1].RoomId, room.RoomId)
+		}
+		seen := make(map[string]bool, len(room.Participants))
+		for _, p := range room.Participants {
+			identity := stringValue(p.Identity)
+			if seen[identity] {
+				t.Errorf("room %s has duplicate participant %s", room.RoomId, identity)
+			}
+			seen[identity] = true
+		}
+	}
+}
+
+// TestRoomStateStoreConcurrent は PutRoom / UpsertParticipant / RemoveParticipant / Snapshot を同時に呼び、
+// 最終的な状態と、状態を変えた書き込みのたびに1つずつ増える version が一致することを確認する (go test -race で実行する)
+func TestRoomStateStoreConcurrent(t *testing.T) {
+	const (
+		rooms        = 8
+		participants = 16
+	)
+	store := NewRoomStateStore()
+	roomIDs := make([]uuid.UUID, rooms)
+	for i := range roomIDs {
+		roomIDs[i] = uuid.New()
+	}
+
+	var writes sync.WaitGroup
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			var last uint64
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				version := store.Version()
+				if version < last {
+					t.Errorf("version went back from %d to %d", last, version)
+				}
+				last = version
+				checkRoomsConsistent(t, store.Snapshot())
+			}
+		}()
+	}
+
+	var calls uint64
+	var callsMu sync.Mutex
+	count := func(n uint64) {
+		callsMu.Lock()
+		calls += n
+		callsMu.Unlock()
+	}
+	for _, roomID := range roomIDs {
+		for i := range participants {
+			writes.Add(1)
+			go func() {
+				defer writes.Done()
+				identity := fmt.Sprintf("user%d", i)
+				status := fmt.Sprintf("status%d", i)
+				store.PutRoom(models.RoomWithParticipants{RoomId: roomID, Metadata: &status, Participants: []models.Participant{}})
+				store.UpsertParticipant(roomID, models.Participant{Identity: &identity})
+				// 同じ内容で置き換えても状態は変わらないので version は進まない
+				store.UpsertParticipant(roomID, models.Participant{Identity: &identity})
+				n := uint64(2)
+				// 奇数番目の参加者は退出する
+				if i%2 == 1 {
+					if !store.RemoveParticipant(roomID, identity) {
+						t.Errorf("participant %s in room %s was not found", identity, roomID)
+					}
+					n++
+				}
+				count(n)
+			}()
+		}
+	}
+	writes.Wait()
+	close(done)
+	readers.Wait()
+
+	if got := store.Version(); got != calls {
+		t.Errorf("version = %d, want %d (one per write that changed the state)", got, calls)
+	}
+	snapshot := store.Snapshot()
+	checkRoomsConsistent(t, snapshot)
+	if len(snapshot) != rooms {
+		t.Fatalf("len(snapshot) = %d, want %d", len(snapshot), rooms)
+	}
+	for _, room := range snapshot {
+		if len(room.Participants) != participants/2 {
+			t.Errorf("room %s has %d participants, want %d", room.RoomId, len(room.Participants), participants/2)
+		}
+		for _, p := range room.Participants {
+			var i int
+			if _, err := fmt.Sscanf(stringValue(p.Identity), "user%d", &i); err != nil || i%2 == 1 {
+				t.Errorf("room %s has unexpected participant %s", room.RoomId, stringValue(p.Identity))
+			}
+		}
+	}
+}
+
+// TestApplyWebhookEventConcurrent はルームの開始・参加・退出の Webhook を順不同に同時に反映し、
+// 発生時刻に従った状態になること、再送しても version が進まないこと、その状態と version で Reconcile しても差分が出ないことを確認する
+func TestApplyWebhookEventConcurrent(t *testing.T) {
+	const (
+		rooms        = 4
+		participants = 16
+	)
+	r := &Repository{
+		RoomState:    NewRoomStateStore(),
+		webhookOrder: newWebhookOrder(),
+	}
+
+	// 参加者 i は時刻 100+i に参加し、奇数番目は時刻 200+i に退出する
+	var events []*livekit.WebhookEvent
+	for range rooms {
+		room := &livekit.Room{Name: uuid.NewString()}
+		events = append(events, &livekit.WebhookEvent{Event: webhook.EventRoomStarted, Room: room, CreatedAt: 50})
+		for i := range participants {
+			participant := &livekit.ParticipantInfo{Identity: fmt.Sprintf("user%d", i), Name: fmt.Sprintf("User %d", i)}
+			events = append(events, &livekit.WebhookEvent{Event: webhook.EventParticipantJoined, Room: room, Participant: participant, CreatedAt: int64(100 + i)})
+			if i%2 == 1 {
+				events = append(events, &livekit.WebhookEvent{Event: webhook.EventParticipantLeft, Room: room, Participant: participant, CreatedAt: int64(200 + i)})
+			}
+		}
+	}
+	rand.Shuffle(len(events), func(i, j int) { events[i], events[j] = events[j], events[i] })
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for _, event := range events {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			r.ApplyWebhookEvent(event)
+			checkRoomsConsistent(t, r.RoomState.Snapshot())
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	version := r.RoomState.Version()
+	snapshot := r.RoomState.Snapshot()
+	checkRoomsConsistent(t, snapshot)
+	if len(snapshot) != rooms {
+		t.Fatalf("len(snapshot) = %d, want %d", len(snapshot), rooms)
+	}
+	for _, room := range snapshot {
+		if len(room.Participants) != participants/2 {
+			t.Errorf("room %s has %d participants, want %d", room.RoomId, len(room.Participants), participants/2)
+		}
+		for _, p := range room.Participants {
+			var i int
+			if _, err := fmt.Sscanf(stringValue(p.Identity), "user%d", &i); err != nil || i%2 == 1 {
+				t.Errorf("room %s has unexpected participant %s", room.RoomId, stringValue(p.Identity))
+			}
+		}
+	}
+
+	// 再送されたイベントは状態を変えないので version も進まない
+	for _, event := range events {
+		r.ApplyWebhookEvent(event)
+	}
+	if got := r.RoomState.Version(); got != version {
+		t.Errorf("version = %d after redelivering the events, want %d", got, version)
+	}
+	changes, ok := r.RoomState.Reconcile(snapshot, version)
+	if !ok {
+		t.Fatalf("Reconcile with the current version was rejected (version %d, now %d)", version, r.RoomState.Version())
+	}
+	if len(changes) != 0 {
+		t.Errorf("snapshot differs from the state it was taken from: %v", changes)
+	}
+	if got := r.RoomState.Version(); got != version {
+		t.Errorf("version changed from %d to %d without changes", version, got)
+	}
+}
+
+// TestReconcileRoomStateRetry は LiveKit から取得している間に状態が変わった場合、取り直して突き合わせること、
+// 変わり続けた場合は reconcileMaxAttempts 回で諦めてエラーを返すことを確認する
+func TestReconcileRoomStateRetry(t *testing.T) {
+	roomID := uuid.New()
+	actual := []models.RoomWithParticipants{{RoomId: roomID, Participants: []models.Participant{}}}
+
+	tests := []struct {
+		name        string
+		changes     int // 取得中に状態が変わる回数
+		wantErr     bool
+		wantFetches int
+	}{
+		{"no concurrent change", 0, false, 1},
+		{"changed once", 1, false, 2},
+		{"changed until the last attempt", reconcileMaxAttempts - 1, false, reconcileMaxAttempts},
+		{"always changed", reconcileMaxAttempts, true, reconcileMaxAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Repository{RoomState: NewRoomStateStore()}
+			fetches := 0
+			fetch := func(context.Context) ([]models.RoomWithParticipants, error) {
+				fetches++
+				if fetches <= tt.changes {
+					// 取得中に届いた Webhook の代わり
+					r.RoomState.PutRoom(models.RoomWithParticipants{RoomId: uuid.New(), Participants: []models.Participant{}})
+				}
+				return actual, nil
+			}
+
+			changes, err := r.reconcileRoomState(context.Background(), fetch)
+			if fetches != tt.wantFetches {
+				t.Errorf("fetched %d times, want %d", fetches, tt.wantFetches)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("reconcileRoomState succeeded, want an error after giving up")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) == 0 {
+				t.Error("reconcileRoomState returned no changes")
+			}
+			snapshot := r.RoomState.Snapshot()
+			if len(snapshot) != 1 || snapshot[0].RoomId != roomID {
+				t.Errorf("snapshot = %v, want only room %s", snapshot, roomID)
+			}
+		})
+	}
+}
+
+func TestReconcileRoomStateFetchError(t *testing.T) {
+	r := &Repository{RoomState: NewRoomStateStore()}
+	wantErr := errors.New("livekit is down")
+	fetches := 0
+	_, err := r.reconcileRoomState(context.Background(), func(context.Context) ([]models.RoomWithParticipants, error) {
+		fetches++
+		return nil, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+	if fetches != 1 {
+		t.Errorf("fetched %d times, want 1 (fetch errors are not retried)", fetches)
+	}
+}
+
+// TestApplyWebhookEventUnhandled はルーム状態に関係しない種類のイベントを、古いイベントとは区別して返すことを確認する
+func TestApplyWebhookEventUnhandled(t *testing.T) {
+	r := &Repository{
+		RoomState:    NewRoomStateStore(),
+		webhookOrder: newWebhookOrder(),
+	}
+	room := &livekit.Room{Name: uuid.NewString()}
+
+	result := r.ApplyWebhookEvent(&livekit.WebhookEvent{Event: "unknown_event", Room: room, CreatedAt: 100})
+	if result.Applied || !result.Unhandled {
+		t.Errorf("unknown event: Applied = %v, Unhandled = %v, want false, true", result.Applied, result.Unhandled)
+	}
+
+	if result := r.ApplyWebhookEvent(&livekit.WebhookEvent{Event: webhook.EventRoomStarted, Room: room, CreatedAt: 100}); !result.Applied || result.Unhandled {
+		t.Errorf("room_started: Applied = %v, Unhandled = %v, want true, false", result.Applied, result.Unhandled)
+	}
+	// より新しいイベントの後に届いたものは Unhandled ではなく無視される
+	if result := r.ApplyWebhookEvent(&livekit.WebhookEvent{Event: webhook.EventRoomFinished, Room: room, CreatedAt: 200}); !result.Applied {
+		t.Fatal("room_finished was not applied")
+	}
+	if result := r.ApplyWebhookEvent(&livekit.WebhookEvent{Event: webhook.EventRoomStarted, Room: room, CreatedAt: 150}); result.Applied || result.Unhandled {
+		t.Errorf("stale room_started: Applied = %v, Unhandled = %v, want false, false", result.Applied, result.Unhandled)
+	}
+}
+
+// TestApplyWebhookEventMissingTarget は対象 (参加者・トラック・Egress・Ingress 等) を含まないイベントを panic せずに無視することを確認する
+func TestApplyWebhookEventMissingTarget(t *testing.T) {
+	r := &Repository{
+		RoomState:    NewRoomStateStore(),
+		webhookOrder: newWebhookOrder(),
+	}
+	room := &livekit.Room{Name: uuid.NewString()}
+	participant := &livekit.ParticipantInfo{Identity: "user"}
+	if result := r.ApplyWebhookEvent(&livekit.WebhookEvent{Event: webhook.EventRoomStarted, Room: room, CreatedAt: 100}); !result.Applied {
+		t.Fatal("room_started was not applied")
+	}
+	version := r.RoomState.Version()
+
+	events := []*livekit.WebhookEvent{
+		{Event: webhook.EventRoomStarted},
+		{Event: webhook.EventRoomFinished},
+		{Event: webhook.EventParticipantJoined, Room: room},
+		{Event: webhook.EventParticipantJoined, Room: room, Participant: &livekit.ParticipantInfo{}},
+		{Event: webhook.EventParticipantJoined, Participant: participant},
+		{Event: webhook.EventParticipantLeft, Room: room},
+		{Event: webhook.EventTrackPublished, Room: room, Track: &livekit.TrackInfo{Sid: "TR_1"}},
+		{Event: webhook.EventTrackPublished, Room: room, Participant: participant},
+		{Event: webhook.EventTrackUnpublished, Room: room, Participant: participant},
+		{Event: webhook.EventEgressStarted, Room: room},
+		{Event: webhook.EventEgressUpdated, EgressInfo: &livekit.EgressInfo{RoomName: room.Name}},
+		{Event: webhook.EventEgressEnded},
+		{Event: webhook.EventIngressStarted, Room: room},
+		{Event: webhook.EventIngressEnded, IngressInfo: &livekit.IngressInfo{RoomName: room.Name}},
+	}
+	for i, event := range events {
+		event.CreatedAt = int64(200 + i)
+		if result := r.ApplyWebhookEvent(event); result.Applied || result.Unhandled {
+			t.Errorf("%s without target (#%d): Applied = %v, Unhandled = %v, want false, false", event.Event, i, result.Applied, result.Unhandled)
+		}
+	}
+	if got := r.RoomState.Version(); got != version {
+		t.Errorf("version = %d, want %d (events without target must not change the room state)", got, version)
+	}
+}
+
+// TestRoomStateStoreNoOpWrites は状態を変えない書き込みでは version が進まないことを確認する
+func TestRoomStateStoreNoOpWrites(t *testing.T) {
+	store := NewRoomStateStore()
+	roomID := uuid.New()
+	identity := "alice_" + uuid.NewString()
+	name := "Alice"
+	status := "status"
+	canPublish := true
+	store.PutRoom(models.RoomWithParticipants{RoomId: roomID, Metadata: &status, Participants: []models.Participant{}})
+	store.UpsertParticipant(roomID, models.Participant{Identity: &identity, Name: &name, Tracks: &[]models.Track{}})
+
+	writes := []struct {
+		name    string
+		write   func()
+		changes bool
+	}{
+		{"same room", func() {
+			store.PutRoom(models.RoomWithParticipants{RoomId: roomID, Metadata: &status, Participants: []models.Participant{}})
+		}, false},
+		{"same metadata", func() { store.SetMetadata(roomID, status) }, false},
+		{"same participant", func() {
+			store.UpsertParticipant(roomID, models.Participant{Identity: &identity, Name: &name, Tracks: &[]models.Track{}})
+		}, false},
+		{"unchanged room", func() { store.UpdateRoom(roomID, func(*models.RoomWithParticipants) {}) }, false},
+		{"unchanged participant", func() { store.UpdateParticipant(roomID, identity, func(*models.Participant) {}) }, false},
+		{"missing room", func() { store.RemoveRoom(uuid.New()) }, false},
+		{"missing room metadata", func() { store.SetMetadata(uuid.New(), status) }, false},
+		{"missing room update", func() { store.UpdateRoom(uuid.New(), func(*models.RoomWithParticipants) {}) }, false},
+		{"missing participant update", func() { store.UpdateParticipant(roomID, "bob", func(*models.Participant) {}) }, false},
+		{"missing participant", func() { store.RemoveParticipant(roomID, "bob") }, false},
+		{"participant in missing room", func() { store.RemoveParticipant(uuid.New(), identity) }, false},
+		{"new metadata", func() { store.SetMetadata(roomID, "new status") }, true},
+		{"new participant permission", func() {
+			store.UpdateParticipant(roomID, identity, func(p *models.Participant) { p.CanPublish = &canPublish })
+		}, true},
+		{"remove participant", func() { store.RemoveParticipant(roomID, identity) }, true},
+		{"remove room", func() { store.RemoveRoom(roomID) }, true},
+	}
+	for _, w := range writes {
+		before := store.Version()
+		w.write()
+		if changed := store.Version() != before; changed != w.changes {
+			t.Errorf("%s: version changed = %v, want %v", w.name, changed, w.changes)
+		}
+	}
+}
@@ -1,54 +1,209 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
 )
 
+// ErrDuplicateSound は同じ内容 (content_hash) のファイルを同じ切り出し方で登録したサウンドが既にある時に返す
+var ErrDuplicateSound = errors.New("sound with the same content already exists")
+
+// SoundWaveformPoints は保存する波形のプレビューの点の数
+const SoundWaveformPoints = 200
+
 // Sound は DB上の sounds テーブルに対応する構造体です
 type Sound struct {
 	SoundID   string `db:"sound_id"`   // UUIDを文字列で扱う
 	SoundName string `db:"sound_name"` // サウンド名
 	StampID   string `db:"stamp_id"`   // スタンプID（任意）
 	CreatorID string `db:"creator_id"` // 作成者のID
+	// OriginalKey はアップロードされた加工前のファイルのキー (加工後のファイルのキーは SoundID)
+	OriginalKey string `db:"original_key"`
+	// DurationMS は加工後の音声の長さ (ミリ秒)。長さを記録する前にアップロードされたサウンドは 0
+	DurationMS int64     `db:"duration_ms"`
+	CreatedAt  time.Time `db:"created_at"`
+	// Waveform は加工後の波形のプレビュー (ピークを 0〜255 にしたもの)。記録する前にアップロードされたサウンドは nil
+	Waveform []byte `db:"waveform"`
+	// PreviewKey は試聴用に小さくエンコードしたファイルのキー。作る前にアップロードされたサウンドは空
+	PreviewKey string `db:"preview_key"`
+	// HiddenAt は通報が一定数に達して非表示になった日時。表示されているサウンドは NULL
+	HiddenAt sql.NullTime `db:"hidden_at"`
+	// StoredBytes は保存しているファイル (加工後・元・試聴用) の合計サイズ。記録する前に登録されたサウンドは 0
+	StoredBytes int64 `db:"stored_bytes"`
+	SoundSource
+	SoundClip
 }
 
-// InsertSoundboardItem は (soundId, soundName, stampId, creatorId) を sounds テーブルへ登録します
-func (r *Repository) InsertSoundboardItem(soundID, soundName, stampID, creatorID string) error {
-	_, err := r.db.Exec(`
-		INSERT INTO sounds (sound_id, sound_name, stamp_id, creator_id)
-		VALUES (?, ?, ?, ?)
-	`, soundID, soundName, stampID, creatorID)
+// SoundSource はアップロードされた加工前のファイルの情報。記録する前にアップロードされたサウンドはゼロ値
+type SoundSource struct {
+	// ContentHash はファイルの SHA-256 (16進数)。同じファイルの重複登録を防ぐのに使う
+	ContentHash string `db:"content_hash"`
+	SizeBytes   int64  `db:"size_bytes"`
+	// Codec は mp3 / pcm / vorbis / opus / flac / aac
+	Codec      string `db:"codec"`
+	SampleRate int    `db:"sample_rate"`
+	Channels   int    `db:"channels"`
+	// SourceDurationMS は加工前の長さ (ミリ秒)
+	SourceDurationMS int64 `db:"source_duration_ms"`
+}
+
+// SoundClip はアップロード時に指定された、元のファイルから切り出す範囲とフェード (ミリ秒)
+type SoundClip struct {
+	ClipStartMS int64 `db:"clip_start_ms"`
+	// ClipEndMS は 0 の場合は最後まで
+	ClipEndMS int64 `db:"clip_end_ms"`
+	FadeInMS  int64 `db:"fade_in_ms"`
+	FadeOutMS int64 `db:"fade_out_ms"`
+}
+
+// soundColumns は sounds テーブル (別名 s) から Sound を取得する時の列
+const soundColumns = `s.sound_id, s.sound_name, s.stamp_id, s.creator_id, s.original_key, s.duration_ms, s.created_at, s.waveform, s.preview_key, s.hidden_at, s.stored_bytes,
+		COALESCE(s.content_hash, '') AS content_hash, s.size_bytes, s.codec, s.sample_rate, s.channels, s.source_duration_ms,
+		s.clip_start_ms, s.clip_end_ms, s.fade_in_ms, s.fade_out_ms`
+
+// SoundPreviewKey は試聴用のファイルのキー (加工後のファイルの隣に置く) を返す。ext は "." から始まる拡張子
+func SoundPreviewKey(soundID, ext string) string {
+	return soundID + ".preview" + ext
+}
+
+// EncodeWaveform は 0〜1 のピークの配列を DB に保存するために 0〜255 にする
+func EncodeWaveform(peaks []float64) []byte {
+	if peaks == nil {
+		return nil
+	}
+	waveform := make([]byte, len(peaks))
+	for i, v := range peaks {
+		waveform[i] = byte(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return waveform
+}
+
+// InsertSoundboardItem はサウンドを sounds テーブルへ登録します。
+// 同じ内容のファイルを同じ切り出し方で登録したサウンドが既にある場合は ErrDuplicateSound を、
+// 登録すると quota を超える場合は *SoundQuotaError を返します
+func (r *Repository) InsertSoundboardItem(sound Sound, quota config.SoundQuota) error {
+	err := r.withSoundQuotaLock(func(tx *sqlx.Tx) error {
+		return insertSoundWithinQuota(tx, sound, quota)
+	})
 	if err != nil {
 		return fmt.Errorf("insert soundboard item: %w", err)
 	}
 	return nil
 }
 
-// GetAllSoundboards は sounds テーブルのレコードを全て取得します
-func (r *Repository) GetAllSoundboards() ([]Sound, error) {
+func insertSound(db sqlx.Execer, sound Sound) error {
+	_, err := db.Exec(`
+		INSERT INTO sounds (sound_id, sound_name, stamp_id, creator_id, original_key, duration_ms, waveform, preview_key, stored_bytes,
+			content_hash, size_bytes, codec, sample_rate, channels, source_duration_ms,
+			clip_start_ms, clip_end_ms, fade_in_ms, fade_out_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, sound.SoundID, sound.SoundName, sound.StampID, sound.CreatorID, sound.OriginalKey, sound.DurationMS, sound.Waveform, sound.PreviewKey, sound.StoredBytes,
+		sound.ContentHash, sound.SizeBytes, sound.Codec, sound.SampleRate, sound.Channels, sound.SourceDurationMS,
+		sound.ClipStartMS, sound.ClipEndMS, sound.FadeInMS, sound.FadeOutMS)
+	if isDuplicateKey(err) {
+		return ErrDuplicateSound
+	}
+	return err
+}
+
+// isDuplicateKey は一意制約の違反 (ER_DUP_ENTRY) かを返す
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// GetSoundboardBySource は内容が contentHash のファイルを clip の切り出し方で登録したサウンドを取得します。
+// 存在しない場合は sql.ErrNoRows をラップして返します
+func (r *Repository) GetSoundboardBySource(contentHash string, clip SoundClip) (Sound, error) {
+	var sound Sound
+	if err := r.db.Get(&sound, `
+		SELECT `+soundColumns+`
+		FROM sounds s
+		WHERE s.content_hash = ? AND s.clip_start_ms = ? AND s.clip_end_ms = ? AND s.fade_in_ms = ? AND s.fade_out_ms = ?
+	`, contentHash, clip.ClipStartMS, clip.ClipEndMS, clip.FadeInMS, clip.FadeOutMS); err != nil {
+		return Sound{}, fmt.Errorf("select sound by content_hash: %w", err)
+	}
+	return sound, nil
+}
+
+// GetSoundboardByID は指定された sound_id のサウンドを取得します。存在しない場合は sql.ErrNoRows をラップして返します
+func (r *Repository) GetSoundboardByID(soundID string) (Sound, error) {
+	var sound Sound
+	if err := r.db.Get(&sound, `
+		SELECT `+soundColumns+`
+		FROM sounds s
+		WHERE s.sound_id = ?
+	`, soundID); err != nil {
+		return Sound{}, fmt.Errorf("select sound by sound_id: %w", err)
+	}
+	return sound, nil
+}
+
+// GetSoundsWithoutPreview は sound_id が afterID より後で、試聴用のファイルか SoundWaveformPoints 点の波形が無いサウンドを
+// sound_id 順に最大 limit 件取得します
+func (r *Repository) GetSoundsWithoutPreview(afterID string, limit int) ([]Sound, error) {
 	var sounds []Sound
 	if err := r.db.Select(&sounds, `
-		SELECT sound_id, sound_name, stamp_id, creator_id
-		FROM sounds
-	`); err != nil {
-		return nil, fmt.Errorf("select sounds: %w", err)
+		SELECT `+soundColumns+`
+		FROM sounds s
+		WHERE s.sound_id > ? AND (s.preview_key = '' OR s.waveform IS NULL OR LENGTH(s.waveform) <> ?)
+		ORDER BY s.sound_id
+		LIMIT ?
+	`, afterID, SoundWaveformPoints, limit); err != nil {
+		return nil, fmt.Errorf("select sounds without preview: %w", err)
 	}
 	return sounds, nil
 }
 
-// GetSoundboardByCreatorID は指定された creator_id に関連する sounds を取得します
-func (r *Repository) GetSoundboardByCreatorID(creatorID string) ([]Sound, error) {
+// UpdateSoundPreview は指定された sound_id の波形のプレビューと試聴用のファイルのキー、保存しているファイルの合計サイズを更新します
+func (r *Repository) UpdateSoundPreview(soundID string, waveform []byte, previewKey string, storedBytes int64) error {
+	_, err := r.db.Exec(`
+		UPDATE sounds
+		SET waveform = ?, preview_key = ?, stored_bytes = ?
+		WHERE sound_id = ?
+	`, waveform, previewKey, storedBytes, soundID)
+	if err != nil {
+		return fmt.Errorf("update sound preview: %w", err)
+	}
+	return nil
+}
+
+// GetSoundsWithoutStoredBytes は sound_id が afterID より後で、保存しているファイルの合計サイズを記録していないサウンドを
+// sound_id 順に最大 limit 件取得します
+func (r *Repository) GetSoundsWithoutStoredBytes(afterID string, limit int) ([]Sound, error) {
 	var sounds []Sound
 	if err := r.db.Select(&sounds, `
-		SELECT sound_id, sound_name, stamp_id, creator_id
-		FROM sounds
-		WHERE creator_id = ?
-	`, creatorID); err != nil {
-		return nil, fmt.Errorf("select sounds by creator_id: %w", err)
+		SELECT `+soundColumns+`
+		FROM sounds s
+		WHERE s.sound_id > ? AND s.stored_bytes = 0
+		ORDER BY s.sound_id
+		LIMIT ?
+	`, afterID, limit); err != nil {
+		return nil, fmt.Errorf("select sounds without stored_bytes: %w", err)
 	}
 	return sounds, nil
 }
 
+// UpdateSoundStoredBytes は指定された sound_id の保存しているファイルの合計サイズを更新します
+func (r *Repository) UpdateSoundStoredBytes(soundID string, storedBytes int64) error {
+	_, err := r.db.Exec(`
+		UPDATE sounds
+		SET stored_bytes = ?
+		WHERE sound_id = ?
+	`, storedBytes, soundID)
+	if err != nil {
+		return fmt.Errorf("update sound stored_bytes: %w", err)
+	}
+	return nil
+}
+
 // EditSoundboardCreatorID は指定された sound_id の creator_id を更新します
 func (r *Repository) EditSoundboardCreatorID(soundID, creatorID string) error {
 	_, err := r.db.Exec(`
@@ -62,14 +217,55 @@ func (r *Repository) EditSoundboardCreatorID(soundID, creatorID string) error {
 	return nil
 }
 
-// DeleteSoundboardItem は指定された sound_id のレコードを削除します
-func (r *Repository) DeleteSoundboardItem(soundID string) error {
-	_, err := r.db.Exec(`
-		DELETE FROM sounds
+// UpdateSoundboardItem は指定された sound_id の sound_name と stamp_id を更新し、tags が nil でなければタグも置き換えます。
+// 一部だけ更新されることが無いよう、同じトランザクションで行います
+func (r *Repository) UpdateSoundboardItem(soundID, soundName, stampID string, tags []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		UPDATE sounds
+		SET sound_name = ?, stamp_id = ?
 		WHERE sound_id = ?
-	`, soundID)
+	`, soundName, stampID, soundID); err != nil {
+		return fmt.Errorf("update soundboard item: %w", err)
+	}
+	if tags != nil {
+		if err := replaceSoundTags(tx, soundID, tags); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update soundboard item: %w", err)
+	}
+	return nil
+}
+
+// DeleteSoundboardItem は指定された sound_id のレコードを、タグ・お気に入り・パックへの登録・再生記録・通報と共に削除します
+func (r *Repository) DeleteSoundboardItem(soundID string) error {
+	tx, err := r.db.Beginx()
 	if err != nil {
-		return fmt.Errorf("delete soundboard item: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, query := range []string{
+		`DELETE FROM sound_tags WHERE sound_id = ?`,
+		`DELETE FROM sound_favorites WHERE sound_id = ?`,
+		`DELETE FROM sound_pack_items WHERE sound_id = ?`,
+		`DELETE FROM sound_plays WHERE sound_id = ?`,
+		`DELETE FROM sound_reports WHERE sound_id = ?`,
+		`DELETE FROM sounds WHERE sound_id = ?`,
+	} {
+		if _, err := tx.Exec(query, soundID); err != nil {
+			return fmt.Errorf("delete soundboard item: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete soundboard item: %w", err)
 	}
 	return nil
 }
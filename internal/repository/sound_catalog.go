@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SoundCursor はサウンド一覧の続きを取得するための位置 (最後に返したサウンド)
+type SoundCursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	SoundID   string    `json:"soundId"`
+}
+
+// SoundFilter はサウンド一覧の絞り込み条件。空の項目では絞り込まない
+type SoundFilter struct {
+	// Query はサウンド名の検索語
+	Query     string
+	Tag       string
+	CreatorID string
+	// FavoriteOf を指定すると、そのユーザがお気に入りにしたサウンドだけを返す
+	FavoriteOf string
+	PackID     string
+	// ChannelID を指定すると、チャンネルで有効なパックのサウンドだけを返す (有効なパックが無いチャンネルでは絞り込まない)
+	ChannelID string
+	// Limit が 0 の場合は全件返す
+	Limit  int
+	Cursor *SoundCursor
+}
+
+// ListSounds は条件に合うサウンドを新しい順に取得します。通報により非表示になったサウンドは含みません
+func (r *Repository) ListSounds(filter SoundFilter) ([]Sound, error) {
+	if filter.Limit < 0 {
+		return nil, fmt.Errorf("invalid sound list limit: %d", filter.Limit)
+	}
+	var (
+		conditions = []string{`s.hidden_at IS NULL`}
+		args       []interface{}
+	)
+	if filter.Query != "" {
+		conditions = append(conditions, `(MATCH (s.sound_name) AGAINST (? IN BOOLEAN MODE) OR s.sound_name LIKE ?)`)
+		args = append(args, fullTextQuery(filter.Query), "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM sound_tags t WHERE t.sound_id = s.sound_id AND t.tag = ?)`)
+		args = append(args, filter.Tag)
+	}
+	if filter.CreatorID != "" {
+		conditions = append(conditions, `s.creator_id = ?`)
+		args = append(args, filter.CreatorID)
+	}
+	if filter.FavoriteOf != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM sound_favorites f WHERE f.sound_id = s.sound_id AND f.user_id = ?)`)
+		args = append(args, filter.FavoriteOf)
+	}
+	if filter.PackID != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM sound_pack_items p WHERE p.sound_id = s.sound_id AND p.pack_id = ?)`)
+		args = append(args, filter.PackID)
+	}
+	if filter.ChannelID != "" {
+		conditions = append(conditions, `(
+			NOT EXISTS (SELECT 1 FROM channel_sound_packs c WHERE c.channel_id = ?)
+			OR EXISTS (
+				SELECT 1 FROM sound_pack_items p
+				JOIN channel_sound_packs c ON c.pack_id = p.pack_id
+				WHERE p.sound_id = s.sound_id AND c.channel_id = ?
+			)
+		)`)
+		args = append(args, filter.ChannelID, filter.ChannelID)
+	}
+	if filter.Cursor != nil {
+		conditions = append(conditions, `(s.created_at < ? OR (s.created_at = ? AND s.sound_id < ?))`)
+		args = append(args, filter.Cursor.CreatedAt, filter.Cursor.CreatedAt, filter.Cursor.SoundID)
+	}
+
+	query := `
+		SELECT ` + soundColumns + `
+		FROM sounds s
+		WHERE ` + strings.Join(conditions, "\n\t\tAND ")
+	query += "\n\t\tORDER BY s.created_at DESC, s.sound_id DESC"
+	if filter.Limit > 0 {
+		query += "\n\t\tLIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	sounds := []Sound{}
+	if err := r.db.Select(&sounds, query, args...); err != nil {
+		return nil, fmt.Errorf("select sounds: %w", err)
+	}
+	return sounds, nil
+}
+
+// fullTextQuery は検索語を BOOLEAN MODE の全文検索の式にする (全ての語を前方一致で含む)
+func fullTextQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return strings.ContainsRune(` +-<>()~*"@`, r)
+	})
+	for i, word := range words {
+		words[i] = "+" + word + "*"
+	}
+	return strings.Join(words, " ")
+}
+
+// escapeLike は LIKE のワイルドカードをエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// GetSoundTags は指定したサウンドのタグを sound_id ごとに取得します
+func (r *Repository) GetSoundTags(soundIDs []string) (map[string][]string, error) {
+	tags := make(map[string][]string, len(soundIDs))
+	if len(soundIDs) == 0 {
+		return tags, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT sound_id, tag
+		FROM sound_tags
+		WHERE sound_id IN (?)
+		ORDER BY tag
+	`, soundIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build sound tags query: %w", err)
+	}
+	var rows []struct {
+		SoundID string `db:"sound_id"`
+		Tag     string `db:"tag"`
+	}
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("select sound tags: %w", err)
+	}
+	for _, row := range rows {
+		tags[row.SoundID] = append(tags[row.SoundID], row.Tag)
+	}
+	return tags, nil
+}
+
+// replaceSoundTags はサウンドのタグを tags で置き換えます
+func replaceSoundTags(tx sqlx.Execer, soundID string, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM sound_tags WHERE sound_id = ?`, soundID); err != nil {
+		return fmt.Errorf("delete sound tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT INTO sound_tags (sound_id, tag) VALUES (?, ?)`, soundID, tag); err != nil {
+			return fmt.Errorf("insert sound tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// TagCount はタグとそのタグが付いたサウンドの数
+type TagCount struct {
+	Tag   string `db:"tag"`
+	Count int    `db:"count"`
+}
+
+// GetAllSoundTags は使われている全てのタグを、付いているサウンドが多い順に取得します
+func (r *Repository) GetAllSoundTags() ([]TagCount, error) {
+	tags := []TagCount{}
+	if err := r.db.Select(&tags, `
+		SELECT tag, COUNT(*) AS count
+		FROM sound_tags
+		GROUP BY tag
+		ORDER BY count DESC, tag
+	`); err != nil {
+		return nil, fmt.Errorf("select all sound tags: %w", err)
+	}
+	return tags, nil
+}
+
+// AddSoundFavorite はサウンドをユーザのお気に入りに追加します (既に追加されている場合は何もしません)
+func (r *Repository) AddSoundFavorite(userID, soundID string) error {
+	_, err := r.db.Exec(`
+		INSERT IGNORE INTO sound_favorites (user_id, sound_id, created_at)
+		VALUES (?, ?, ?)
+	`, userID, soundID, time.Now())
+	if err != nil {
+		return fmt.Errorf("insert sound favorite: %w", err)
+	}
+	return nil
+}
+
+// RemoveSoundFavorite はサウンドをユーザのお気に入りから外します
+func (r *Repository) RemoveSoundFavorite(userID, soundID string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM sound_favorites
+		WHERE user_id = ? AND sound_id = ?
+	`, userID, soundID)
+	if err != nil {
+		return fmt.Errorf("delete sound favorite: %w", err)
+	}
+	return nil
+}
+
+// GetFavoriteSoundIDs は指定したサウンドのうち、ユーザがお気に入りにしているものを返します
+func (r *Repository) GetFavoriteSoundIDs(userID string, soundIDs []string) (map[string]bool, error) {
+	favorites := make(map[string]bool)
+	if len(soundIDs) == 0 {
+		return favorites, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT sound_id
+		FROM sound_favorites
+		WHERE user_id = ? AND sound_id IN (?)
+	`, userID, soundIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build sound favorites query: %w", err)
+	}
+	var ids []string
+	if err := r.db.Select(&ids, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("select sound favorites: %w", err)
+	}
+	for _, id := range ids {
+		favorites[id] = true
+	}
+	return favorites, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestListSoundsLimit(t *testing.T) {
+	r := newTestRepository(t)
+	creator := "creator-" + uuid.NewString()
+	for range 3 {
+		insertTestSound(t, r, creator)
+	}
+
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, 3}, // 0 は全件
+		{2, 2},
+		{10, 3},
+	}
+	for _, tt := range tests {
+		sounds, err := r.ListSounds(SoundFilter{CreatorID: creator, Limit: tt.limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sounds) != tt.want {
+			t.Errorf("ListSounds(limit %d) returned %d sounds, want %d", tt.limit, len(sounds), tt.want)
+		}
+	}
+	if _, err := r.ListSounds(SoundFilter{CreatorID: creator, Limit: -1}); err == nil {
+		t.Error("ListSounds accepted a negative limit")
+	}
+}
+
+func TestUpdateSoundboardItem(t *testing.T) {
+	r := newTestRepository(t)
+	sound := insertTestSound(t, r, "creator")
+
+	check := func(wantName string, wantTags []string) {
+		t.Helper()
+		got, err := r.GetSoundboardByID(sound.SoundID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.SoundName != wantName {
+			t.Errorf("sound name = %q, want %q", got.SoundName, wantName)
+		}
+		tags, err := r.GetSoundTags([]string{sound.SoundID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(tags[sound.SoundID], wantTags) {
+			t.Errorf("tags = %v, want %v", tags[sound.SoundID], wantTags)
+		}
+	}
+
+	if err := r.UpdateSoundboardItem(sound.SoundID, "renamed", "", []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	check("renamed", []string{"a", "b"})
+
+	// tags が nil ならタグは変えない
+	if err := r.UpdateSoundboardItem(sound.SoundID, "renamed again", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	check("renamed again", []string{"a", "b"})
+
+	// タグを書き込めなかった場合は名前も変わらない (sound_tags.tag は 64 文字まで)
+	if err := r.UpdateSoundboardItem(sound.SoundID, "not saved", "", []string{strings.Repeat("x", 65)}); err == nil {
+		t.Fatal("UpdateSoundboardItem accepted a tag longer than the column")
+	}
+	check("renamed again", []string{"a", "b"})
+
+	if err := r.UpdateSoundboardItem(sound.SoundID, "renamed again", "", []string{}); err != nil {
+		t.Fatal(err)
+	}
+	check("renamed again", nil)
+}
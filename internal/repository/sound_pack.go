@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SoundPack は DB上の sound_packs テーブルに対応する構造体です (チャンネルごとに有効にできるサウンドのまとまり)
+type SoundPack struct {
+	PackID      string    `db:"pack_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatorID   string    `db:"creator_id"`
+	CreatedAt   time.Time `db:"created_at"`
+	// SoundIDs はパックに含まれるサウンド (sound_pack_items)
+	SoundIDs []string `db:"-"`
+}
+
+// InsertSoundPack はパックとその中のサウンドを登録します
+func (r *Repository) InsertSoundPack(pack SoundPack) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		INSERT INTO sound_packs (pack_id, name, description, creator_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, pack.PackID, pack.Name, pack.Description, pack.CreatorID, pack.CreatedAt); err != nil {
+		return fmt.Errorf("insert sound pack: %w", err)
+	}
+	if err := setSoundPackItems(tx, pack.PackID, pack.SoundIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit sound pack: %w", err)
+	}
+	return nil
+}
+
+// UpdateSoundPack はパックの名前・説明とその中のサウンドを更新します
+func (r *Repository) UpdateSoundPack(pack SoundPack) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		UPDATE sound_packs
+		SET name = ?, description = ?
+		WHERE pack_id = ?
+	`, pack.Name, pack.Description, pack.PackID); err != nil {
+		return fmt.Errorf("update sound pack: %w", err)
+	}
+	if err := setSoundPackItems(tx, pack.PackID, pack.SoundIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit sound pack: %w", err)
+	}
+	return nil
+}
+
+func setSoundPackItems(tx *sqlx.Tx, packID string, soundIDs []string) error {
+	if _, err := tx.Exec(`DELETE FROM sound_pack_items WHERE pack_id = ?`, packID); err != nil {
+		return fmt.Errorf("delete sound pack items: %w", err)
+	}
+	for _, soundID := range soundIDs {
+		if _, err := tx.Exec(`INSERT INTO sound_pack_items (pack_id, sound_id) VALUES (?, ?)`, packID, soundID); err != nil {
+			return fmt.Errorf("insert sound pack item: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteSoundPack はパックを、チャンネルでの有効化と共に削除します
+func (r *Repository) DeleteSoundPack(packID string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, query := range []string{
+		`DELETE FROM channel_sound_packs WHERE pack_id = ?`,
+		`DELETE FROM sound_pack_items WHERE pack_id = ?`,
+		`DELETE FROM sound_packs WHERE pack_id = ?`,
+	} {
+		if _, err := tx.Exec(query, packID); err != nil {
+			return fmt.Errorf("delete sound pack: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete sound pack: %w", err)
+	}
+	return nil
+}
+
+// GetSoundPack は指定された pack_id のパックを取得します。存在しない場合は sql.ErrNoRows をラップして返します
+func (r *Repository) GetSoundPack(packID string) (SoundPack, error) {
+	var pack SoundPack
+	if err := r.db.Get(&pack, `
+		SELECT pack_id, name, description, creator_id, created_at
+		FROM sound_packs
+		WHERE pack_id = ?
+	`, packID); err != nil {
+		return SoundPack{}, fmt.Errorf("select sound pack: %w", err)
+	}
+	packs := []SoundPack{pack}
+	if err := r.fillSoundPackItems(packs); err != nil {
+		return SoundPack{}, err
+	}
+	return packs[0], nil
+}
+
+// GetAllSoundPacks は全てのパックを作成順に取得します
+func (r *Repository) GetAllSoundPacks() ([]SoundPack, error) {
+	packs := []SoundPack{}
+	if err := r.db.Select(&packs, `
+		SELECT pack_id, name, description, creator_id, created_at
+		FROM sound_packs
+		ORDER BY created_at, pack_id
+	`); err != nil {
+		return nil, fmt.Errorf("select sound packs: %w", err)
+	}
+	if err := r.fillSoundPackItems(packs); err != nil {
+		return nil, err
+	}
+	return packs, nil
+}
+
+// GetChannelSoundPacks はチャンネルで有効になっているパックを取得します
+func (r *Repository) GetChannelSoundPacks(channelID string) ([]SoundPack, error) {
+	packs := []SoundPack{}
+	if err := r.db.Select(&packs, `
+		SELECT p.pack_id, p.name, p.description, p.creator_id, p.created_at
+		FROM sound_packs p
+		JOIN channel_sound_packs c ON c.pack_id = p.pack_id
+		WHERE c.channel_id = ?
+		ORDER BY p.created_at, p.pack_id
+	`, channelID); err != nil {
+		return nil, fmt.Errorf("select channel sound packs: %w", err)
+	}
+	if err := r.fillSoundPackItems(packs); err != nil {
+		return nil, err
+	}
+	return packs, nil
+}
+
+// SetChannelSoundPacks はチャンネルで有効なパックを packIDs で置き換えます
+func (r *Repository) SetChannelSoundPacks(channelID string, packIDs []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM channel_sound_packs WHERE channel_id = ?`, channelID); err != nil {
+		return fmt.Errorf("delete channel sound packs: %w", err)
+	}
+	for _, packID := range packIDs {
+		if _, err := tx.Exec(`INSERT INTO channel_sound_packs (channel_id, pack_id) VALUES (?, ?)`, channelID, packID); err != nil {
+			return fmt.Errorf("insert channel sound pack: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit channel sound packs: %w", err)
+	}
+	return nil
+}
+
+// CountExistingSounds は soundIDs のうち sounds テーブルに存在するものの数を返します
+func (r *Repository) CountExistingSounds(soundIDs []string) (int, error) {
+	return r.countExisting(`SELECT COUNT(*) FROM sounds WHERE sound_id IN (?)`, soundIDs)
+}
+
+// CountExistingSoundPacks は packIDs のうち sound_packs テーブルに存在するものの数を返します
+func (r *Repository) CountExistingSoundPacks(packIDs []string) (int, error) {
+	return r.countExisting(`SELECT COUNT(*) FROM sound_packs WHERE pack_id IN (?)`, packIDs)
+}
+
+func (r *Repository) countExisting(query string, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return 0, fmt.Errorf("build count query: %w", err)
+	}
+	var count int
+	if err := r.db.Get(&count, r.db.Rebind(query), args...); err != nil {
+		return 0, fmt.Errorf("count existing rows: %w", err)
+	}
+	return count, nil
+}
+
+// fillSoundPackItems はパックに含まれるサウンドを SoundIDs に設定します
+func (r *Repository) fillSoundPackItems(packs []SoundPack) error {
+	if len(packs) == 0 {
+		return nil
+	}
+	packIDs := make([]string, 0, len(packs))
+	for _, pack := range packs {
+		packIDs = append(packIDs, pack.PackID)
+	}
+	query, args, err := sqlx.In(`
+		SELECT pack_id, sound_id
+		FROM sound_pack_items
+		WHERE pack_id IN (?)
+		ORDER BY sound_id
+	`, packIDs)
+	if err != nil {
+		return fmt.Errorf("build sound pack items query: %w", err)
+	}
+	var rows []struct {
+		PackID  string `db:"pack_id"`
+		SoundID string `db:"sound_id"`
+	}
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("select sound pack items: %w", err)
+	}
+	items := make(map[string][]string, len(packs))
+	for _, row := range rows {
+		items[row.PackID] = append(items[row.PackID], row.SoundID)
+	}
+	for i := range packs {
+		packs[i].SoundIDs = items[packs[i].PackID]
+		if packs[i].SoundIDs == nil {
+			packs[i].SoundIDs = []string{}
+		}
+	}
+	return nil
+}
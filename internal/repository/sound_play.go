@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SoundPlay は DB上の sound_plays テーブルに対応する構造体です (サウンドが再生された記録)
+type SoundPlay struct {
+	SoundID  string    `db:"sound_id"`
+	RoomID   string    `db:"room_id"`
+	UserID   string    `db:"user_id"`
+	PlayedAt time.Time `db:"played_at"`
+}
+
+// SoundPlayStats はサウンドの再生の統計
+type SoundPlayStats struct {
+	Plays         int          `db:"plays"`
+	UniquePlayers int          `db:"unique_players"`
+	LastPlayedAt  sql.NullTime `db:"last_played_at"`
+	TopRooms      []RoomPlayCount
+	TopPlayers    []UserPlayCount
+}
+
+// RoomPlayCount はルームごとの再生回数
+type RoomPlayCount struct {
+	RoomID string `db:"room_id"`
+	Plays  int    `db:"plays"`
+}
+
+// UserPlayCount はユーザごとの再生回数
+type UserPlayCount struct {
+	UserID string `db:"user_id"`
+	Plays  int    `db:"plays"`
+}
+
+// SoundRanking はランキングの1件 (サウンドと期間内の再生回数)
+type SoundRanking struct {
+	SoundID       string `db:"sound_id"`
+	SoundName     string `db:"sound_name"`
+	StampID       string `db:"stamp_id"`
+	Plays         int    `db:"plays"`
+	UniquePlayers int    `db:"unique_players"`
+}
+
+// InsertSoundPlay はサウンドが再生されたことを記録します
+func (r *Repository) InsertSoundPlay(play SoundPlay) error {
+	_, err := r.db.Exec(`
+		INSERT INTO sound_plays (sound_id, room_id, user_id, played_at)
+		VALUES (?, ?, ?, ?)
+	`, play.SoundID, play.RoomID, play.UserID, play.PlayedAt)
+	if err != nil {
+		return fmt.Errorf("insert sound play: %w", err)
+	}
+	return nil
+}
+
+// GetSoundPlayStats はサウンドの since 以降 (nil の場合は全期間) の再生回数・再生したユーザ数と、
+// 再生回数の多いルーム・ユーザをそれぞれ top 件まで取得します
+func (r *Repository) GetSoundPlayStats(soundID string, since *time.Time, top int) (SoundPlayStats, error) {
+	where := `WHERE sound_id = ?`
+	args := []interface{}{soundID}
+	if since != nil {
+		where += ` AND played_at >= ?`
+		args = append(args, *since)
+	}
+
+	var stats SoundPlayStats
+	if err := r.db.Get(&stats, `
+		SELECT COUNT(*) AS plays, COUNT(DISTINCT user_id) AS unique_players, MAX(played_at) AS last_played_at
+		FROM sound_plays
+		`+where, args...); err != nil {
+		return SoundPlayStats{}, fmt.Errorf("select sound play stats: %w", err)
+	}
+
+	stats.TopRooms = []RoomPlayCount{}
+	if err := r.db.Select(&stats.TopRooms, `
+		SELECT room_id, COUNT(*) AS plays
+		FROM sound_plays
+		`+where+`
+		GROUP BY room_id
+		ORDER BY plays DESC, room_id
+		LIMIT ?
+	`, append(args, top)...); err != nil {
+		return SoundPlayStats{}, fmt.Errorf("select sound play top rooms: %w", err)
+	}
+
+	stats.TopPlayers = []UserPlayCount{}
+	if err := r.db.Select(&stats.TopPlayers, `
+		SELECT user_id, COUNT(*) AS plays
+		FROM sound_plays
+		`+where+`
+		GROUP BY user_id
+		ORDER BY plays DESC, user_id
+		LIMIT ?
+	`, append(args, top)...); err != nil {
+		return SoundPlayStats{}, fmt.Errorf("select sound play top players: %w", err)
+	}
+	return stats, nil
+}
+
+// GetSoundRanking は since 以降 (nil の場合は全期間) に再生回数の多いサウンドを limit 件まで取得します。
+// 通報により非表示になったサウンドは含みません
+func (r *Repository) GetSoundRanking(since *time.Time, limit int) ([]SoundRanking, error) {
+	where := ``
+	args := []interface{}{}
+	if since != nil {
+		where = `WHERE p.played_at >= ?`
+		args = append(args, *since)
+	}
+
+	// 先に sound_plays だけで集計し (インデックスのみで済む)、上位の分だけ sounds と結合する
+	ranking := []SoundRanking{}
+	if err := r.db.Select(&ranking, `
+		SELECT p.sound_id, s.sound_name, COALESCE(s.stamp_id, '') AS stamp_id, p.plays, p.unique_players
+		FROM (
+			SELECT p.sound_id, COUNT(*) AS plays, COUNT(DISTINCT p.user_id) AS unique_players
+			FROM sound_plays p
+			`+where+`
+			GROUP BY p.sound_id
+		) p
+		JOIN sounds s ON s.sound_id = p.sound_id
+		WHERE s.hidden_at IS NULL
+		ORDER BY p.plays DESC, p.unique_players DESC, p.sound_id
+		LIMIT ?
+	`, append(args, limit)...); err != nil {
+		return nil, fmt.Errorf("select sound ranking: %w", err)
+	}
+	return ranking, nil
+}
+
+// GetRoomPlayRanking は since 以降 (nil の場合は全期間) にサウンドの再生回数が多いルームを limit 件まで取得します
+func (r *Repository) GetRoomPlayRanking(since *time.Time, limit int) ([]RoomPlayCount, error) {
+	where := ``
+	args := []interface{}{}
+	if since != nil {
+		where = `WHERE played_at >= ?`
+		args = append(args, *since)
+	}
+
+	rooms := []RoomPlayCount{}
+	if err := r.db.Select(&rooms, `
+		SELECT room_id, COUNT(*) AS plays
+		FROM sound_plays
+		`+where+`
+		GROUP BY room_id
+		ORDER BY plays DESC, room_id
+		LIMIT ?
+	`, append(args, limit)...); err != nil {
+		return nil, fmt.Errorf("select room play ranking: %w", err)
+	}
+	return rooms, nil
+}
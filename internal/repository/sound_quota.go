@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+)
+
+// サウンドの上限の種類。API のエラーコードとしてそのまま返す
+const (
+	SoundQuotaUserSounds = "user_sound_count_exceeded"
+	SoundQuotaUserBytes  = "user_bytes_exceeded"
+	SoundQuotaTotalBytes = "total_bytes_exceeded"
+)
+
+// soundQuotaLockName はサウンドの上限の確認と登録を直列にするための名前付きロック
+const soundQuotaLockName = "soundboard_quota"
+
+// soundQuotaLockTimeoutSeconds は名前付きロックを待つ時間 (秒)
+const soundQuotaLockTimeoutSeconds = 10
+
+// soundStoredBytes は sounds の行が保存しているファイルの合計サイズを表す式。
+// stored_bytes を記録する前に登録されたサウンドは、元のファイルと加工後の WAV (48kHz モノラル 16bit = 96 バイト/ミリ秒) のサイズで見積もる
+const soundStoredBytes = `CASE WHEN stored_bytes > 0 THEN stored_bytes ELSE size_bytes + 44 + duration_ms * 96 END`
+
+// SoundUsage は登録済みのサウンドの数と、保存しているファイルの合計サイズ (バイト)
+type SoundUsage struct {
+	UserSounds int64 `db:"user_sounds"`
+	UserBytes  int64 `db:"user_bytes"`
+	TotalBytes int64 `db:"total_bytes"`
+}
+
+// SoundQuotaError はサウンドを登録すると上限を超える時に返す
+type SoundQuotaError struct {
+	// Code は SoundQuotaUserSounds / SoundQuotaUserBytes / SoundQuotaTotalBytes のいずれか
+	Code string
+	// Limit は上限、Used は登録済みの分、Requested は登録しようとした分 (サウンドの数の場合は 1)
+	Limit     int64
+	Used      int64
+	Requested int64
+}
+
+func (e *SoundQuotaError) Error() string {
+	return fmt.Sprintf("sound quota exceeded (%s): %d + %d > %d", e.Code, e.Used, e.Requested, e.Limit)
+}
+
+// CheckSoundQuota は usage に保存するファイルの合計が sizeBytes のサウンドを1つ加えると quota を超えるかを確認し、超える場合は *SoundQuotaError を返します
+func CheckSoundQuota(quota config.SoundQuota, usage SoundUsage, sizeBytes int64) error {
+	checks := []struct {
+		code      string
+		limit     int64
+		used      int64
+		requested int64
+	}{
+		{SoundQuotaUserSounds, quota.MaxSoundsPerUser, usage.UserSounds, 1},
+		{SoundQuotaUserBytes, quota.MaxBytesPerUser, usage.UserBytes, sizeBytes},
+		{SoundQuotaTotalBytes, quota.MaxTotalBytes, usage.TotalBytes, sizeBytes},
+	}
+	for _, check := range checks {
+		if check.limit > 0 && check.used+check.requested > check.limit {
+			return &SoundQuotaError{Code: check.code, Limit: check.limit, Used: check.used, Requested: check.requested}
+		}
+	}
+	return nil
+}
+
+// GetSoundUsage は creatorID のユーザと全体のサウンドの使用量を取得します
+func (r *Repository) GetSoundUsage(creatorID string) (SoundUsage, error) {
+	usage, err := soundUsage(r.db, creatorID)
+	if err != nil {
+		return SoundUsage{}, fmt.Errorf("select sound usage: %w", err)
+	}
+	return usage, nil
+}
+
+func soundUsage(q sqlx.Queryer, creatorID string) (SoundUsage, error) {
+	var usage SoundUsage
+	err := sqlx.Get(q, &usage, `
+		SELECT
+			COALESCE(SUM(creator_id = ?), 0) AS user_sounds,
+			COALESCE(SUM(CASE WHEN creator_id = ? THEN `+soundStoredBytes+` ELSE 0 END), 0) AS user_bytes,
+			COALESCE(SUM(`+soundStoredBytes+`), 0) AS total_bytes
+		FROM sounds
+	`, creatorID, creatorID)
+	return usage, err
+}
+
+// withSoundQuotaLock は名前付きロックを取った接続でトランザクションを開始して fn を実行し、コミットしてからロックを解放する。
+// 上限の確認から登録・コミットまでの間に、他のアップロードが使用量を読まないようにするために使う。
+func (r *Repository) withSoundQuotaLock(fn func(tx *sqlx.Tx) error) error {
+	ctx := context.Background()
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.GetContext(ctx, &locked, `SELECT GET_LOCK(?, ?)`, soundQuotaLockName, soundQuotaLockTimeoutSeconds); err != nil {
+		return fmt.Errorf("get sound quota lock: %w", err)
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return errors.New("timed out waiting for sound quota lock")
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, `SELECT RELEASE_LOCK(?)`, soundQuotaLockName); err != nil {
+			fmt.Printf("Failed to release sound quota lock: %v\n", err)
+		}
+	}()
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertSoundWithinQuota は sound の作成者と全体の使用量に sound の保存するファイル (StoredBytes) を加えても
+// quota を超えないことを確認してから登録する
+func insertSoundWithinQuota(tx *sqlx.Tx, sound Sound, quota config.SoundQuota) error {
+	usage, err := soundUsage(tx, sound.CreatorID)
+	if err != nil {
+		return fmt.Errorf("select sound usage: %w", err)
+	}
+	if err := CheckSoundQuota(quota, usage, sound.StoredBytes); err != nil {
+		return err
+	}
+	return insertSound(tx, sound)
+}
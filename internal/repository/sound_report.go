@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SoundReport は DB上の sound_reports テーブルに対応する構造体です
+type SoundReport struct {
+	SoundID    string    `db:"sound_id"`
+	ReporterID string    `db:"reporter_id"`
+	Reason     string    `db:"reason"`
+	CreatedAt  time.Time `db:"created_at"`
+	// ResolvedAt は管理者が問題なしと判断した日時。未対応の通報は NULL
+	ResolvedAt sql.NullTime `db:"resolved_at"`
+}
+
+// SoundReportResult は通報を記録した結果
+type SoundReportResult struct {
+	// OpenReports はサウンドの未対応の通報の数
+	OpenReports int64
+	// Hidden はサウンドが非表示かどうか、NewlyHidden はこの通報で非表示になったかどうか
+	Hidden      bool
+	NewlyHidden bool
+	// FirstOpenReport はこの通報でサウンドの未対応の通報が 0 件から 1 件になったかどうか
+	// (未対応の通報を同じユーザが出し直した場合は false)
+	FirstOpenReport bool
+}
+
+// ReportedSound は未対応の通報があるサウンド
+type ReportedSound struct {
+	Sound
+	OpenReports    int64     `db:"open_reports"`
+	LastReportedAt time.Time `db:"last_reported_at"`
+}
+
+// ReportSound は reporterID による sound_id の通報を記録し、未対応の通報が threshold 件以上になったらサウンドを非表示にします。
+// 同じユーザが既に通報している場合は理由を更新し、問題なしとされた通報であれば未対応に戻します
+func (r *Repository) ReportSound(soundID, reporterID, reason string, threshold int64) (SoundReportResult, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return SoundReportResult{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 同じユーザの未対応の通報が既にあれば、理由を更新するだけで新しい通報としては数えない
+	var alreadyOpen bool
+	err = tx.Get(&alreadyOpen, `
+		SELECT resolved_at IS NULL
+		FROM sound_reports
+		WHERE sound_id = ? AND reporter_id = ?
+		FOR UPDATE
+	`, soundID, reporterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return SoundReportResult{}, fmt.Errorf("select sound report: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO sound_reports (sound_id, reporter_id, reason)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE reason = VALUES(reason), created_at = CURRENT_TIMESTAMP, resolved_at = NULL
+	`, soundID, reporterID, reason); err != nil {
+		return SoundReportResult{}, fmt.Errorf("insert sound report: %w", err)
+	}
+
+	var result SoundReportResult
+	if err := tx.Get(&result.OpenReports, `
+		SELECT COUNT(*)
+		FROM sound_reports
+		WHERE sound_id = ? AND resolved_at IS NULL
+	`, soundID); err != nil {
+		return SoundReportResult{}, fmt.Errorf("count sound reports: %w", err)
+	}
+	result.FirstOpenReport = !alreadyOpen && result.OpenReports == 1
+	if threshold > 0 && result.OpenReports >= threshold {
+		res, err := tx.Exec(`
+			UPDATE sounds
+			SET hidden_at = CURRENT_TIMESTAMP
+			WHERE sound_id = ? AND hidden_at IS NULL
+		`, soundID)
+		if err != nil {
+			return SoundReportResult{}, fmt.Errorf("hide sound: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return SoundReportResult{}, fmt.Errorf("hide sound: %w", err)
+		}
+		result.NewlyHidden = n > 0
+	}
+	if err := tx.Get(&result.Hidden, `SELECT hidden_at IS NOT NULL FROM sounds WHERE sound_id = ?`, soundID); err != nil {
+		return SoundReportResult{}, fmt.Errorf("select sound hidden_at: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SoundReportResult{}, fmt.Errorf("commit sound report: %w", err)
+	}
+	return result, nil
+}
+
+// GetReportedSounds は未対応の通報があるサウンドを、非表示のもの・通報の多いもの・最近通報されたものの順に取得します
+func (r *Repository) GetReportedSounds() ([]ReportedSound, error) {
+	sounds := []ReportedSound{}
+	if err := r.db.Select(&sounds, `
+		SELECT `+soundColumns+`, rep.open_reports, rep.last_reported_at
+		FROM (
+			SELECT sound_id, COUNT(*) AS open_reports, MAX(created_at) AS last_reported_at
+			FROM sound_reports
+			WHERE resolved_at IS NULL
+			GROUP BY sound_id
+		) rep
+		JOIN sounds s ON s.sound_id = rep.sound_id
+		ORDER BY s.hidden_at IS NULL, rep.open_reports DESC, rep.last_reported_at DESC, s.sound_id
+	`); err != nil {
+		return nil, fmt.Errorf("select reported sounds: %w", err)
+	}
+	return sounds, nil
+}
+
+// GetOpenSoundReports は指定したサウンドの未対応の通報を sound_id ごとに新しい順に取得します
+func (r *Repository) GetOpenSoundReports(soundIDs []string) (map[string][]SoundReport, error) {
+	reports := make(map[string][]SoundReport, len(soundIDs))
+	if len(soundIDs) == 0 {
+		return reports, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT sound_id, reporter_id, reason, created_at, resolved_at
+		FROM sound_reports
+		WHERE resolved_at IS NULL AND sound_id IN (?)
+		ORDER BY created_at DESC, reporter_id
+	`, soundIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build sound reports query: %w", err)
+	}
+	var rows []SoundReport
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("select sound reports: %w", err)
+	}
+	for _, row := range rows {
+		reports[row.SoundID] = append(reports[row.SoundID], row)
+	}
+	return reports, nil
+}
+
+// ApproveSound は sound_id の未対応の通報を全て問題なしとし、サウンドを再び表示します
+func (r *Repository) ApproveSound(soundID string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		UPDATE sound_reports
+		SET resolved_at = CURRENT_TIMESTAMP
+		WHERE sound_id = ? AND resolved_at IS NULL
+	`, soundID); err != nil {
+		return fmt.Errorf("resolve sound reports: %w", err)
+	}
+	if _, err := tx.Exec(`
+		UPDATE sounds
+		SET hidden_at = NULL
+		WHERE sound_id = ?
+	`, soundID); err != nil {
+		return fmt.Errorf("unhide sound: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit approve sound: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReportSound(t *testing.T) {
+	r := newTestRepository(t)
+	sound := insertTestSound(t, r, "creator")
+
+	// 閾値 2: 1件目は最初の通報、2件目で非表示、3件目は既に非表示
+	steps := []struct {
+		reporter string
+		want     SoundReportResult
+	}{
+		{"alice", SoundReportResult{OpenReports: 1, FirstOpenReport: true}},
+		// 同じユーザの出し直しは数えない
+		{"alice", SoundReportResult{OpenReports: 1}},
+		{"bob", SoundReportResult{OpenReports: 2, Hidden: true, NewlyHidden: true}},
+		{"carol", SoundReportResult{OpenReports: 3, Hidden: true}},
+	}
+	for i, step := range steps {
+		got, err := r.ReportSound(sound.SoundID, step.reporter, "too loud", 2)
+		if err != nil {
+			t.Fatalf("step %d: ReportSound: %v", i, err)
+		}
+		if got != step.want {
+			t.Errorf("step %d (%s): ReportSound = %+v, want %+v", i, step.reporter, got, step.want)
+		}
+	}
+
+	hidden, err := r.GetSoundboardByID(sound.SoundID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hidden.HiddenAt.Valid {
+		t.Error("sound is not hidden")
+	}
+	listed, err := r.ListSounds(SoundFilter{CreatorID: "creator", Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.ContainsFunc(listed, func(s Sound) bool { return s.SoundID == sound.SoundID }) {
+		t.Error("hidden sound is listed")
+	}
+
+	reported, err := r.GetReportedSounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(reported, func(s ReportedSound) bool { return s.SoundID == sound.SoundID })
+	if i < 0 {
+		t.Fatal("hidden sound is not in the review queue")
+	}
+	if reported[i].OpenReports != 3 {
+		t.Errorf("OpenReports = %d, want 3", reported[i].OpenReports)
+	}
+	reports, err := r.GetOpenSoundReports([]string{sound.SoundID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports[sound.SoundID]) != 3 {
+		t.Errorf("got %d open reports, want 3", len(reports[sound.SoundID]))
+	}
+
+	// 承認すると通報は対応済みになり、再び表示される
+	if err := r.ApproveSound(sound.SoundID); err != nil {
+		t.Fatal(err)
+	}
+	approved, err := r.GetSoundboardByID(sound.SoundID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.HiddenAt.Valid {
+		t.Error("approved sound is still hidden")
+	}
+	reported, err = r.GetReportedSounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.ContainsFunc(reported, func(s ReportedSound) bool { return s.SoundID == sound.SoundID }) {
+		t.Error("approved sound is still in the review queue")
+	}
+	listed, err = r.ListSounds(SoundFilter{CreatorID: "creator", Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(listed, func(s Sound) bool { return s.SoundID == sound.SoundID }) {
+		t.Error("approved sound is not listed")
+	}
+
+	// 対応済みの通報を出し直すと未対応に戻り、最初の通報として数える
+	got, err := r.ReportSound(sound.SoundID, "alice", "still too loud", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (SoundReportResult{OpenReports: 1, FirstOpenReport: true}); got != want {
+		t.Errorf("re-report after approval = %+v, want %+v", got, want)
+	}
+	got, err = r.ReportSound(sound.SoundID, "bob", "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (SoundReportResult{OpenReports: 2, Hidden: true, NewlyHidden: true}); got != want {
+		t.Errorf("second report after approval = %+v, want %+v", got, want)
+	}
+}
+
+func TestReportSoundWithoutThreshold(t *testing.T) {
+	r := newTestRepository(t)
+	sound := insertTestSound(t, r, "creator")
+
+	for i, reporter := range []string{"alice", "bob", "carol"} {
+		got, err := r.ReportSound(sound.SoundID, reporter, "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (SoundReportResult{OpenReports: int64(i + 1), FirstOpenReport: i == 0}); got != want {
+			t.Errorf("report %d = %+v, want %+v (threshold 0 never hides)", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
+)
+
+// アップロードの状態
+const (
+	SoundUploadPending    = "pending"
+	SoundUploadProcessing = "processing"
+	SoundUploadCompleted  = "completed"
+	SoundUploadFailed     = "failed"
+)
+
+// SoundUpload は DB上の sound_uploads テーブルに対応する構造体です (署名付き URL で直接アップロード中のサウンド)
+type SoundUpload struct {
+	// UploadID は完了後にそのまま soundId になる
+	UploadID  string `db:"upload_id"`
+	CreatorID string `db:"creator_id"`
+	SoundName string `db:"sound_name"`
+	StampID   string `db:"stamp_id"`
+	// ObjectKey はクライアントがアップロードする加工前のファイルのキー
+	ObjectKey   string    `db:"object_key"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	Status      string    `db:"status"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+// InsertSoundUpload はアップロードを pending 状態で登録します
+func (r *Repository) InsertSoundUpload(upload SoundUpload) error {
+	_, err := r.db.Exec(`
+		INSERT INTO sound_uploads (upload_id, creator_id, sound_name, stamp_id, object_key, content_type, size, status, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, upload.UploadID, upload.CreatorID, upload.SoundName, upload.StampID, upload.ObjectKey,
+		upload.ContentType, upload.Size, SoundUploadPending, upload.CreatedAt, upload.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("insert sound upload: %w", err)
+	}
+	return nil
+}
+
+// GetSoundUpload は指定された upload_id のアップロードを取得します。存在しない場合は sql.ErrNoRows をラップして返します
+func (r *Repository) GetSoundUpload(uploadID string) (SoundUpload, error) {
+	var upload SoundUpload
+	if err := r.db.Get(&upload, `
+		SELECT upload_id, creator_id, sound_name, stamp_id, object_key, content_type, size, status, created_at, expires_at
+		FROM sound_uploads
+		WHERE upload_id = ?
+	`, uploadID); err != nil {
+		return SoundUpload{}, fmt.Errorf("select sound upload: %w", err)
+	}
+	return upload, nil
+}
+
+// ClaimSoundUpload は pending 状態のアップロードを processing にします。
+// 既に他のリクエストが処理している等で pending でなかった場合は false を返します
+func (r *Repository) ClaimSoundUpload(uploadID string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE sound_uploads
+		SET status = ?
+		WHERE upload_id = ? AND status = ?
+	`, SoundUploadProcessing, uploadID, SoundUploadPending)
+	if err != nil {
+		return false, fmt.Errorf("claim sound upload: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim sound upload: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetSoundUploadStatus はアップロードの状態を更新します
+func (r *Repository) SetSoundUploadStatus(uploadID, status string) error {
+	_, err := r.db.Exec(`
+		UPDATE sound_uploads
+		SET status = ?
+		WHERE upload_id = ?
+	`, status, uploadID)
+	if err != nil {
+		return fmt.Errorf("set sound upload status: %w", err)
+	}
+	return nil
+}
+
+// CompleteSoundUpload はアップロードを完了状態にし、加工後のサウンド sound (sound_id は upload_id) を sounds テーブルへ登録します。
+// 同じ内容のサウンドが既に登録されている場合は ErrDuplicateSound を、登録すると quota を超える場合は *SoundQuotaError を返します
+func (r *Repository) CompleteSoundUpload(sound Sound, quota config.SoundQuota) error {
+	return r.withSoundQuotaLock(func(tx *sqlx.Tx) error {
+		if err := insertSoundWithinQuota(tx, sound, quota); err != nil {
+			return fmt.Errorf("insert soundboard item: %w", err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE sound_uploads
+			SET status = ?
+			WHERE upload_id = ?
+		`, SoundUploadCompleted, sound.SoundID); err != nil {
+			return fmt.Errorf("complete sound upload: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetExpiredSoundUploads は before より前に期限が切れたアップロードを取得します
+func (r *Repository) GetExpiredSoundUploads(before time.Time) ([]SoundUpload, error) {
+	var uploads []SoundUpload
+	if err := r.db.Select(&uploads, `
+		SELECT upload_id, creator_id, sound_name, stamp_id, object_key, content_type, size, status, created_at, expires_at
+		FROM sound_uploads
+		WHERE expires_at < ?
+	`, before); err != nil {
+		return nil, fmt.Errorf("select expired sound uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// DeleteSoundUpload は指定された upload_id のレコードを削除します
+func (r *Repository) DeleteSoundUpload(uploadID string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM sound_uploads
+		WHERE upload_id = ?
+	`, uploadID)
+	if err != nil {
+		return fmt.Errorf("delete sound upload: %w", err)
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/pikachu0310/livekit-server/internal/pkg/bot"
+	"github.com/pikachu0310/livekit-server/internal/pkg/config"
 	"github.com/traPtitech/go-traq"
 )
 
@@ -40,6 +43,39 @@ func (r *Repository) SendStartScreenShareMessageToTraQ(channelId string, userNam
 	bot.SendMessageToNotificationChannel(content)
 }
 
+func (r *Repository) SendStopScreenShareMessageToTraQ(channelId string, userName string) {
+	path := r.GetChannelFullPath(channelId)
+	content := fmt.Sprintf(":@%s: %s さんが #%s で画面共有を終了しました", userName, userName, path)
+	bot.SendMessageToNotificationChannel(content)
+}
+
+// SendSoundReportedMessageToTraQ は未対応の通報が無かったサウンドが通報されたことを、モデレーション用のチャンネルに送る
+func (r *Repository) SendSoundReportedMessageToTraQ(sound Sound, reason string, threshold int64) {
+	content := fmt.Sprintf("サウンド「%s」(%s) が通報されました。", sound.SoundName, sound.SoundID)
+	if reason != "" {
+		content += fmt.Sprintf("\n理由: %s", reason)
+	}
+	if threshold > 0 {
+		content += fmt.Sprintf("\n%d 件の通報で自動的に非表示になります。確認待ちの一覧 (GET /admin/soundboard/reports) から確認できます", threshold)
+	}
+	if _, err := bot.SendMessage(config.SoundboardModerationChannelID(), content, true); err != nil {
+		fmt.Println("Failed to send message to moderation channel: " + err.Error())
+	}
+}
+
+// SendSoundHiddenMessageToTraQ は通報によりサウンドが非表示になったことを、管理者へのメンション付きでモデレーション用のチャンネルに送る
+func (r *Repository) SendSoundHiddenMessageToTraQ(sound Sound, reports int64) {
+	mentions := make([]string, 0, len(config.SoundboardAdmins()))
+	for _, admin := range config.SoundboardAdmins() {
+		mentions = append(mentions, "@"+admin)
+	}
+	content := fmt.Sprintf("%s\nサウンド「%s」(%s) が %d 件の通報により非表示になりました。確認待ちの一覧 (GET /admin/soundboard/reports) から承認か削除をしてください",
+		strings.Join(mentions, " "), sound.SoundName, sound.SoundID, reports)
+	if _, err := bot.SendMessage(config.SoundboardModerationChannelID(), strings.TrimSpace(content), true); err != nil {
+		fmt.Println("Failed to send message to moderation channel: " + err.Error())
+	}
+}
+
 func (r *Repository) GetTraQChannelsAndSet() error {
 	channels, err := bot.GetChannels()
 	if err != nil {
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// WebhookEvent は DB上の webhook_events テーブルに対応する構造体です
+type WebhookEvent struct {
+	EventID    string    `db:"event_id"`
+	Event      string    `db:"event"`
+	RoomName   string    `db:"room_name"`
+	CreatedAt  time.Time `db:"created_at"`
+	ReceivedAt time.Time `db:"received_at"`
+	Payload    string    `db:"payload"`
+}
+
+// SaveWebhookEvent は受け取ったWebhookイベントを記録します。
+// 同じイベントIDが既に記録されている場合 (LiveKitによる再送) は false を返します
+func (r *Repository) SaveWebhookEvent(event *livekit.WebhookEvent, receivedAt time.Time) (bool, error) {
+	payload, err := protojson.Marshal(event)
+	if err != nil {
+		return false, fmt.Errorf("marshal webhook event: %w", err)
+	}
+	result, err := r.db.Exec(`
+		INSERT IGNORE INTO webhook_events (event_id, event, room_name, created_at, received_at, payload)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.Id, event.Event, WebhookRoomName(event), time.Unix(event.CreatedAt, 0), receivedAt, string(payload))
+	if err != nil {
+		return false, fmt.Errorf("insert webhook event: %w", err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return inserted > 0, nil
+}
+
+// GetWebhookEvents は [from, to) に発生したWebhookイベントを発生順に取得します。
+// roomName が空でなければそのルームのイベントだけを返します
+func (r *Repository) GetWebhookEvents(from, to time.Time, roomName string) ([]*livekit.WebhookEvent, error) {
+	query := `
+		SELECT event_id, event, room_name, created_at, received_at, payload
+		FROM webhook_events
+		WHERE created_at >= ? AND created_at < ?`
+	args := []interface{}{from, to}
+	if roomName != "" {
+		query += ` AND room_name = ?`
+		args = append(args, roomName)
+	}
+	query += ` ORDER BY created_at, received_at`
+
+	var rows []WebhookEvent
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select webhook events: %w", err)
+	}
+
+	events := make([]*livekit.WebhookEvent, 0, len(rows))
+	for _, row := range rows {
+		event := &livekit.WebhookEvent{}
+		if err := protojson.Unmarshal([]byte(row.Payload), event); err != nil {
+			return nil, fmt.Errorf("unmarshal webhook event %s: %w", row.EventID, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// WebhookRoomName はイベントの対象ルーム名を返す (egress / ingress は Room を持たない)
+func WebhookRoomName(event *livekit.WebhookEvent) string {
+	switch {
+	case event.GetEgressInfo() != nil:
+		return event.GetEgressInfo().GetRoomName()
+	case event.GetIngressInfo() != nil:
+		return event.GetIngressInfo().GetRoomName()
+	default:
+		return event.GetRoom().GetName()
+	}
+}
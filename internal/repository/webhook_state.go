@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+	"github.com/pikachu0310/livekit-server/openapi/models"
+)
+
+// webhookOrderRetention はイベントの順序判定のために発生時刻を覚えておく期間。
+// LiveKit の再送はこれより十分短い間に行われる。
+const webhookOrderRetention = time.Hour
+
+// WebhookResult は Webhook イベントをルーム状態に反映した結果
+type WebhookResult struct {
+	// Applied が false の場合は、より新しいイベントが反映済みか、ルーム状態に関係しない種類のイベント (Unhandled) なので何もしていない
+	Applied bool
+	// Unhandled はルーム状態に反映しない種類のイベントの場合に true
+	Unhandled bool
+	// RoomID はイベントの対象ルーム (ルーム名が UUID でない場合は uuid.Nil)
+	RoomID uuid.UUID
+	// NewRoom はこのイベントでルームが作成された場合に true
+	NewRoom bool
+	// Changed は対象の参加者・トラック等がルーム状態に見つかり、更新された場合に true
+	Changed bool
+	Track   models.Track
+	Egress  models.Egress
+	Ingress models.Ingress
+}
+
+// ApplyWebhookEvent は Webhook イベントをルーム状態に反映する。
+// イベントは順不同で届くことがあるので、対象ごとに反映済みのイベントより古いものは無視する。
+// traQ への通知や履歴の記録は行わないので、記録済みイベントの再生にも使える。
+func (r *Repository) ApplyWebhookEvent(event *livekit.WebhookEvent) WebhookResult {
+	roomName := WebhookRoomName(event)
+	roomID, _ := uuid.Parse(roomName)
+	result := WebhookResult{RoomID: roomID}
+
+	if !hasWebhookTarget(event) {
+		return result
+	}
+
+	roomKey := "room/" + roomName
+	identity := event.GetParticipant().GetIdentity()
+	participantKey := roomKey + "/participant/" + identity
+	switch event.Event {
+	case webhook.EventRoomStarted:
+		if !r.webhookOrder.accept(event.CreatedAt, false, roomKey) {
+			return result
+		}
+		result.NewRoom = r.AddRoomToRoomState(event.GetRoom())
+		result.Changed = result.NewRoom
+	case webhook.EventRoomFinished:
+		if !r.webhookOrder.accept(event.CreatedAt, true, roomKey) {
+			return result
+		}
+		result.Changed = r.RoomState.RemoveRoom(roomID)
+	case webhook.EventParticipantJoined:
+		if !r.webhookOrder.accept(event.CreatedAt, false, participantKey, roomKey) {
+			return result
+		}
+		result.NewRoom = !r.RoomState.Exists(roomID)
+		r.AddParticipantToRoomState(event.GetRoom(), event.GetParticipant())
+		result.Changed = true
+	case webhook.EventParticipantLeft:
+		if !r.webhookOrder.accept(event.CreatedAt, true, participantKey) {
+			return result
+		}
+		result.Changed = r.RoomState.RemoveParticipant(roomID, identity)
+	case webhook.EventTrackPublished:
+		trackKey := roomKey + "/track/" + event.GetTrack().GetSid()
+		if !r.webhookOrder.accept(event.CreatedAt, false, trackKey, participantKey, roomKey) {
+			return result
+		}
+		result.Track, result.Changed = r.AddTrackToRoomState(roomName, identity, event.GetTrack())
+	case webhook.EventTrackUnpublished:
+		trackKey := roomKey + "/track/" + event.GetTrack().GetSid()
+		if !r.webhookOrder.accept(event.CreatedAt, true, trackKey) {
+			return result
+		}
+		result.Track, result.Changed = r.RemoveTrackFromRoomState(roomName, identity, event.GetTrack())
+	case webhook.EventEgressStarted, webhook.EventEgressUpdated:
+		if !r.webhookOrder.accept(event.CreatedAt, false, "egress/"+event.GetEgressInfo().GetEgressId(), roomKey) {
+			return result
+		}
+		result.Egress, result.Changed = r.UpdateEgressInRoomState(event.GetEgressInfo())
+	case webhook.EventEgressEnded:
+		if !r.webhookOrder.accept(event.CreatedAt, true, "egress/"+event.GetEgressInfo().GetEgressId()) {
+			return result
+		}
+		result.Egress, result.Changed = r.RemoveEgressFromRoomState(event.GetEgressInfo())
+	case webhook.EventIngressStarted:
+		if !r.webhookOrder.accept(event.CreatedAt, false, "ingress/"+event.GetIngressInfo().GetIngressId(), roomKey) {
+			return result
+		}
+		result.Ingress, result.Changed = r.UpdateIngressInRoomState(event.GetIngressInfo())
+	case webhook.EventIngressEnded:
+		if !r.webhookOrder.accept(event.CreatedAt, true, "ingress/"+event.GetIngressInfo().GetIngressId()) {
+			return result
+		}
+		result.Ingress, result.Changed = r.RemoveIngressFromRoomState(event.GetIngressInfo())
+	default:
+		result.Unhandled = true
+		return result
+	}
+	result.Applied = true
+	return result
+}
+
+// hasWebhookTarget はイベントが種類ごとの対象 (ルーム・参加者・トラック・Egress・Ingress) を含んでいるかを返す。
+// 不正なイベントや記録から再生したイベントで panic しないよう、含んでいないイベントは反映しない
+func hasWebhookTarget(event *livekit.WebhookEvent) bool {
+	switch event.GetEvent() {
+	case webhook.EventRoomStarted, webhook.EventRoomFinished:
+		return event.GetRoom() != nil
+	case webhook.EventParticipantJoined, webhook.EventParticipantLeft:
+		return event.GetRoom() != nil && event.GetParticipant().GetIdentity() != ""
+	case webhook.EventTrackPublished, webhook.EventTrackUnpublished:
+		return event.GetRoom() != nil && event.GetParticipant().GetIdentity() != "" && event.GetTrack() != nil
+	case webhook.EventEgressStarted, webhook.EventEgressUpdated, webhook.EventEgressEnded:
+		return event.GetEgressInfo().GetEgressId() != ""
+	case webhook.EventIngressStarted, webhook.EventIngressEnded:
+		return event.GetIngressInfo().GetIngressId() != ""
+	default:
+		return true
+	}
+}
+
+// webhookOrder は対象 (ルーム・参加者・トラック等) ごとに、最後に反映したイベントの発生時刻を覚えておく
+type webhookOrder struct {
+	mu    sync.Mutex
+	marks map[string]webhookMark
+	// latest は反映したイベントの最新の発生時刻。記録済みイベントの再生でも使えるよう、古い発生時刻の判定は現在時刻ではなくこれを基準にする。
+	latest    int64
+	lastPrune int64
+}
+
+type webhookMark struct {
+	createdAt int64
+	// ended は最後に反映したイベントが終了 (退出・非公開等) を表すかどうか
+	ended bool
+}
+
+func newWebhookOrder() *webhookOrder {
+	return &webhookOrder{
+		marks: make(map[string]webhookMark),
+	}
+}
+
+// accept はイベントを反映してよいかを判定し、よければ keys[0] の発生時刻を更新する。
+// keys[0] はイベントの対象、keys[1:] はそれを含む親 (ルーム等)。
+// 対象により新しいイベントが反映済みの場合と、開始系のイベントより後に親が終了している場合は false を返す。
+// CreatedAt は秒単位なので、同じ時刻では終了系のイベントを優先する。
+func (o *webhookOrder) accept(createdAt int64, ended bool, keys ...string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if mark, ok := o.marks[keys[0]]; ok && isOlder(createdAt, ended, mark) {
+		return false
+	}
+	if !ended {
+		for _, key := range keys[1:] {
+			if mark, ok := o.marks[key]; ok && mark.ended && isOlder(createdAt, ended, mark) {
+				return false
+			}
+		}
+	}
+	o.marks[keys[0]] = webhookMark{createdAt: createdAt, ended: ended}
+	if createdAt > o.latest {
+		o.latest = createdAt
+		o.pruneLocked()
+	}
+	return true
+}
+
+func isOlder(createdAt int64, ended bool, mark webhookMark) bool {
+	if createdAt != mark.createdAt {
+		return createdAt < mark.createdAt
+	}
+	return mark.ended && !ended
+}
+
+// pruneLocked は古くなった発生時刻を捨てる。o.mu を取った状態で呼ぶこと。
+func (o *webhookOrder) pruneLocked() {
+	if o.latest-o.lastPrune < int64(time.Minute/time.Second) {
+		return
+	}
+	o.lastPrune = o.latest
+
+	threshold := o.latest - int64(webhookOrderRetention/time.Second)
+	for key, mark := range o.marks {
+		if mark.createdAt < threshold {
+			delete(o.marks, key)
+		}
+	}
+}
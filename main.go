@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
@@ -10,9 +11,11 @@ import (
 	"github.com/pikachu0310/livekit-server/internal/pkg/bot"
 	"github.com/pikachu0310/livekit-server/internal/pkg/config"
 	mw "github.com/pikachu0310/livekit-server/internal/pkg/middleware"
+	"github.com/pikachu0310/livekit-server/internal/pkg/util"
 	"github.com/pikachu0310/livekit-server/internal/repository"
 	"github.com/pikachu0310/livekit-server/openapi"
 	"net/http"
+	"time"
 )
 
 func main() {
@@ -28,10 +31,12 @@ func main() {
 
 	// middlewares
 	e.Use(middleware.Recover())
-	e.Use(middleware.Logger())
+	e.Use(mw.Logger)
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"http://localhost:8080", "https://*.traq-preview.trapti.tech", "https://*.livekit.trap.show", "https://*.trap.jp"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowOrigins: config.AllowedOrigins(),
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
+		// ブラウザの JavaScript から読めるようにするレスポンスヘッダ (サウンド一覧のページング・再生回数の制限)
+		ExposeHeaders: []string{"X-Next-Cursor", "Retry-After"},
 	}))
 	//e.Use(oapimiddleware.OapiRequestValidator(swagger))
 	e.Use(mw.AuthTraQMiddlewareWithPathSkipper)
@@ -48,6 +53,11 @@ func main() {
 		e.Logger.Fatal(err)
 	}
 
+	// setup traQ token verification
+	if err := util.SetTraQAuth(config.LoadAuthConfig()); err != nil {
+		e.Logger.Fatal(err)
+	}
+
 	// set and start traQ bot
 	bot.SetAndStartTraQBot()
 
@@ -59,10 +69,24 @@ func main() {
 	}
 
 	// setup routes
-	cfg := config.NewS3Config()
-	fileSvc := repository.NewFileService(cfg)
+	fileSvc, err := repository.NewFileService(config.NewStorageConfig())
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
 	h := handler.New(repo, fileSvc)
 	openapi.RegisterHandlersWithBaseURL(e, h, baseURL)
 
+	// 前回の起動で残ったサウンドボードの Ingress を削除
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := h.CleanupSoundboardPlayback(cleanupCtx); err != nil {
+		e.Logger.Warn(err)
+	}
+	cancel()
+
+	// LiveKitとのルーム状態の突き合わせを開始
+	go h.RunRoomStateReconciler(context.Background(), config.RoomStateReconcileInterval())
+	// 完了しなかったサウンドのアップロードの掃除を開始
+	go h.RunSoundUploadSweeper(context.Background(), config.SoundUploadSweepInterval())
+
 	e.Logger.Fatal(e.Start(config.AppAddr()))
 }
@@ -9,6 +9,121 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// Defines values for SoundboardDuplicatePolicy.
+const (
+	Conflict SoundboardDuplicatePolicy = "conflict"
+	Existing SoundboardDuplicatePolicy = "existing"
+)
+
+// Defines values for SoundboardPlaybackStatus.
+const (
+	Playing SoundboardPlaybackStatus = "playing"
+	Queued  SoundboardPlaybackStatus = "queued"
+)
+
+// Defines values for SoundboardQueuePolicy.
+const (
+	Overlap SoundboardQueuePolicy = "overlap"
+	Queue   SoundboardQueuePolicy = "queue"
+	Replace SoundboardQueuePolicy = "replace"
+)
+
+// Defines values for SoundboardQuotaErrorCode.
+const (
+	TotalBytesExceeded     SoundboardQuotaErrorCode = "total_bytes_exceeded"
+	UserBytesExceeded      SoundboardQuotaErrorCode = "user_bytes_exceeded"
+	UserSoundCountExceeded SoundboardQuotaErrorCode = "user_sound_count_exceeded"
+)
+
+// Defines values for SoundboardStatsPeriod.
+const (
+	All   SoundboardStatsPeriod = "all"
+	Day   SoundboardStatsPeriod = "day"
+	Month SoundboardStatsPeriod = "month"
+	Week  SoundboardStatsPeriod = "week"
+)
+
+// Defines values for TrackSource.
+const (
+	Camera           TrackSource = "camera"
+	Microphone       TrackSource = "microphone"
+	ScreenShare      TrackSource = "screen_share"
+	ScreenShareAudio TrackSource = "screen_share_audio"
+	Unknown          TrackSource = "unknown"
+)
+
+// Defines values for WsClientMessageType.
+const (
+	Resync      WsClientMessageType = "resync"
+	Subscribe   WsClientMessageType = "subscribe"
+	Unsubscribe WsClientMessageType = "unsubscribe"
+)
+
+// Defines values for WsEventType.
+const (
+	EgressEnded       WsEventType = "egress_ended"
+	EgressUpdated     WsEventType = "egress_updated"
+	IngressEnded      WsEventType = "ingress_ended"
+	IngressUpdated    WsEventType = "ingress_updated"
+	MetadataChanged   WsEventType = "metadata_changed"
+	ParticipantJoined WsEventType = "participant_joined"
+	ParticipantLeft   WsEventType = "participant_left"
+	PermissionChanged WsEventType = "permission_changed"
+	RoomFinished      WsEventType = "room_finished"
+	RoomStarted       WsEventType = "room_started"
+	Snapshot          WsEventType = "snapshot"
+	TrackPublished    WsEventType = "track_published"
+	TrackUnpublished  WsEventType = "track_unpublished"
+)
+
+// CallSession 1回の Qall (ルームが開始してから終了するまで)
+type CallSession struct {
+	// EndedAt 終了時刻 (通話中の場合は無し)
+	EndedAt *time.Time `json:"endedAt,omitempty"`
+
+	// IsWebinar ウェビナールームかどうか
+	IsWebinar    bool               `json:"isWebinar"`
+	Participants []ParticipantStint `json:"participants"`
+
+	// PeakParticipants 最大同時参加者数
+	PeakParticipants int `json:"peakParticipants"`
+
+	// RoomId ルーム(チャンネル)のID
+	RoomId openapi_types.UUID `json:"roomId"`
+
+	// SessionId セッションのID
+	SessionId openapi_types.UUID `json:"sessionId"`
+
+	// StartedAt 開始時刻
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// ChannelSoundboardPacksRequest defines model for ChannelSoundboardPacksRequest.
+type ChannelSoundboardPacksRequest struct {
+	PackIds []string `json:"packIds"`
+}
+
+// Egress ルームの録画・配信
+type Egress struct {
+	EgressId  string     `json:"egressId"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// Status LiveKit の EgressStatus (例 EGRESS_ACTIVE)
+	Status string `json:"status"`
+}
+
+// Ingress ルームへの外部からの入力 (RTMP / WHIP 等)
+type Ingress struct {
+	IngressId string  `json:"ingressId"`
+	Name      *string `json:"name,omitempty"`
+
+	// ParticipantIdentity ingress が参加者として現れる時の identity
+	ParticipantIdentity *string `json:"participantIdentity,omitempty"`
+
+	// Status LiveKit の IngressState.Status (例 ENDPOINT_PUBLISHING)
+	Status string `json:"status"`
+}
+
 // Participant ルーム内の参加者一覧
 type Participant struct {
 	// Attributes ユーザーに関連付けられたカスタム属性
@@ -25,10 +140,47 @@ type Participant struct {
 
 	// Name 表示名
 	Name *string `json:"name,omitempty"`
+
+	// Tracks 参加者が公開中のトラック
+	Tracks *[]Track `json:"tracks,omitempty"`
+}
+
+// ParticipantStint 参加者が1回入室してから退出するまでの記録
+type ParticipantStint struct {
+	// Identity ユーザーID_RandomUUID
+	Identity string `json:"identity"`
+
+	// JoinedAt 入室時刻
+	JoinedAt time.Time `json:"joinedAt"`
+
+	// LeftAt 退出時刻 (通話中の場合は無し)
+	LeftAt *time.Time `json:"leftAt,omitempty"`
+
+	// RoomId ルーム(チャンネル)のID
+	RoomId openapi_types.UUID `json:"roomId"`
+
+	// SessionId セッションのID
+	SessionId openapi_types.UUID `json:"sessionId"`
+
+	// UserId traQのユーザID
+	UserId string `json:"userId"`
+}
+
+// RoomHistoryResponse defines model for RoomHistoryResponse.
+type RoomHistoryResponse struct {
+	// NextOffset 続きを取得する際の offset (続きが無い場合は無し)
+	NextOffset *int          `json:"nextOffset,omitempty"`
+	Sessions   []CallSession `json:"sessions"`
 }
 
 // RoomWithParticipants defines model for RoomWithParticipants.
 type RoomWithParticipants struct {
+	// Egresses ルームで動いている録画・配信 (egress)
+	Egresses *[]Egress `json:"egresses,omitempty"`
+
+	// Ingresses ルームへの外部からの入力 (ingress)
+	Ingresses *[]Ingress `json:"ingresses,omitempty"`
+
 	// IsWebinar ウェビナールームかどうか
 	IsWebinar *bool `json:"isWebinar,omitempty"`
 
@@ -43,24 +195,75 @@ type RoomWithParticipants struct {
 // RoomsListResponse defines model for RoomsListResponse.
 type RoomsListResponse = []RoomWithParticipants
 
+// SoundboardDuplicateError defines model for SoundboardDuplicateError.
+type SoundboardDuplicateError struct {
+	Error string `json:"error"`
+
+	// SoundId 同じ内容の既存のサウンドID
+	SoundId *string `json:"soundId,omitempty"`
+}
+
+// SoundboardDuplicatePolicy 同じ内容のサウンドが既に登録されている場合の扱い。 existing は既存のサウンドを返し、conflict は 409 と既存の soundId を返す。
+type SoundboardDuplicatePolicy string
+
 // SoundboardItem defines model for SoundboardItem.
 type SoundboardItem struct {
+	// CreatedAt 登録日時
+	CreatedAt time.Time `json:"createdAt"`
+
 	// CreatorId 作成者のユーザID
 	CreatorId string `json:"creatorId"`
 
+	// DurationMs 音声の長さ (ミリ秒)。長さを記録する前にアップロードされたサウンドは 0
+	DurationMs int64 `json:"durationMs"`
+
+	// Favorite リクエストしたユーザのお気に入りかどうか
+	Favorite bool `json:"favorite"`
+
+	// Hidden 通報により非表示になっているかどうか (一覧には含まれないので、通常は確認待ちの一覧でだけ true になる)
+	Hidden bool `json:"hidden"`
+
 	// SoundId サーバが発行したサウンドID
 	SoundId string `json:"soundId"`
 
 	// SoundName ユーザが指定した表示用のサウンド名
-	SoundName string `json:"soundName"`
+	SoundName string            `json:"soundName"`
+	Source    *SoundboardSource `json:"source,omitempty"`
 
 	// StampId 任意のスタンプID等、サウンドに紐づく拡張情報
 	StampId string `json:"stampId"`
+
+	// Tags サウンドに付けられたタグ
+	Tags []string `json:"tags"`
+
+	// Waveform 波形のプレビュー。音声を 200 の区間に分けた、それぞれの区間のピーク (0〜1)。 波形を記録する前にアップロードされたサウンドでは空
+	Waveform []float32 `json:"waveform"`
 }
 
 // SoundboardListResponse defines model for SoundboardListResponse.
 type SoundboardListResponse = []SoundboardItem
 
+// SoundboardPack defines model for SoundboardPack.
+type SoundboardPack struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// CreatorId 作成者のユーザID
+	CreatorId   string `json:"creatorId"`
+	Description string `json:"description"`
+	Name        string `json:"name"`
+	PackId      string `json:"packId"`
+
+	// SoundIds パックに含まれるサウンドID
+	SoundIds []string `json:"soundIds"`
+}
+
+// SoundboardPackRequest defines model for SoundboardPackRequest.
+type SoundboardPackRequest struct {
+	Description *string  `json:"description,omitempty"`
+	Name        string   `json:"name"`
+	SoundIds    []string `json:"soundIds"`
+}
+
 // SoundboardPlayRequest defines model for SoundboardPlayRequest.
 type SoundboardPlayRequest struct {
 	// RoomName 再生させたいルームのUUID
@@ -72,8 +275,12 @@ type SoundboardPlayRequest struct {
 
 // SoundboardPlayResponse defines model for SoundboardPlayResponse.
 type SoundboardPlayResponse struct {
-	// IngressId 作成された Ingress のID
-	IngressId string `json:"ingressId"`
+	// IngressId 作成された Ingress のID (キューに追加された場合は無い)
+	IngressId *string `json:"ingressId,omitempty"`
+
+	// PlaybackId 再生のID (停止に使う)
+	PlaybackId string                   `json:"playbackId"`
+	Status     SoundboardPlaybackStatus `json:"status"`
 
 	// StreamKey RTMP配信の場合のstream key
 	StreamKey *string `json:"streamKey,omitempty"`
@@ -82,19 +289,299 @@ type SoundboardPlayResponse struct {
 	Url *string `json:"url,omitempty"`
 }
 
+// SoundboardPlayback defines model for SoundboardPlayback.
+type SoundboardPlayback struct {
+	// DurationMs サウンドの長さ (ミリ秒)
+	DurationMs int64 `json:"durationMs"`
+
+	// IngressId 再生に使っている Ingress のID (キューで待っている間や Ingress の作成中は無い)
+	IngressId  *string `json:"ingressId,omitempty"`
+	PlaybackId string  `json:"playbackId"`
+
+	// RequestedBy 再生したユーザのID
+	RequestedBy string `json:"requestedBy"`
+	SoundId     string `json:"soundId"`
+	SoundName   string `json:"soundName"`
+
+	// StartedAt 再生を開始した時刻 (キューで待っている間は無い)
+	StartedAt *time.Time               `json:"startedAt,omitempty"`
+	Status    SoundboardPlaybackStatus `json:"status"`
+}
+
+// SoundboardPlaybackState defines model for SoundboardPlaybackState.
+type SoundboardPlaybackState struct {
+	Playing []SoundboardPlayback `json:"playing"`
+
+	// Policy 再生中のサウンドがある時に新しいサウンドを再生した場合の扱い。 overlap は重ねて再生、queue は再生中のサウンドが終わるまで待つ、replace は再生中のサウンドを止めて再生する。
+	Policy SoundboardQueuePolicy `json:"policy"`
+
+	// Queue 再生を待っているサウンド (先頭から順に再生される)
+	Queue    []SoundboardPlayback `json:"queue"`
+	RoomName openapi_types.UUID   `json:"roomName"`
+}
+
+// SoundboardPlaybackStatus defines model for SoundboardPlaybackStatus.
+type SoundboardPlaybackStatus string
+
+// SoundboardPolicyRequest defines model for SoundboardPolicyRequest.
+type SoundboardPolicyRequest struct {
+	// Policy 再生中のサウンドがある時に新しいサウンドを再生した場合の扱い。 overlap は重ねて再生、queue は再生中のサウンドが終わるまで待つ、replace は再生中のサウンドを止めて再生する。
+	Policy SoundboardQueuePolicy `json:"policy"`
+
+	// RoomName ルームのUUID
+	RoomName openapi_types.UUID `json:"roomName"`
+}
+
+// SoundboardQueuePolicy 再生中のサウンドがある時に新しいサウンドを再生した場合の扱い。 overlap は重ねて再生、queue は再生中のサウンドが終わるまで待つ、replace は再生中のサウンドを止めて再生する。
+type SoundboardQueuePolicy string
+
+// SoundboardQuota defines model for SoundboardQuota.
+type SoundboardQuota struct {
+	Bytes      SoundboardQuotaUsage `json:"bytes"`
+	Sounds     SoundboardQuotaUsage `json:"sounds"`
+	TotalBytes SoundboardQuotaUsage `json:"totalBytes"`
+}
+
+// SoundboardQuotaError defines model for SoundboardQuotaError.
+type SoundboardQuotaError struct {
+	Code  SoundboardQuotaErrorCode `json:"code"`
+	Error string                   `json:"error"`
+
+	// Limit 超えた上限 (サウンドの数、もしくはバイト数)
+	Limit int64 `json:"limit"`
+
+	// Requested 登録しようとした分 (サウンドの数の場合は 1)
+	Requested int64 `json:"requested"`
+
+	// Used 登録済みの分
+	Used int64 `json:"used"`
+}
+
+// SoundboardQuotaErrorCode defines model for SoundboardQuotaErrorCode.
+type SoundboardQuotaErrorCode string
+
+// SoundboardQuotaUsage defines model for SoundboardQuotaUsage.
+type SoundboardQuotaUsage struct {
+	// Limit 上限。無制限の場合は省略
+	Limit *int64 `json:"limit,omitempty"`
+	Used  int64  `json:"used"`
+}
+
+// SoundboardRanking defines model for SoundboardRanking.
+type SoundboardRanking struct {
+	Period SoundboardStatsPeriod `json:"period"`
+
+	// Since 集計の開始日時 (period が all の場合は省略)
+	Since  *time.Time              `json:"since,omitempty"`
+	Sounds []SoundboardRankingItem `json:"sounds"`
+
+	// TopRooms サウンドの再生回数が多いルーム
+	TopRooms []SoundboardRoomPlays `json:"topRooms"`
+}
+
+// SoundboardRankingItem defines model for SoundboardRankingItem.
+type SoundboardRankingItem struct {
+	// Plays 期間内の再生回数
+	Plays     int    `json:"plays"`
+	SoundId   string `json:"soundId"`
+	SoundName string `json:"soundName"`
+	StampId   string `json:"stampId"`
+
+	// UniquePlayers 期間内に再生したユーザの数
+	UniquePlayers int `json:"uniquePlayers"`
+}
+
+// SoundboardRateLimitError defines model for SoundboardRateLimitError.
+type SoundboardRateLimitError struct {
+	Error string `json:"error"`
+
+	// RetryAfter 再び再生できるようになるまでの秒数 (Retry-After ヘッダと同じ値)
+	RetryAfter int `json:"retryAfter"`
+}
+
+// SoundboardReport defines model for SoundboardReport.
+type SoundboardReport struct {
+	// CreatedAt 通報した日時
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Reason 通報の理由 (指定されなかった場合は空)
+	Reason string `json:"reason"`
+
+	// ReporterId 通報したユーザのID
+	ReporterId string `json:"reporterId"`
+}
+
+// SoundboardReportRequest defines model for SoundboardReportRequest.
+type SoundboardReportRequest struct {
+	// Reason 通報の理由 (任意)
+	Reason *string `json:"reason,omitempty"`
+}
+
+// SoundboardReportResponse defines model for SoundboardReportResponse.
+type SoundboardReportResponse struct {
+	// Hidden サウンドが非表示になっているかどうか
+	Hidden bool `json:"hidden"`
+
+	// OpenReports サウンドの未対応の通報の数
+	OpenReports int64 `json:"openReports"`
+}
+
+// SoundboardReportedSound defines model for SoundboardReportedSound.
+type SoundboardReportedSound struct {
+	// LastReportedAt 最後に通報された日時
+	LastReportedAt time.Time `json:"lastReportedAt"`
+
+	// OpenReports 未対応の通報の数
+	OpenReports int64 `json:"openReports"`
+
+	// Reports 未対応の通報 (新しい順)
+	Reports []SoundboardReport `json:"reports"`
+	Sound   SoundboardItem     `json:"sound"`
+}
+
+// SoundboardRoomPlays defines model for SoundboardRoomPlays.
+type SoundboardRoomPlays struct {
+	Plays  int                `json:"plays"`
+	RoomId openapi_types.UUID `json:"roomId"`
+}
+
+// SoundboardSource defines model for SoundboardSource.
+type SoundboardSource struct {
+	Channels int `json:"channels"`
+
+	// Codec mp3 / pcm / vorbis / opus / flac / aac
+	Codec string `json:"codec"`
+
+	// DurationMs 加工前の長さ (ミリ秒)
+	DurationMs int64 `json:"durationMs"`
+	SampleRate int   `json:"sampleRate"`
+
+	// Sha256 ファイルの SHA-256 (16進数)
+	Sha256    string `json:"sha256"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// SoundboardStats defines model for SoundboardStats.
+type SoundboardStats struct {
+	// LastPlayedAt 最後に再生された日時 (期間内に再生されていない場合は省略)
+	LastPlayedAt *time.Time            `json:"lastPlayedAt,omitempty"`
+	Period       SoundboardStatsPeriod `json:"period"`
+
+	// Plays 再生回数
+	Plays   int    `json:"plays"`
+	SoundId string `json:"soundId"`
+
+	// TopPlayers 再生回数の多いユーザ
+	TopPlayers []SoundboardUserPlays `json:"topPlayers"`
+
+	// TopRooms 再生回数の多いルーム
+	TopRooms []SoundboardRoomPlays `json:"topRooms"`
+
+	// UniquePlayers 再生したユーザの数
+	UniquePlayers int `json:"uniquePlayers"`
+}
+
+// SoundboardStatsPeriod defines model for SoundboardStatsPeriod.
+type SoundboardStatsPeriod string
+
+// SoundboardStopRequest defines model for SoundboardStopRequest.
+type SoundboardStopRequest struct {
+	// PlaybackId 止める再生のID。省略するとルームの全ての再生を止める
+	PlaybackId *string `json:"playbackId,omitempty"`
+
+	// RoomName ルームのUUID
+	RoomName openapi_types.UUID `json:"roomName"`
+}
+
+// SoundboardTag defines model for SoundboardTag.
+type SoundboardTag struct {
+	// Count タグが付いているサウンドの数
+	Count int    `json:"count"`
+	Tag   string `json:"tag"`
+}
+
+// SoundboardUpdateRequest defines model for SoundboardUpdateRequest.
+type SoundboardUpdateRequest struct {
+	// SoundName 新しいサウンド名
+	SoundName *string `json:"soundName,omitempty"`
+
+	// StampId 新しいスタンプID
+	StampId *string `json:"stampId,omitempty"`
+
+	// Tags 新しいタグ (指定したタグで置き換える。最大10個、各64文字以内)
+	Tags *[]string `json:"tags,omitempty"`
+}
+
 // SoundboardUploadRequest defines model for SoundboardUploadRequest.
 type SoundboardUploadRequest struct {
-	// Audio アップロードする音声ファイル(20秒以内)
+	// Audio アップロードする音声ファイル(20秒以内。mp3 / wav / ogg / flac / m4a / webm)
 	Audio openapi_types.File `json:"audio"`
 
+	// EndMs 切り出しを終える位置 (ミリ秒)。startMs より後。指定しない場合は最後まで
+	EndMs *int `json:"endMs,omitempty"`
+
+	// FadeInMs 先頭にかけるフェードインの長さ (ミリ秒)
+	FadeInMs *int `json:"fadeInMs,omitempty"`
+
+	// FadeOutMs 末尾にかけるフェードアウトの長さ (ミリ秒)
+	FadeOutMs *int `json:"fadeOutMs,omitempty"`
+
 	// SoundName ユーザが自由につけるサウンド名
 	SoundName string `json:"soundName"`
+
+	// StartMs 切り出しを始める位置 (ミリ秒)。指定しない場合は先頭から
+	StartMs *int `json:"startMs,omitempty"`
 }
 
 // SoundboardUploadResponse defines model for SoundboardUploadResponse.
 type SoundboardUploadResponse struct {
+	// Duplicate true の場合は同じ内容の既存のサウンドを返している
+	Duplicate bool `json:"duplicate"`
+
+	// DurationMs 加工後の長さ (ミリ秒)
+	DurationMs int64 `json:"durationMs"`
+
 	// SoundId 登録されたサウンドID (ファイル名)
 	SoundId string `json:"soundId"`
+
+	// Waveform 加工後の波形のプレビュー。音声を等間隔の区間に分けた、それぞれの区間のピーク (0〜1)。 波形を記録する前にアップロードされたサウンドでは空
+	Waveform []float32 `json:"waveform"`
+}
+
+// SoundboardUploadUrlRequest defines model for SoundboardUploadUrlRequest.
+type SoundboardUploadUrlRequest struct {
+	// ContentType アップロード時に付ける Content-Type (形式はファイルの中身で判定するので video/webm なども可)
+	ContentType string `json:"contentType"`
+
+	// FileName アップロードするファイル名 (形式はファイルの中身で判定し、保存するファイルの拡張子も中身から決めるので参考情報)
+	FileName string `json:"fileName"`
+
+	// Size ファイルサイズ (バイト)
+	Size int64 `json:"size"`
+
+	// SoundName ユーザが自由につけるサウンド名
+	SoundName string `json:"soundName"`
+
+	// StampId サウンドに紐づけるスタンプID
+	StampId string `json:"stampId"`
+}
+
+// SoundboardUploadUrlResponse defines model for SoundboardUploadUrlResponse.
+type SoundboardUploadUrlResponse struct {
+	// ExpiresAt この時刻までにアップロードを完了させる必要がある
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// UploadId アップロードID (完了後の soundId)
+	UploadId string `json:"uploadId"`
+
+	// UploadUrl ファイルを PUT する署名付き URL
+	UploadUrl string `json:"uploadUrl"`
+}
+
+// SoundboardUserPlays defines model for SoundboardUserPlays.
+type SoundboardUserPlays struct {
+	Plays  int    `json:"plays"`
+	UserId string `json:"userId"`
 }
 
 // TokenResponse defines model for TokenResponse.
@@ -103,6 +590,175 @@ type TokenResponse struct {
 	Token string `json:"token"`
 }
 
+// Track 参加者が公開しているトラック
+type Track struct {
+	// Muted ミュート中かどうか
+	Muted *bool `json:"muted,omitempty"`
+
+	// Sid トラックのID
+	Sid string `json:"sid"`
+
+	// Source トラックの種類
+	Source TrackSource `json:"source"`
+}
+
+// TrackSource トラックの種類
+type TrackSource string
+
+// UserHistoryResponse defines model for UserHistoryResponse.
+type UserHistoryResponse struct {
+	// NextOffset 続きを取得する際の offset (続きが無い場合は無し)
+	NextOffset *int               `json:"nextOffset,omitempty"`
+	Stints     []ParticipantStint `json:"stints"`
+}
+
+// WsClientMessage クライアントからサーバへ送るメッセージ。 resync は最新の snapshot を要求し、subscribe / unsubscribe は購読するルームを変更する (変更後は購読中のルームだけを含む snapshot が返される)。
+type WsClientMessage struct {
+	// Global true の場合、全ルームの購読を開始・解除する
+	Global *bool `json:"global,omitempty"`
+
+	// RoomIds 購読を追加・解除するルームのUUID
+	RoomIds *[]openapi_types.UUID `json:"roomIds,omitempty"`
+	Type    WsClientMessageType   `json:"type"`
+}
+
+// WsClientMessageType defines model for WsClientMessage.Type.
+type WsClientMessageType string
+
+// WsEgressPayload defines model for WsEgressPayload.
+type WsEgressPayload struct {
+	// Egress ルームの録画・配信
+	Egress Egress             `json:"egress"`
+	RoomId openapi_types.UUID `json:"roomId"`
+}
+
+// WsEvent WebSocketでサーバから送られるイベントのエンベロープ
+type WsEvent struct {
+	// Payload type ごとのペイロード。snapshot は WsSnapshotPayload、room_started は RoomWithParticipants、 それ以外は Ws{Type}Payload (例: participant_joined は WsParticipantJoinedPayload)
+	Payload interface{} `json:"payload"`
+
+	// RoomId イベントの対象ルーム (snapshot には無し)
+	RoomId *openapi_types.UUID `json:"roomId,omitempty"`
+
+	// Seq 接続ごとのメッセージの通し番号。snapshot を含めてサーバから送るたびに1ずつ増える。 番号が飛んだ場合、クライアントは resync を送って snapshot を受け取り直す。
+	Seq  int64       `json:"seq"`
+	Type WsEventType `json:"type"`
+
+	// Version プロトコルのバージョン (現在は 1)
+	Version int `json:"version"`
+}
+
+// WsEventType defines model for WsEventType.
+type WsEventType string
+
+// WsIngressPayload defines model for WsIngressPayload.
+type WsIngressPayload struct {
+	// Ingress ルームへの外部からの入力 (RTMP / WHIP 等)
+	Ingress Ingress            `json:"ingress"`
+	RoomId  openapi_types.UUID `json:"roomId"`
+}
+
+// WsMetadataChangedPayload defines model for WsMetadataChangedPayload.
+type WsMetadataChangedPayload struct {
+	IsWebinar *bool `json:"isWebinar,omitempty"`
+
+	// Metadata 変更後のメタデータ
+	Metadata string             `json:"metadata"`
+	RoomId   openapi_types.UUID `json:"roomId"`
+}
+
+// WsParticipantJoinedPayload defines model for WsParticipantJoinedPayload.
+type WsParticipantJoinedPayload struct {
+	// Participant ルーム内の参加者一覧
+	Participant Participant        `json:"participant"`
+	RoomId      openapi_types.UUID `json:"roomId"`
+}
+
+// WsParticipantLeftPayload defines model for WsParticipantLeftPayload.
+type WsParticipantLeftPayload struct {
+	// Identity 退出した参加者の identity
+	Identity string             `json:"identity"`
+	RoomId   openapi_types.UUID `json:"roomId"`
+}
+
+// WsPermissionChangedPayload defines model for WsPermissionChangedPayload.
+type WsPermissionChangedPayload struct {
+	CanPublish bool               `json:"canPublish"`
+	Identity   string             `json:"identity"`
+	RoomId     openapi_types.UUID `json:"roomId"`
+}
+
+// WsRoomFinishedPayload defines model for WsRoomFinishedPayload.
+type WsRoomFinishedPayload struct {
+	RoomId openapi_types.UUID `json:"roomId"`
+}
+
+// WsSnapshotPayload defines model for WsSnapshotPayload.
+type WsSnapshotPayload struct {
+	Rooms []RoomWithParticipants `json:"rooms"`
+}
+
+// WsTrackPayload defines model for WsTrackPayload.
+type WsTrackPayload struct {
+	// Identity トラックを公開している参加者の identity
+	Identity string             `json:"identity"`
+	RoomId   openapi_types.UUID `json:"roomId"`
+
+	// Track 参加者が公開しているトラック
+	Track Track `json:"track"`
+}
+
+// LimitParam defines model for limitParam.
+type LimitParam = int
+
+// OffsetParam defines model for offsetParam.
+type OffsetParam = int
+
+// OnDuplicateParam 同じ内容のサウンドが既に登録されている場合の扱い。 existing は既存のサウンドを返し、conflict は 409 と既存の soundId を返す。
+type OnDuplicateParam = SoundboardDuplicatePolicy
+
+// GetFileParams defines parameters for GetFile.
+type GetFileParams struct {
+	// Expires URL の有効期限 (UNIX 時間)
+	Expires int64 `form:"expires" json:"expires"`
+
+	// Signature URL の署名
+	Signature string `form:"signature" json:"signature"`
+}
+
+// PutFileParams defines parameters for PutFile.
+type PutFileParams struct {
+	// Expires URL の有効期限 (UNIX 時間)
+	Expires int64 `form:"expires" json:"expires"`
+
+	// Signature URL の署名
+	Signature string `form:"signature" json:"signature"`
+}
+
+// GetRoomsStreamParams defines parameters for GetRoomsStream.
+type GetRoomsStreamParams struct {
+	// AccessToken traQのトークン (Authorization ヘッダを付けられない EventSource の場合のみ)
+	AccessToken *string `form:"access_token,omitempty" json:"access_token,omitempty"`
+
+	// Rooms 購読するルームのUUID (カンマ区切り)
+	Rooms *[]openapi_types.UUID `form:"rooms,omitempty" json:"rooms,omitempty"`
+
+	// Global true の場合、全ルームのイベントを購読する
+	Global *bool `form:"global,omitempty" json:"global,omitempty"`
+
+	// LastEventID 最後に受け取ったイベントの id (再接続時)
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// GetRoomHistoryParams defines parameters for GetRoomHistory.
+type GetRoomHistoryParams struct {
+	// Limit 取得する件数(最大100)
+	Limit *LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset 取得を開始する位置
+	Offset *OffsetParam `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
 // UpdateRoomMetadataJSONBody defines parameters for UpdateRoomMetadata.
 type UpdateRoomMetadataJSONBody struct {
 	// Metadata ルームに関連付けられたカスタム属性
@@ -112,6 +768,66 @@ type UpdateRoomMetadataJSONBody struct {
 // ChangeParticipantRoleJSONBody defines parameters for ChangeParticipantRole.
 type ChangeParticipantRoleJSONBody = []Participant
 
+// GetSoundboardListParams defines parameters for GetSoundboardList.
+type GetSoundboardListParams struct {
+	// CreatorId 作成者のユーザIDで絞り込む
+	CreatorId *string `form:"creatorId,omitempty" json:"creatorId,omitempty"`
+
+	// Q サウンド名で検索する (全文検索と部分一致)
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// Tag タグで絞り込む
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Favorite true の場合、リクエストしたユーザのお気に入りだけを返す
+	Favorite *bool `form:"favorite,omitempty" json:"favorite,omitempty"`
+
+	// Pack パックIDで絞り込む
+	Pack *string `form:"pack,omitempty" json:"pack,omitempty"`
+
+	// ChannelId チャンネル(ルーム)で有効なパックのサウンドだけを返す。 有効なパックが無いチャンネルでは絞り込まない
+	ChannelId *openapi_types.UUID `form:"channelId,omitempty" json:"channelId,omitempty"`
+
+	// Limit 取得する件数(最大100)。続きは X-Next-Cursor を cursor に指定して取得する。 limit も cursor も省略した場合はページ分けせずに全件返し、cursor だけを指定した場合は 100 件ずつ返す
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor 前のページの X-Next-Cursor
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// PostSoundboardParams defines parameters for PostSoundboard.
+type PostSoundboardParams struct {
+	// OnDuplicate 同じ内容のサウンドが既に登録されている場合の扱い (省略時は existing)
+	OnDuplicate *OnDuplicateParam `form:"onDuplicate,omitempty" json:"onDuplicate,omitempty"`
+}
+
+// GetSoundboardPlaybackParams defines parameters for GetSoundboardPlayback.
+type GetSoundboardPlaybackParams struct {
+	// RoomName ルームのUUID
+	RoomName openapi_types.UUID `form:"roomName" json:"roomName"`
+}
+
+// GetSoundboardRankingParams defines parameters for GetSoundboardRanking.
+type GetSoundboardRankingParams struct {
+	// Period 集計する期間 (省略時は week)
+	Period *SoundboardStatsPeriod `form:"period,omitempty" json:"period,omitempty"`
+
+	// Limit 取得する件数(最大100)
+	Limit *LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostSoundboardUploadCompleteParams defines parameters for PostSoundboardUploadComplete.
+type PostSoundboardUploadCompleteParams struct {
+	// OnDuplicate 同じ内容のサウンドが既に登録されている場合の扱い (省略時は existing)
+	OnDuplicate *OnDuplicateParam `form:"onDuplicate,omitempty" json:"onDuplicate,omitempty"`
+}
+
+// GetSoundboardStatsParams defines parameters for GetSoundboardStats.
+type GetSoundboardStatsParams struct {
+	// Period 集計する期間 (省略時は all)
+	Period *SoundboardStatsPeriod `form:"period,omitempty" json:"period,omitempty"`
+}
+
 // GetLiveKitTokenParams defines parameters for GetLiveKitToken.
 type GetLiveKitTokenParams struct {
 	// Room 参加するルームのUUID
@@ -121,9 +837,33 @@ type GetLiveKitTokenParams struct {
 	IsWebinar *bool `form:"isWebinar,omitempty" json:"isWebinar,omitempty"`
 }
 
+// GetUserHistoryParams defines parameters for GetUserHistory.
+type GetUserHistoryParams struct {
+	// Limit 取得する件数(最大100)
+	Limit *LimitParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset 取得を開始する位置
+	Offset *OffsetParam `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
 // LiveKitWebhookApplicationWebhookPlusJSONBody defines parameters for LiveKitWebhook.
 type LiveKitWebhookApplicationWebhookPlusJSONBody = map[string]interface{}
 
+// GetWsParams defines parameters for GetWs.
+type GetWsParams struct {
+	// AccessToken traQのトークン (非推奨。Authorization ヘッダかサブプロトコル `bearer.{token}` を使ってください)
+	AccessToken *string `form:"access_token,omitempty" json:"access_token,omitempty"`
+
+	// Rooms 接続直後から購読するルームのUUID (カンマ区切り)
+	Rooms *[]openapi_types.UUID `form:"rooms,omitempty" json:"rooms,omitempty"`
+
+	// Global true の場合、全ルームのイベントを購読する
+	Global *bool `form:"global,omitempty" json:"global,omitempty"`
+}
+
+// PutChannelSoundboardPacksJSONRequestBody defines body for PutChannelSoundboardPacks for application/json ContentType.
+type PutChannelSoundboardPacksJSONRequestBody = ChannelSoundboardPacksRequest
+
 // UpdateRoomMetadataJSONRequestBody defines body for UpdateRoomMetadata for application/json ContentType.
 type UpdateRoomMetadataJSONRequestBody UpdateRoomMetadataJSONBody
 
@@ -133,8 +873,29 @@ type ChangeParticipantRoleJSONRequestBody = ChangeParticipantRoleJSONBody
 // PostSoundboardMultipartRequestBody defines body for PostSoundboard for multipart/form-data ContentType.
 type PostSoundboardMultipartRequestBody = SoundboardUploadRequest
 
+// PostSoundboardPackJSONRequestBody defines body for PostSoundboardPack for application/json ContentType.
+type PostSoundboardPackJSONRequestBody = SoundboardPackRequest
+
+// PutSoundboardPackJSONRequestBody defines body for PutSoundboardPack for application/json ContentType.
+type PutSoundboardPackJSONRequestBody = SoundboardPackRequest
+
 // PostSoundboardPlayJSONRequestBody defines body for PostSoundboardPlay for application/json ContentType.
 type PostSoundboardPlayJSONRequestBody = SoundboardPlayRequest
 
+// PutSoundboardPlaybackPolicyJSONRequestBody defines body for PutSoundboardPlaybackPolicy for application/json ContentType.
+type PutSoundboardPlaybackPolicyJSONRequestBody = SoundboardPolicyRequest
+
+// PostSoundboardStopJSONRequestBody defines body for PostSoundboardStop for application/json ContentType.
+type PostSoundboardStopJSONRequestBody = SoundboardStopRequest
+
+// PostSoundboardUploadJSONRequestBody defines body for PostSoundboardUpload for application/json ContentType.
+type PostSoundboardUploadJSONRequestBody = SoundboardUploadUrlRequest
+
+// PatchSoundboardJSONRequestBody defines body for PatchSoundboard for application/json ContentType.
+type PatchSoundboardJSONRequestBody = SoundboardUpdateRequest
+
+// PostSoundboardReportJSONRequestBody defines body for PostSoundboardReport for application/json ContentType.
+type PostSoundboardReportJSONRequestBody = SoundboardReportRequest
+
 // LiveKitWebhookApplicationWebhookPlusJSONRequestBody defines body for LiveKitWebhook for application/webhook+json ContentType.
 type LiveKitWebhookApplicationWebhookPlusJSONRequestBody = LiveKitWebhookApplicationWebhookPlusJSONBody
@@ -22,12 +22,42 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// 通報されたサウンドの確認待ちの一覧を取得 (管理者のみ)
+	// (GET /admin/soundboard/reports)
+	GetAdminSoundboardReports(ctx echo.Context) error
+	// 通報されたサウンドを承認 (管理者のみ)
+	// (POST /admin/soundboard/reports/{soundId}/approve)
+	PostAdminSoundboardReportApprove(ctx echo.Context, soundId string) error
+	// 通報されたサウンドを削除 (管理者のみ)
+	// (POST /admin/soundboard/reports/{soundId}/remove)
+	PostAdminSoundboardReportRemove(ctx echo.Context, soundId string) error
+	// チャンネルで有効なサウンドパックを取得
+	// (GET /channels/{channelId}/soundboard-packs)
+	GetChannelSoundboardPacks(ctx echo.Context, channelId openapi_types.UUID) error
+	// チャンネルで有効なサウンドパックを変更
+	// (PUT /channels/{channelId}/soundboard-packs)
+	PutChannelSoundboardPacks(ctx echo.Context, channelId openapi_types.UUID) error
+	// 保存したファイルを署名付き URL で取得
+	// (GET /files/{key})
+	GetFile(ctx echo.Context, key string, params GetFileParams) error
+	// 署名付き URL でファイルをアップロード
+	// (PUT /files/{key})
+	PutFile(ctx echo.Context, key string, params PutFileParams) error
+	// メトリクスを取得
+	// (GET /metrics)
+	GetMetrics(ctx echo.Context) error
 	// サーバーの生存確認
 	// (GET /ping)
 	PingServer(ctx echo.Context) error
 	// ルームと参加者の一覧を取得
 	// (GET /rooms)
 	GetRooms(ctx echo.Context) error
+	// ルーム状態のイベントを Server-Sent Events で受け取る
+	// (GET /rooms/stream)
+	GetRoomsStream(ctx echo.Context, params GetRoomsStreamParams) error
+	// ルームの通話履歴を取得
+	// (GET /rooms/{roomId}/history)
+	GetRoomHistory(ctx echo.Context, roomId openapi_types.UUID, params GetRoomHistoryParams) error
 	// ルームのメタデータを取得
 	// (GET /rooms/{roomId}/metadata)
 	GetRoomMetadata(ctx echo.Context, roomId openapi_types.UUID) error
@@ -39,25 +69,88 @@ type ServerInterface interface {
 	ChangeParticipantRole(ctx echo.Context, roomId openapi_types.UUID) error
 	// サウンドボード用の音声一覧を取得
 	// (GET /soundboard)
-	GetSoundboardList(ctx echo.Context) error
+	GetSoundboardList(ctx echo.Context, params GetSoundboardListParams) error
 	// サウンドボード用の短い音声ファイルをアップロード
 	// (POST /soundboard)
-	PostSoundboard(ctx echo.Context) error
+	PostSoundboard(ctx echo.Context, params PostSoundboardParams) error
+	// サウンドパックの一覧を取得
+	// (GET /soundboard/packs)
+	GetSoundboardPacks(ctx echo.Context) error
+	// サウンドパックを作成
+	// (POST /soundboard/packs)
+	PostSoundboardPack(ctx echo.Context) error
+	// サウンドパックを削除
+	// (DELETE /soundboard/packs/{packId})
+	DeleteSoundboardPack(ctx echo.Context, packId string) error
+	// サウンドパックを取得
+	// (GET /soundboard/packs/{packId})
+	GetSoundboardPack(ctx echo.Context, packId string) error
+	// サウンドパックを更新
+	// (PUT /soundboard/packs/{packId})
+	PutSoundboardPack(ctx echo.Context, packId string) error
 	// アップロード済み音声を LiveKit ルームで再生
 	// (POST /soundboard/play)
 	PostSoundboardPlay(ctx echo.Context) error
+	// ルームのサウンドの再生状況を取得
+	// (GET /soundboard/playback)
+	GetSoundboardPlayback(ctx echo.Context, params GetSoundboardPlaybackParams) error
+	// ルームのキューポリシーを変更
+	// (PUT /soundboard/playback/policy)
+	PutSoundboardPlaybackPolicy(ctx echo.Context) error
+	// サウンドの登録数・サイズの使用量と上限を取得
+	// (GET /soundboard/quota)
+	GetSoundboardQuota(ctx echo.Context) error
+	// よく再生されたサウンドのランキングを取得
+	// (GET /soundboard/ranking)
+	GetSoundboardRanking(ctx echo.Context, params GetSoundboardRankingParams) error
+	// ルームで再生中のサウンドを止める
+	// (POST /soundboard/stop)
+	PostSoundboardStop(ctx echo.Context) error
+	// サウンドに付けられているタグの一覧を取得
+	// (GET /soundboard/tags)
+	GetSoundboardTags(ctx echo.Context) error
+	// サウンドを直接ストレージへアップロードするための URL を発行
+	// (POST /soundboard/uploads)
+	PostSoundboardUpload(ctx echo.Context) error
+	// 直接アップロードしたサウンドを登録
+	// (POST /soundboard/uploads/{uploadId}/complete)
+	PostSoundboardUploadComplete(ctx echo.Context, uploadId string, params PostSoundboardUploadCompleteParams) error
+	// サウンドを削除
+	// (DELETE /soundboard/{soundId})
+	DeleteSoundboard(ctx echo.Context, soundId string) error
+	// サウンドの名前・スタンプを変更
+	// (PATCH /soundboard/{soundId})
+	PatchSoundboard(ctx echo.Context, soundId string) error
+	// サウンドをお気に入りから外す
+	// (DELETE /soundboard/{soundId}/favorite)
+	DeleteSoundboardFavorite(ctx echo.Context, soundId string) error
+	// サウンドをお気に入りに追加
+	// (PUT /soundboard/{soundId}/favorite)
+	PutSoundboardFavorite(ctx echo.Context, soundId string) error
+	// サウンドの試聴用ファイルを取得
+	// (GET /soundboard/{soundId}/preview)
+	GetSoundboardPreview(ctx echo.Context, soundId string) error
+	// サウンドを通報
+	// (POST /soundboard/{soundId}/report)
+	PostSoundboardReport(ctx echo.Context, soundId string) error
+	// サウンドの再生統計を取得
+	// (GET /soundboard/{soundId}/stats)
+	GetSoundboardStats(ctx echo.Context, soundId string, params GetSoundboardStatsParams) error
 	// テスト用
 	// (GET /test)
 	Test(ctx echo.Context) error
 	// LiveKitトークンを取得
 	// (GET /token)
 	GetLiveKitToken(ctx echo.Context, params GetLiveKitTokenParams) error
+	// ユーザの通話参加履歴を取得
+	// (GET /users/{userId}/history)
+	GetUserHistory(ctx echo.Context, userId string, params GetUserHistoryParams) error
 	// LiveKit Webhook受信
 	// (POST /webhook)
 	LiveKitWebhook(ctx echo.Context) error
 	// WebSocketエンドポイント
 	// (GET /ws)
-	GetWs(ctx echo.Context) error
+	GetWs(ctx echo.Context, params GetWsParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -65,6 +158,152 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetAdminSoundboardReports converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminSoundboardReports(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminSoundboardReports(ctx)
+	return err
+}
+
+// PostAdminSoundboardReportApprove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminSoundboardReportApprove(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminSoundboardReportApprove(ctx, soundId)
+	return err
+}
+
+// PostAdminSoundboardReportRemove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminSoundboardReportRemove(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminSoundboardReportRemove(ctx, soundId)
+	return err
+}
+
+// GetChannelSoundboardPacks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetChannelSoundboardPacks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "channelId" -------------
+	var channelId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "channelId", ctx.Param("channelId"), &channelId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter channelId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetChannelSoundboardPacks(ctx, channelId)
+	return err
+}
+
+// PutChannelSoundboardPacks converts echo context to params.
+func (w *ServerInterfaceWrapper) PutChannelSoundboardPacks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "channelId" -------------
+	var channelId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "channelId", ctx.Param("channelId"), &channelId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter channelId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutChannelSoundboardPacks(ctx, channelId)
+	return err
+}
+
+// GetFile converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "key" -------------
+	var key string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key", ctx.Param("key"), &key, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter key: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFileParams
+	// ------------- Required query parameter "expires" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "expires", ctx.QueryParams(), &params.Expires)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter expires: %s", err))
+	}
+
+	// ------------- Required query parameter "signature" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "signature", ctx.QueryParams(), &params.Signature)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter signature: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetFile(ctx, key, params)
+	return err
+}
+
+// PutFile converts echo context to params.
+func (w *ServerInterfaceWrapper) PutFile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "key" -------------
+	var key string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key", ctx.Param("key"), &key, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter key: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutFileParams
+	// ------------- Required query parameter "expires" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "expires", ctx.QueryParams(), &params.Expires)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter expires: %s", err))
+	}
+
+	// ------------- Required query parameter "signature" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "signature", ctx.QueryParams(), &params.Signature)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter signature: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutFile(ctx, key, params)
+	return err
+}
+
+// GetMetrics converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMetrics(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetMetrics(ctx)
+	return err
+}
+
 // PingServer converts echo context to params.
 func (w *ServerInterfaceWrapper) PingServer(ctx echo.Context) error {
 	var err error
@@ -83,6 +322,87 @@ func (w *ServerInterfaceWrapper) GetRooms(ctx echo.Context) error {
 	return err
 }
 
+// GetRoomsStream converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRoomsStream(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRoomsStreamParams
+	// ------------- Optional query parameter "access_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "access_token", ctx.QueryParams(), &params.AccessToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter access_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "rooms" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "rooms", ctx.QueryParams(), &params.Rooms)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rooms: %s", err))
+	}
+
+	// ------------- Optional query parameter "global" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "global", ctx.QueryParams(), &params.Global)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter global: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRoomsStream(ctx, params)
+	return err
+}
+
+// GetRoomHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRoomHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "roomId" -------------
+	var roomId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "roomId", ctx.Param("roomId"), &roomId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter roomId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRoomHistoryParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRoomHistory(ctx, roomId, params)
+	return err
+}
+
 // GetRoomMetadata converts echo context to params.
 func (w *ServerInterfaceWrapper) GetRoomMetadata(ctx echo.Context) error {
 	var err error
@@ -135,8 +455,66 @@ func (w *ServerInterfaceWrapper) ChangeParticipantRole(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) GetSoundboardList(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSoundboardListParams
+	// ------------- Optional query parameter "creatorId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "creatorId", ctx.QueryParams(), &params.CreatorId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter creatorId: %s", err))
+	}
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", ctx.QueryParams(), &params.Tag)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag: %s", err))
+	}
+
+	// ------------- Optional query parameter "favorite" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "favorite", ctx.QueryParams(), &params.Favorite)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter favorite: %s", err))
+	}
+
+	// ------------- Optional query parameter "pack" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pack", ctx.QueryParams(), &params.Pack)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pack: %s", err))
+	}
+
+	// ------------- Optional query parameter "channelId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "channelId", ctx.QueryParams(), &params.ChannelId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter channelId: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetSoundboardList(ctx)
+	err = w.Handler.GetSoundboardList(ctx, params)
 	return err
 }
 
@@ -144,8 +522,83 @@ func (w *ServerInterfaceWrapper) GetSoundboardList(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) PostSoundboard(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostSoundboardParams
+	// ------------- Optional query parameter "onDuplicate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "onDuplicate", ctx.QueryParams(), &params.OnDuplicate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter onDuplicate: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSoundboard(ctx, params)
+	return err
+}
+
+// GetSoundboardPacks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardPacks(ctx echo.Context) error {
+	var err error
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostSoundboard(ctx)
+	err = w.Handler.GetSoundboardPacks(ctx)
+	return err
+}
+
+// PostSoundboardPack converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSoundboardPack(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSoundboardPack(ctx)
+	return err
+}
+
+// DeleteSoundboardPack converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteSoundboardPack(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "packId" -------------
+	var packId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "packId", ctx.Param("packId"), &packId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter packId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteSoundboardPack(ctx, packId)
+	return err
+}
+
+// GetSoundboardPack converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardPack(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "packId" -------------
+	var packId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "packId", ctx.Param("packId"), &packId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter packId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardPack(ctx, packId)
+	return err
+}
+
+// PutSoundboardPack converts echo context to params.
+func (w *ServerInterfaceWrapper) PutSoundboardPack(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "packId" -------------
+	var packId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "packId", ctx.Param("packId"), &packId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter packId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutSoundboardPack(ctx, packId)
 	return err
 }
 
@@ -158,6 +611,240 @@ func (w *ServerInterfaceWrapper) PostSoundboardPlay(ctx echo.Context) error {
 	return err
 }
 
+// GetSoundboardPlayback converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardPlayback(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSoundboardPlaybackParams
+	// ------------- Required query parameter "roomName" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "roomName", ctx.QueryParams(), &params.RoomName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter roomName: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardPlayback(ctx, params)
+	return err
+}
+
+// PutSoundboardPlaybackPolicy converts echo context to params.
+func (w *ServerInterfaceWrapper) PutSoundboardPlaybackPolicy(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutSoundboardPlaybackPolicy(ctx)
+	return err
+}
+
+// GetSoundboardQuota converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardQuota(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardQuota(ctx)
+	return err
+}
+
+// GetSoundboardRanking converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardRanking(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSoundboardRankingParams
+	// ------------- Optional query parameter "period" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "period", ctx.QueryParams(), &params.Period)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter period: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardRanking(ctx, params)
+	return err
+}
+
+// PostSoundboardStop converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSoundboardStop(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSoundboardStop(ctx)
+	return err
+}
+
+// GetSoundboardTags converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardTags(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardTags(ctx)
+	return err
+}
+
+// PostSoundboardUpload converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSoundboardUpload(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSoundboardUpload(ctx)
+	return err
+}
+
+// PostSoundboardUploadComplete converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSoundboardUploadComplete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "uploadId" -------------
+	var uploadId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uploadId", ctx.Param("uploadId"), &uploadId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter uploadId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostSoundboardUploadCompleteParams
+	// ------------- Optional query parameter "onDuplicate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "onDuplicate", ctx.QueryParams(), &params.OnDuplicate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter onDuplicate: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSoundboardUploadComplete(ctx, uploadId, params)
+	return err
+}
+
+// DeleteSoundboard converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteSoundboard(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteSoundboard(ctx, soundId)
+	return err
+}
+
+// PatchSoundboard converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchSoundboard(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchSoundboard(ctx, soundId)
+	return err
+}
+
+// DeleteSoundboardFavorite converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteSoundboardFavorite(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteSoundboardFavorite(ctx, soundId)
+	return err
+}
+
+// PutSoundboardFavorite converts echo context to params.
+func (w *ServerInterfaceWrapper) PutSoundboardFavorite(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutSoundboardFavorite(ctx, soundId)
+	return err
+}
+
+// GetSoundboardPreview converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardPreview(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardPreview(ctx, soundId)
+	return err
+}
+
+// PostSoundboardReport converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSoundboardReport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSoundboardReport(ctx, soundId)
+	return err
+}
+
+// GetSoundboardStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoundboardStats(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "soundId" -------------
+	var soundId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "soundId", ctx.Param("soundId"), &soundId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter soundId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSoundboardStatsParams
+	// ------------- Optional query parameter "period" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "period", ctx.QueryParams(), &params.Period)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter period: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoundboardStats(ctx, soundId, params)
+	return err
+}
+
 // Test converts echo context to params.
 func (w *ServerInterfaceWrapper) Test(ctx echo.Context) error {
 	var err error
@@ -192,6 +879,38 @@ func (w *ServerInterfaceWrapper) GetLiveKitToken(ctx echo.Context) error {
 	return err
 }
 
+// GetUserHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUserHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserHistoryParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUserHistory(ctx, userId, params)
+	return err
+}
+
 // LiveKitWebhook converts echo context to params.
 func (w *ServerInterfaceWrapper) LiveKitWebhook(ctx echo.Context) error {
 	var err error
@@ -205,8 +924,31 @@ func (w *ServerInterfaceWrapper) LiveKitWebhook(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) GetWs(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetWsParams
+	// ------------- Optional query parameter "access_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "access_token", ctx.QueryParams(), &params.AccessToken)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter access_token: %s", err))
+	}
+
+	// ------------- Optional query parameter "rooms" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "rooms", ctx.QueryParams(), &params.Rooms)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rooms: %s", err))
+	}
+
+	// ------------- Optional query parameter "global" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "global", ctx.QueryParams(), &params.Global)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter global: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetWs(ctx)
+	err = w.Handler.GetWs(ctx, params)
 	return err
 }
 
@@ -238,16 +980,47 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/admin/soundboard/reports", wrapper.GetAdminSoundboardReports)
+	router.POST(baseURL+"/admin/soundboard/reports/:soundId/approve", wrapper.PostAdminSoundboardReportApprove)
+	router.POST(baseURL+"/admin/soundboard/reports/:soundId/remove", wrapper.PostAdminSoundboardReportRemove)
+	router.GET(baseURL+"/channels/:channelId/soundboard-packs", wrapper.GetChannelSoundboardPacks)
+	router.PUT(baseURL+"/channels/:channelId/soundboard-packs", wrapper.PutChannelSoundboardPacks)
+	router.GET(baseURL+"/files/:key", wrapper.GetFile)
+	router.PUT(baseURL+"/files/:key", wrapper.PutFile)
+	router.GET(baseURL+"/metrics", wrapper.GetMetrics)
 	router.GET(baseURL+"/ping", wrapper.PingServer)
 	router.GET(baseURL+"/rooms", wrapper.GetRooms)
+	router.GET(baseURL+"/rooms/stream", wrapper.GetRoomsStream)
+	router.GET(baseURL+"/rooms/:roomId/history", wrapper.GetRoomHistory)
 	router.GET(baseURL+"/rooms/:roomId/metadata", wrapper.GetRoomMetadata)
 	router.PATCH(baseURL+"/rooms/:roomId/metadata", wrapper.UpdateRoomMetadata)
 	router.PATCH(baseURL+"/rooms/:roomId/participants", wrapper.ChangeParticipantRole)
 	router.GET(baseURL+"/soundboard", wrapper.GetSoundboardList)
 	router.POST(baseURL+"/soundboard", wrapper.PostSoundboard)
+	router.GET(baseURL+"/soundboard/packs", wrapper.GetSoundboardPacks)
+	router.POST(baseURL+"/soundboard/packs", wrapper.PostSoundboardPack)
+	router.DELETE(baseURL+"/soundboard/packs/:packId", wrapper.DeleteSoundboardPack)
+	router.GET(baseURL+"/soundboard/packs/:packId", wrapper.GetSoundboardPack)
+	router.PUT(baseURL+"/soundboard/packs/:packId", wrapper.PutSoundboardPack)
 	router.POST(baseURL+"/soundboard/play", wrapper.PostSoundboardPlay)
+	router.GET(baseURL+"/soundboard/playback", wrapper.GetSoundboardPlayback)
+	router.PUT(baseURL+"/soundboard/playback/policy", wrapper.PutSoundboardPlaybackPolicy)
+	router.GET(baseURL+"/soundboard/quota", wrapper.GetSoundboardQuota)
+	router.GET(baseURL+"/soundboard/ranking", wrapper.GetSoundboardRanking)
+	router.POST(baseURL+"/soundboard/stop", wrapper.PostSoundboardStop)
+	router.GET(baseURL+"/soundboard/tags", wrapper.GetSoundboardTags)
+	router.POST(baseURL+"/soundboard/uploads", wrapper.PostSoundboardUpload)
+	router.POST(baseURL+"/soundboard/uploads/:uploadId/complete", wrapper.PostSoundboardUploadComplete)
+	router.DELETE(baseURL+"/soundboard/:soundId", wrapper.DeleteSoundboard)
+	router.PATCH(baseURL+"/soundboard/:soundId", wrapper.PatchSoundboard)
+	router.DELETE(baseURL+"/soundboard/:soundId/favorite", wrapper.DeleteSoundboardFavorite)
+	router.PUT(baseURL+"/soundboard/:soundId/favorite", wrapper.PutSoundboardFavorite)
+	router.GET(baseURL+"/soundboard/:soundId/preview", wrapper.GetSoundboardPreview)
+	router.POST(baseURL+"/soundboard/:soundId/report", wrapper.PostSoundboardReport)
+	router.GET(baseURL+"/soundboard/:soundId/stats", wrapper.GetSoundboardStats)
 	router.GET(baseURL+"/test", wrapper.Test)
 	router.GET(baseURL+"/token", wrapper.GetLiveKitToken)
+	router.GET(baseURL+"/users/:userId/history", wrapper.GetUserHistory)
 	router.POST(baseURL+"/webhook", wrapper.LiveKitWebhook)
 	router.GET(baseURL+"/ws", wrapper.GetWs)
 
@@ -256,56 +1029,216 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xa7VPbRv7/VzT6/V7AnBOTpn3DzM1Nc+l1uKY9joTJi7vMVdgbrMaWXGmdlmOY8UpA",
-	"IDaD4xAekrSEQLGBwwmFdigQ+GPWss2r/gs3uytZkrWyebje9e4NYxvt7vfh8/k+rUbFmJpKqwpQoC72",
-	"jop6LAFSEv3YL2lQjslpSYHkaxzoMU1OQ1lVxF4Rm1vYPMLmK2tyAqOKNWtYj181shPV/WxjvSRGxLSm",
-	"poEGZUD3kiDU5KEMtL/F4zLZR0r2+56CI2kg9oo61GRlWByLBM5cJ2caP5K/aOt0/vVpdrV6uIjRE2xM",
-	"YyOP0TI2trDxEzZOiGg739ayRBZ7X3XoCxCDZN+YpPRnhpKynghqVl86aJSztfLG6VLBXTukqkkgKWSx",
-	"HAcKlOEIzyiugH03/zYgKXE1NTjYd9Pdx9XtC1VWQPxDjm2ZMTFawGi5tmRYU4diRLyvaikJir1iXILg",
-	"CpRTgLepIqVAcMPGSrm+dmAVZoJLxjjWGVDV1F0ZJjwAoO7xu1TW74IhWZE0jh2MdWyUsPkUm48pSmys",
-	"YJTDaAOjSYxyXNOmAJTiEpTa4O0ijnftk25RSYYgRT/8vwbui73i/0VdOkRtLkS9RHDtJWmaNEK+a6qa",
-	"6ou3FblCIdB0YSYjx7mu0MCXGVkDcbH3L862LTLfC/GXfkvW4QDQ06qigzNrxvU0R8XbakaJD6mSFu+D",
-	"IBXEQkwDElQ1nhWq717WpgqNLIkSTX7wGaGTU7iWNH6glixglCf0XMkzcpDfjXVs7mJzus2Wn3FJ4ZIV",
-	"5Wv5R1blOduUkaU+VyYCew7g0ici6lBKpbmKHx7WxmfpJgyWu9hc6LtZ357GWeTdGKOt+l4Bo+8wmq3l",
-	"VqyjH2rmhPVqpyNCHHt51XQFini8wkON69MLQacFEm1B05+URgbAlxmgwyB2CM75HrImZ+pzyxg9w+gF",
-	"8TYa91LKjqsdSNUBVS56hK6bN+pLh6f572v7UxiddJ/D/E0V2tuZmcG1c0s8VYY1oOvhHCKGoKFO6GOP",
-	"Ck5g4YBSA1LqE8DJUQN3Pu0/nZipnqyQtP1qzypMYVRhC4QHYIS3XUZLnksognhzCpubzFmDA7fq29Md",
-	"7ekaoL0ZB9NJVYqH4knKxGWV5+3X2DSxuYDNbSrWNEZL2MidLu9aq2+x+QwbK9hYw+ZW13s99VKxevid",
-	"NTnR7UUYzXYjlwgzjUeb9bkdjLYwWqO5K9cxwLTYiCnnPfBstgoDXSg5GBWa3m3litdeVmHm7GThiXtH",
-	"fQCUcBkh+XdQwlvyQ/CJDFmg/uPdOxRxR9h4g83djuKwPYPCkOdk5T7FT0xVoBSjCGNllZiUH4IHMryi",
-	"A+0h0ESbGGICwrTeG40OyzCRGboaU1PRtPxAiiUyPdev9URbVnHKWldwJ7ttYrRhr8NoH6NKbSNX218h",
-	"GcooMuBi81tif7Js20mQT7HxE9FdhkkiL9SkPwspOaap5Gw5BoT7qibY+4oR8SHQdCbDtas9V3uIaGoa",
-	"KFJaFnvF61d7rl6n5QdMUD9E08SSvaPiMKBGIT6SiA4EP2K/rAzfduyi2c6k697r6XHMCVgrAcHXMJpO",
-	"SrLithzkE/haSqWp5GlVGeY4MWC7ftV2rp5JpQg5vcUC6RIq9blla3ux/vqgsTlDn4ySaK179GgB/uyx",
-	"9bJsbS9aL8vM0F0YHdP084bEEGLvSWysYnMeo81uT0Yqk7yOvqF1jlus1qaz1s63tLRYJxnMyDV7JZbm",
-	"sVG0Zuet4wX6zDE5M2v8VREjLQb+GEBa6HU2r5ROJ+UYXRn9QldbjNypHvRXkhyb16YK1uNl4Yrg1b2l",
-	"AaQtIVGKYOr9nveDdv5MhcIfSFAgT3zAdPA/0adAoClSUmCwEj7SNFVrdTZPBIwqthSOaQmUpGGdMN9B",
-	"/z0XC9FRVmuPRb39Bxcd3gIEmyu0rntE2XtyTj9+6hxFC3wpBSDQiIDtTrRLHkIbSkvRafjcZsENclDL",
-	"gIjH7526j3uXxJU/aP/CndwYN3J3xmmo0/59OA0XgQdSGn9jifMisfZirzb/tgMSB9NxCYJfLxhpgXdD",
-	"jY/8d+OwjacCNhrjk/BCwGYYYMDm7HJDigtOEU2fuRZ8ZlCRMjChavLfwS+Jf1vSMwbp1hlOJ4K0TChJ",
-	"TeAZ8RH6rU3XXux1IMvvE5IyDDyDkgE1Cf4X+HL5EVgQ8iEGXvJWP6SodeYcZyJBB42axeMoWZ1JQuYQ",
-	"D1qoHzM60K6xMQnM6MSGmVgM6Lo4FhkVAYHup0DXpWFAkf1QSspxwbOHQJ3I2/Q976aAcYDE77DI1BTS",
-	"4wL/E35pgg1tGZsbtMjNY2QQ0zrdfGNjt773tsP8k9f1WW+OGzsrTReFDReYkq2LbUMK2DAol2YxeiMw",
-	"Q5xp7NwCqvNkeC7efj6aOjXL1tRk/fk4e9IqbGEj+/PR9K8uKJbColJoUNSbPX5osXrzBkZb1ZNvrO1F",
-	"biOPzZdsEtKmEREEwSqM08ZnjTQ+NF0Sx9oNfURojiIigj16FDDKE1OjY3pm21LYP4X8JXubkHknL2F7",
-	"bNTSzpCGjkIp1PFu++nwk9OfBjzAphhsFnWW3iUiplWd4/JrH9RLxXo5Zx2sE0Qtb2M0HpxwYaMopDJJ",
-	"KJNwECW55QqpkQSMSqdZREeDCziLbl/vqh4Ua7MvnHneP2jG3u8mEOCM03yooY3yBp1QvKbKTpEGOptv",
-	"wgVnZ0jPjMYxmqTCrTpXRdPYKHrlIE21kcdG0Qdo0lD767YiHZ2M+6RgrTsbuFO3WGgPo5IDX4GserRp",
-	"5Z7V55bpOJMpTssU34iLnPzuhPqpbZ3Qr+oeUIvt8jLHBReBs38eOuYfdV08n15UjHa8asWMyyVuNPZh",
-	"FuVrs4Xq8QsnlNFpCtrEaLy+Pd2GjgGcunlzk95GVi5D2TYUCx59hmAeTSclihM+v29fr+4/phHY4M6u",
-	"iUTvvrcKM5QTM4MDt7BR9EJbEJxBPTaK1cMc45F9/+XtiNjWRrH2A2pltrlJyG2UnbCwSNXMksJjc6ZR",
-	"PvIONDHKkYbKvfjzZRkqT2Pje+vdU+/hngtwe2bmmaKXqO55jN6y+yFr9k3DfEdDyTmI2U/MfPGi+WyU",
-	"8N57/cd46bt14rDSgQOqODc6Wx1p+YTSZIU1cNX9mcaPu4KqCd67DlJ67eQoVP005dZUNnBc/oWWVVRY",
-	"Jqm1tlN7tnAeGgfiD73qa2JdsO8UvI11iaEslLrQvoTiDsXvgNCaxq/Xnz4J1IaTjF71uTIbWzevQbi1",
-	"nvcGm0clemNAyj+EUcVW08fTkOIvnOxbwg0gaYB43b8NKbIRPe4pZWUJZxHzr3WcJ+s8Nzf+pY2TuZbj",
-	"q8e5XuHzjz+6IzD9f0d65d+Okl567HN+RWnvTi+VOvXnPuuE9OpfZgC99PM065dq1SMXeVumi45LnmFj",
-	"g/57SrgvJXXQHSKj+2qOV7DW120uPfFtF3/8d3rtejc+GL1Q+HV0a+1IExodvgJDCVV9EJ7Q7V1ZVdoo",
-	"bzdJfJetZPnTenKE0a716MAufowcjXGsHvmGpn5WYZfcrs0zZ7EmvrMqa9g8PM1m6SbNiLmGzSV7LdFl",
-	"AaMn1uw8Nh77SubJCRIHH63XC5PBKpvDQlspW4Uzp1nbWL8JIo1rM8HeX/CpQQr35/QXT9EVHCRcYOzq",
-	"eMQo1rZXrf19Gl0Xqicrofh050cjpDb+V8DPUdo+ORR34Recd8HQbTX2AEDaZT3HaJFUWZ7gF7jKo2gx",
-	"ijQVvKa17Qk1MMsvTcy4+aUjOG8xYSnqCb4bm29ptMszpEYdnLKXvEJAilbZ+5GkxqCFpDVRpgVjxak9",
-	"7AGj0MV7z61bCGYcfka5G7hzvcaLNLe/kmEsISvDQr+mQjWmJnWhq2nt0+zz6snK6XzOKuW6L4UEjwM5",
-	"ZuZDYqz5awiZPuzvc/OHs3Ds3tg/AwAA///0NMoUICwAAA==",
+	"H4sIAAAAAAAC/+y9a3MT17I3/lVW6fxfyPUXsbkkdQ5Vu54iQHZ8QsDbhsM5tZPCY2mwdZA1imZE4k25",
+	"SjOywfgSOwbjcAnmYrDBsQThEoIBf5jxSNar8xWe6nWZWTOz5iJfgDxnv9k7yDNr1urVq7tX96+7LyTS",
+	"ymBByct5TU0cvJAoSEVpUNbkIv5XLjuY1brgJ/hXRlbTxWxByyr5xMGENX3Nejdv6tdNY2Jj7WV97kmy",
+	"fqtsLS7t7ehoS6QSWXjou5JcHEqkEnlpUE4cJOMlUgk1PSAPSmTMs1IppyUO7utIJQalH7KDpcHEwb0d",
+	"8K9snv4rldCGCvB+Nq/J/XIxMTycSihnz6pyxOSM2ea1CWtpgs7y7VTjbTVgamQ48dz4yXSIJ5M/Uirk",
+	"smlJk4NmNDNp6j9bF0et6h+mXjWNF6bx0Kw8MyuXTX2yPn/P1Fca19eak7+Z+pxpTJr6Q1MfMY0J685z",
+	"a2bM1Kv1y09NfQQlG7f0xtyD+nXD1GtI/iGratl8fxDFuYm51vb/FeWziYOJf2l3tr+d/FVt71FK+Uyf",
+	"IhUzzqKUXDY9lBiGtdLHYJTDUi7XI6sqXqJ3xXutm7dNvYr+JuVyKGlWVszKG7Nyx9Qn2abM40VOmMbl",
+	"xgtj4/VFsk2m/s7Ul2BBhaJSkItaVsYfk/MZOXNI83+IvFy/blhjayjZLN/YfPR049WqqVcZ7WqNkbum",
+	"Pg9jnlWKg5KWOJjISJq8R8sOygl7R1WtmM33J4ZTiax6Wu7L5qWi/2uwbcaSWbliVsbxiux1TZj6I1O/",
+	"aOoTzpB9ipKTpTyMWZCKWjadLUj0qGU1eVCN2osu56UeLZvXEsP20FKxKA3hgWXpXJdncPecycG0ZiaB",
+	"SNOGNX5nszxan3uS8DNzKlFUlMHOjGDhbKVJs6KblfuYeafMykqbqVc7j/C0LZWyGRFZVcIrwtGNNbNS",
+	"MY3fzcoSjBx7SE0qamK+IGxG+CLmzsPy5e9K2aKcSRz8Ozdfmyz8F3k+EWyDZ8u/tb+m9P23nMZbeXhA",
+	"yuflnHPiuqT0ObVb/q4kq3hF7iNQkNLnOjNu7vFRxM0enhWxEUSTOdpflFU1ZONNvdqc/K1xdc2srDVH",
+	"pzbW7/oPKR6DbHD4XsU7iKomaSXBnI5lz8tfZTUEAobMuwc/iZIb7ybQ0b92H+3pOXPo8MnO/zjaFrnN",
+	"9qTt74nI05mPps8rEDqL15qVZSLX4J+jD6zxmyjZffLrLtSOTn/Z2YUaq5f98i2bD6MdkeeCP3A81pmR",
+	"81pWG/JPkY6NTH3SPv+mvkxEcGP6HSgcYwLrlCrKsmG2uB+deXtD5E9c23L8SNeJzuMnz3Sd+vxYZ8+X",
+	"ncf/Gr05DllCd4c7eSE7ZF0chS1hJNh4Vd58uOTbCUnTitm+kkb/lclkYRwp1+V6ykcb7zcfwjeNl/C/",
+	"+krz2r1m+f7G2s+m/hMwBmj4BdNYMY0/TGMdpvb0dr28lBAsLS3lu0p9uaw6INB9119vLpfry4+a12eE",
+	"WicbyBP8BDuPnOmW8hll8NSpziPOOM7a/lvJ5sVSlhATc9JCS7LW4Wn3gJt3lxuLr62ZKdErWhEEZNAs",
+	"MFdPWqO/Nq9NEAvArIyZlUdYs9QSqXg69yR8QyhJw7iOqOfQiYFFBOKgusjbPs1y2br0mrd9TL26ufxz",
+	"c/I3v4zY9d3E02txH3PyWU2ogPHKdtQw+3MZJyVVLorG04rS3zBz0j0T7VIcU4SOz51ybm9FUrJbUQa/",
+	"zKqaUhzqltWCkldlv5WRl3/QTpDrkF/evLxh6lOmMctf/5o3boLcJ1colKTP6JN4c0cEe+03Ouny4hvG",
+	"/NUjyuixBw8iyemsNuC1oEV2jRyu/ZesiTm4tLGrm8dYQkkySltcQUQNMoHJn83HmU+INZLNtzaVznzw",
+	"XHbhrjQoa1JG0qTQ5bWuU4VW05YuYyJCRMqmmHLDw732aY+8TQArq8eyqsaf7VgrEx4CwRIFroGjxaJS",
+	"FBwY9rNfzsIYnZlIF0l9/p61+rPHVxJDUpIvi+gT7NjYNXeNWTZsHw0y9ZpwUaYxu7l+FWyCsp5W8mdz",
+	"2TRY0jV0oOPfkKkv2y8hSjvE3rhulo1v8olUQs6Dc+rvCfatRCrBRkp866MYT4pOTR70b1+6KEsB12qy",
+	"7Po8uKBi6208niJUhhtvb9XHZrCFFK4RU4lMqSjBW18L5F5z4Zl1/wlcUed+N/U58DgtmJXHjaXZNrNs",
+	"kB+BbNiwInrLujxl6iumcQ+Ue2XerKzic3qZbemCe9trqINfbzavfXZAqMzOSueVYlaTRaLgsWnUTGMZ",
+	"RFRljFjN9qKBAPp4/cm8qa9Yow9MYzxSUA5kMxk5LzK9blh3nuLVjZnGePOX28Sshl/0x6Z+32ZY/hMo",
+	"SW5E+KmaNbMC9ihQ4jE8C7NbMss6jP3qFSj0e683H09Z70ZN/a6pV9m7S6Z+x9R/QlqxJCP6QWOiTTj/",
+	"QGEApIfNmAE74vrrzbuTlFgRwoAOeVx4teAoPVmfvGRVb5BBCW0aV5c9BzPgEqIqpWJaju9G7SHPk+vz",
+	"YEF4CNbW6iPT+PNEdz0zK/OdRxqrl82y7mbDlcbzGVN/YOrT9Ym71psX9cqodeep8LIk9atiynKj+dTn",
+	"umk84c2CCAdTKvG9dF6GUyHwOz67Z729h8/1vFn5FRsCD2AHygY9rsYs2tfRAW4Da/J189oV4PyxizAh",
+	"fQFWrv+Cp3Ub/6/9TNWsXMUbWUPJDrN8ay8ccUS/tq0zvgRs/ej1N3meAvaRP5tTJC3BByqEkYF8abCP",
+	"SIJws5TyPs+yDovwMtMl+TiC0y3mJI4tEVKcCA/XhVsyGDz6I9RUAJ9mhIZ5r0qEf7E1Txv4TsNsGqE5",
+	"/hPxPgBn2wLVmPDJsW05dFnAx706NwvZc4zPGrBzgc7ouHQczOaPyfl+bYCP5IlJt0Ua0KXbI0UsKicN",
+	"BS4KjG2x7rAuTjWuLoDY0G+C2NBHeLueelqinQxh+s7hB5Q88jm1s16Nmfp6tJvUESb2EuKQIcgH4HJG",
+	"i46aLT+Zuxfh2w1KmsYqFfL6yub6W+wbpI+6PQEjbcKLWU4a6rMPmngL8Hcs/VZ9Fczwjbfrpn6xLdxb",
+	"HU+WddGPE6c1GaEoS4NfyYIrAjj0ycWec2hVyQvonCx0n5eKuZYISs3EymPCaKe6jzVWL0fyAkfDUJ+5",
+	"f+GCUx5icrtVp8Dwjmcuh/Aa23GyyY7RGsJ0S9gedR4Fg8EY4V8g9MaOyPic6PtzkYgQOfP5UPC8veZ9",
+	"iMUaplmOB6mkkOgnnYIDgXAc9JH04unSapxuayctlIOFdhJPf/v7LkMpHsvDDAQCEGYAa2vdIGIDC0P1",
+	"trsh3lh/K8klG3yRAnBHSQ7Zas9W8ucTJa3RseadVRpzuHMR7BFbpWGTJLYzMN5aeVXauseL7jIlWMre",
+	"D0aE+JtbougR4iDxjJOJ8I4Q2gcH5Le3n8HWRuu2RSQNwynGzyyAwWhEze0KM3WDRY5X6teeYDEz4nFu",
+	"8dJQ4B9TzsvFnFQAj1fz0pSpr5r6Q/pKWce7BH8KmUPjhWEa03b4DJ+CRbOsF+VCTkqHv23MghVh6M43",
+	"SSDO41ejc7TZL5Wgg0fwz99Kiib5+aZviAaX47KNokmnVKlftlXClt/WFE3Kfb7174vsTjWRoktyjR/F",
+	"cYomBXiP00pGbnF6eKTD8N5wKsT7TCCI/qDzy1FTHzP1hY1X483rMyjpYhK9Wp97gh0xBubiaVOvgWvK",
+	"WDQrY/W5JzENHVtlBTlVYXBjDPvilulxGbsonAsXQkV7Y36+pAZ/mVwzYNixi3FGE/rcU2TfUjbOE3+R",
+	"X3dcjjhM958dPwh0nsGsdiatlPLaGfmHtCxnZBYEPYO5j/8Vs6H35xhnlXC5jyED2IZwi1k2GiN3rbGX",
+	"8N98cBsjNVvbnFYpX1Jd6xKRtVvKn6OmjEd7ycWskmnBj6lJmtpFXgI5lM2nBaqrefPi5jJIeIq/w4EC",
+	"lCQfAwQSAkCon04tGJu2AGzRXKGUCHJZaUoBh9AibzpEU1g3b+OjOGkt3uDdAa2bUfBVMFnUaHcPoX7K",
+	"kbv2pGMxgTjaU8DfFsBGF5rXrlDMFLdkcQR/O5cY5hT3/a2Uz35XkoE2cjF8hitBdy7xhFv0xRIKeecT",
+	"RXJNPgZyo+UQaVHWikOHzmpyUWiLmfozttgljMaYYFqDxlpsGFFjabY+9wQlu2HAPXhEZFZ+xr7wsqkv",
+	"0zBnebEtvoznJhexfrmgFLWWYossbIXvqq1FGIuypCohkbBqY+Zi4+pTlGSBnzkW2ZrAdybOO/XodZv4",
+	"C7AeMaKHn3jUhd9rqTvD2quI76IlRA72Z8alCglAtZHgBvPXftrRIZp9jOkE+RWD4pUeiz5mvFIYUlQK",
+	"cp5MI1qQ1289tmrvrPVboK4YSYjAaFUR85+1ozBx9k7O4F8ENoekauyRQ5oY1f9uEnAwdObUgdjiwQml",
+	"13YIxE5MrGFR0r49Nu9c3IIrgixBpNZVRt5WAloi/ZBIeXbZs0POgiP23Vb3wZo4LC9jq/ghPHb41Hrs",
+	"0LZHZpNEhYCpgdmf9u/yYGE/akeF9CBqR+eVYl9WRe1IKZTg/87mpDRqR5KUbhXoYY3fsX5/gOO6W3Y5",
+	"q9JgISd3U8+f4O8D0r5PPxO5ZeZM4y6+960AHqfny0N79n36GUru/axZ/o3eBP0GTvYfsn3jblWu0Knw",
+	"ozCCu9aRcvaoBS8oNunFogcbOeGCx+VAZIIHJUVWGQeSwmiSLRv/27y0BJi62zJvNaUQaKG6rwpVdlWg",
+	"FkLrYu6UKhcDrgphF5iAaezGjSXSaN8hQ92+Coltc44arh2KcR66bBZj/oeMNAS4C1k+l0glBpW8NpBI",
+	"JaRcLsKj0ANTCPQdh0Q6qU/SmOBCnuBlwGeFpQos805ia3QZH6+qHQywxwjC0e+25zmc0ielfpHfryTM",
+	"38GgJFOfxGClEWGAI5CBMEhGcG4904aHUnQC4TM/VQABFbivIfgzoYOcgMyiUBJBwDFuSB44Fh8Oxg0A",
+	"RHbuR/M2GszUlxpvq6Y+VZ++CV5S7B5nyd5WecIs69bMyGcH6tcuWavzG2sPrIujba1BWkLJnVOkTCC5",
+	"pVImq4hYxo/4wrkKBHfG6fHkvo7G0iyZtVk2iNHyvXQebJX+fsdUGTwgwR/kvkGXssLQd2GwX85nhPbL",
+	"2CXTGMcpP5ChDsELfcxOT/fgVnGA92sVERgnaN2ywW2QS5MytQx3/0R4/jwgxjJyp9i+onHCFXzT+gl2",
+	"G6i1RKloLJIMGKHpFZYmT755oqR9LbwQrFhP3gV/9B4+LmNb+25MROjmpceNqxgzqy/SOcTAgpINitxm",
+	"CMEbetA2B20pH7NNRFYh4MUZORb82r+NdcqCLu4Zu4yAIJupJLv8uXGA/A7mnQlz4WU+xkXg3WQQV8S5",
+	"CAThsdzg/gUvPIsXINbMlNDsD8bF8jOPg5FtrF5uXrvSvHH1fzlONsPVshAjYuMw+aliLlCbpJW8Jue1",
+	"k3iMaJ1C4t4MQj2BDpPX98D7KGm9vWe9IbFC17Vx49Xq5usViFSPLeJjT+w5gNej89mMrLSDlkFYFjwy",
+	"DcOargkZ7Gw2JweItQDt52HbFqYIuSkb67/gg+wdCbgYQ9Gt1RnTMNi7ILTqT18TsUeWZ00bm+UKQawH",
+	"XpUjrt3Ah4um8Rol7RCs8LCH678d1AlbNd0CYP3kI+HGnOiE+GIWNnukXGxNiRz7pARpBPmHQrYoqyIH",
+	"gamD8CFgMxaLEMkWY9aqTuLCLxhVa0xY66ObD3UbVhLbKVDC0xUT2ftVDB7FnyUCmCVVtQUPfKqYi2LK",
+	"WdR16iQiZ6Px9jdrZgpLhSl0qvtY5P7Z0+c/mOIoHLFZtk+gFX+ik5wcMTmVxiaC/YcnlXNyPphTNPhz",
+	"YM0Iknbz76dPYqQrKCuz8iySZGRM4WSKFMgaVSGANz48dQLc8x8sCTEb2NbAqroyhpFFEYEJNSscxPlw",
+	"GEA0RsIRXjjLNfJKiCyNbRbTcjDVHO9v6CQby9Xm3dscOmowmy4qhQElj2WNNCgXJfhauijL+TPqgFSU",
+	"Pf88w8zTUv5cXvk+L/SjAFt/nMnqWnZnazh5N4uM79+mYYyWPqswS0VK41Xb1dXOy+ey2h5VLp6XiwmK",
+	"Nk8MaFpBPdje3p/VBkp9n6SVwfZC9pyUHih17N/b0e55S1DHxDmVLBEP7BL6Hskyrz+aqL+6S246zD64",
+	"jdV0DeQuzeW7Yhp/AEGzWk6mVRAQZh34djYto7NKEdFxE6nEebmo0lJin3R80sFiVVIhmziY2P9Jxyf7",
+	"cVK0NoB3oF3KDGbz7aotFdu56FO/rMWLbVHF47mpQNJjSEQSUGFVSPNnATLqXmW/12+VN9d/8kTp6F/1",
+	"KsHi0rxK8jq9OS1bo09xFgW5J70j+b4IIW4ynkuVgf569CTiqXCBqrfh9kJRPp+Vv0emvrT56OGm/pzC",
+	"B9jA2JiHs4XtalANib/K2iEgqzfMpuJYFzmTmMD7Ojo48xn+UyoQWz2r5Nv/m8agnfpzWwrusUip//z4",
+	"uJac+PrYjDW+AM8f6NgrAP09ntpcfgPZuCDc3pDn9gvkSPVuY+Yi1Atae2AtXoPnPu3o8D/n5Kw6Q4K4",
+	"KA0OgovoYMLHAi73pTidlkkvlLSngfmG5ARhl97f2dFPfAvfCzwJHC9IhUJROS97Sz7+PTwtiRUZhEPn",
+	"1BjkUo9sIQY+Ab7eoFeZf5tKFBRV20qA3pglvm5rbrpJhdE8xUuGHdNJU39CsDPsAe5I1S+vbz6eopDL",
+	"d5POx/Ra/eofBB6MIydjYaelS1HFx+UQpbbv0BwQSCU8ld3j3AMdB6KoPmmt/mzdWrbdUbvD8MYsWerO",
+	"MnZRHvwo+frI0WNHTx4VS2ZkI7EcHJcn69uYZalgv2Jt/Grj1ThWLvwdZISRmyoO6/J48/oiz+itMG03",
+	"oWQcniUf+l/Bs2SpLfAsi8q3X6D/BVzqcMGeAitoRm0UnwIW14h8zwq4S1wSLYbeFe2cu0TXTm6eb+il",
+	"+q3L1vgfMC6/kXZWNlOvwv1LhQsRbMDiU+j6KPEPu6OobWIRY7NEqJCJir+C0ClpMejMEYMjAKJFQZE3",
+	"zsekRuMRUac07GyHmj0nkOnVOZbhzmNSx0NlUCmMy7Gr9nMlM9QSg4cWDwstuzo8POzdjeGP9LQtXq7f",
+	"fM6ftg5RjsJUffU+3nNX8RmU9Jw6mydwedKWxPjHf8gxpQKFNLhK1fYL5+Sh4cDrIvF+W6NjcEskzkRj",
+	"BZ/zS6ZxH3tsa6gdmZW7ZuWeWXmMkj0nT3Qf+uvRM58fOvzV0eNH/pJT0lIOIsnyoFIcauNjZqDwkTDL",
+	"AErAgIpZwFboCl8Lx+tnhAPs8UqyBHXX9bFnPxSQInnzThoR/KpX/WOyJ+kUyjrx7uLNIWT+hQaFIT5b",
+	"w6WBVtCBjgOIi/GFXjG/yObkRISs9cQbcALzG7FQJbn38W22lPdbhJRVwlQAZoOEsFPHO/8T1a8bzWtX",
+	"goqtU39tPFkeDP8LmA7ZmYBPq9n+vKSVinKr1moLYk1Ja7K2hxQ4cIu3SDDE8HAqZD9DZAi/6V4B4knI",
+	"oydgksq7yhrZORyHn/RIEBbJIhA014kRHapwE6H0QYRF14ket6OHRA6IFm9NSFjT86b+kzV9jVfTH5uc",
+	"6Cr9U058UDkRxwzbpoiIMroOBB20iAtHuGRIJQ7s3R8z6IxHwTmfxizLGWb4FbeIiXHu/LHJQANlUNaK",
+	"2XSwL/u03NejpM/JuAJ7/ccHjZc3wF9ljGC/sg42gFPyY7L++h6BY9eniCm/gAVRBdc5Bu8CvEvRB7Oo",
+	"q6gMytqAXCIlaSoXYShsQjL0wJLfRe3cjZzwAUTIynpj9ol1rwIG2dwT1Pv10ZPdnYd7zpw88dXR471Q",
+	"LnBzedXBH/JvG7PoUEkbUIrZf2BuczLZULL3c1kqykV0AccFh3tBXi7RkAQscBrXJJwjFQfEVsjXlMSR",
+	"OlGTf9DaCzkp6zHy5R8wGh/Me/Tl0WNd6DsplzvzvXomreTzchq+paLjGPOClLOI/ipnkLN56VwWrgSf",
+	"fJP/F3Tyv7qOCofol0r98jd50Z/2f5MXnyw3t5z4Kr5577G879J6RGBV/+HcnjGTFmiWsdCT0ZXN9/ew",
+	"yNS2KVxQ8v1xVtql5Pu9a7CvF3AWqgCLX/2Z+N7JMooMQi88aY3pd9atZWaKwJU4ifl+AUA0gFOs4RNy",
+	"36xcM/XHbZwjYJkCtfQq9+NK/XLZenqbj0U7HVowToaLX0YZ0gzuvq27alSdYHeFYQHNiTBGexC/dk/P",
+	"BdyFgXBOgMw+rmjoCxbuEd4TO/OaXMxLOUTYCpE0Wy/DCqbgD64Eyl3MC+2OQhOyRG/792ov58Rl32yM",
+	"v6yPYuQTSPzr1PQxZlEvZnD5PEDFyNjw+lKzrPMWGCfTjVnrzQvTGMfRartYJzaCgd8eYeV0j3zAmvkJ",
+	"ntGXyCgIIWtmxDUDvYZ6s5leKAm0THQF2LijT5vlGyTyYerzjbnH1vTvbWZlrRfPsxclT6tHz1MQEf4d",
+	"CnQ7P+PWH//ec+J4G0y3PqnjKBZTCPxDvar8XS+ub+wlnD7NpnMVR3RccyHFZ/ciAizDJa3eQWnbMoGX",
+	"LZj6M1Nf2WvqN0x90bo3z3uwEBtisnn/pmlcMfU7jkHbe0xStT14fns6j/RiS5Yg+nBNGDYlgSJBCJG/",
+	"Nm4+x2Gj2uazN5uPV1nvCccHiHrVvFRQBxQNj+/ZafsrBE8YMiOS90F9Baw+7sb6L5jejghp3Hy+uf6T",
+	"l/NoEXiAim6sPWhehww663co+UGK9DTLZBBnVvgXEiR24ZLDl4MQInRgUACbDjXUi09UL2pHvf05pU/K",
+	"Yc7n8M9RJkQtyAgIUfjOMw4hnSOEejGlCfill79ogWcfUZ9ZhfhMH2EV+MY01lGvlE7LqnoGmxy99gXo",
+	"vuf7KEmsvxo19MB4+p16deCfT0AJVCeAgMYILF4f8V2h7L2uUhVjrGELjLwOYFyrvAgTAGvsJsuzA1K2",
+	"hSuLHiLZYji6PRuBkkHbICQxR2EvgYNuSTx1Ey3dywKYDzvioZrOCj4Pt63J1wSk30awdjlcdOaslFNl",
+	"8YyKVL0K/LqRhT99ScnaEDZk4MWEfwkeTH1ZB6c7txSPRuFXHEBOctpEhLQhav5pOJmetosA10pwixVo",
+	"GYWSvBCz93RAljJy0ZmFS7Altueb8ilRtx0T7YJy0dBVaDPQPD6VlyjbyztkmAQaCeTlPT2gNjHFVOKK",
+	"sn01ExFWywWS+T3cPkBAdMFAKD7jK6SRzhI4lYxpEhclfR3xvD3NcZZJPpjvd9Z3i2QdGLN8wj9msfhG",
+	"LoUFRjuDfLmMAj+QnSC/9QhcSmw2O5Nr51qpxniab246/O0uG/RejGWYSU+aOFlPH9RXn3O3v4DQ0+dS",
+	"BtlRtfd2oojdKJhmzOPCN58Rnhf+S2AOAFL/EjEKWrysfc0+9bEw8nZ5zQOc3t02PsNCgGz0ZTRw097f",
+	"ZTR4CkE4BC090ConQmz42pMITqTZzB8tM24t+v/R8WHITiViog22xNiEBz4+CR0405hC2tvAK+qAeDp/",
+	"guOPa51pIwQiDgsAR/plDtDfrcQJSX3852X7/c/8LB9A4Ou8ixOiFax/zU5AbmwP8QV4u5TTyIa4muQC",
+	"AVW5uNcpG34woZbwbRPbZrjQ3deySiqBJjrz56VcNoO4MRDeRNGg+/hBZXIGvh1OBUome5LcFgiqBNqz",
+	"8WNlqKPeTl2wr9abj541nj+JaH4nLLpfe7f59K69RQEpSQGNgCkhkSsyz0oHxiglF1UZIkTDC/ntf96M",
+	"NSvL1tjFxo0R8iR0YTHK//Pm8kcnFJeCpFKgUHTC/4HG6pHPIUOZ4h1EmNbKLZrLzKINkfcy5lK+h6+s",
+	"F4n2hH2mgOYUshNSU4jmo6YQqSWSQtvpgQYgEKcrmTMd9w3W5abDVRQgyll/XYa/6tddq8etyvwABIQQ",
+	"vrERX7YzuJMfxvLG8KmD5AL0n3uOyz9oew6XiqriKrO5Uv/1LlZ5N0h0Ff7bwLtuvCWRYNrMj3VYQzSf",
+	"35hFaTqavsJN46Evs82ZuWjanpoSyxtrL2MBs9xNoqL0XEAPJsCjvLhtGuOb796YRjnAPeXqVBTf1efJ",
+	"xgYf8OKtxvN7RM2QCMe1S+y3ZSIKIPhz6XmQ3/G7VmfASuREL5LUGGphcJ8bsPXDw/P39YB5ce3DWvEO",
+	"2gDLuJsMQOMWqet2Qjm46jYxmtkLSnatHmf6CN6huZ9+WCmcc3tV+jtykL7JB6yOB3O35DoSQegJA2+s",
+	"vazPPUmyWksd4M1nkqfmFTiB0oIfEWjARIRhP2/YpcVcQs6WWKzWyE0cXVtxiZCybn+VUlsoMNHejg60",
+	"sfaSxOfIhgSSkhWQd8jolBLp6Aiv7SCgKK4VyYtfN+mC9pP9cacAlK255gI69Akd2Q7Le8LrwAkEG5Wi",
+	"vng8T/fy/XfLcH3l8KA3axpv6xyN+SQPn+juwT5rX7p9WyhVh2NA2d2huKR1f9y5r7OJtopj3yIaXWRM",
+	"kaIGpIBPHKxBUNrY3k8bS7ON5QnrNSRcNBZWwSzzlTCDoz9YymlZsOzbQeLsAXcHxRRQAHhZ79mf3Hg9",
+	"C8kd7lyytoDKPy5zyA80AAFRnrRNPbM8hYP2I2CqweTus5bfl0mI2J6Hbd24bFPgDrcLZhaDWEdcsyBQ",
+	"G5Idh7fFwnnMrs7Ilx5bE3ONqwu4qxpZuCdXDpsK0FQM75NnnYElkAjhUdJbIi5JitumcG3bNnfFuKQk",
+	"pZ0/4Lo+SfyvFOLqzrVhOERADR4MdyBIO2OWr8dz4F/PffkPhAHEP2LWhLfQ6UP/AQoAbg7TN8mDCO/c",
+	"Q8ygUwS3VZ+umPoYSkJxruoNtGfvZ+jYqS962iB2c3mK5MA2Ru42F54RxjUNkktIii/ClLh9Y4CB0Yov",
+	"J9Fo3rjv3mSH1Ky4XTvCpfJ4o/W6jXTAiOlnHEvSbXDQDHq1URuxbv5m6x9X6TU2XbsDdH3xFhx8rugS",
+	"UAj/ecS3A2XcPJ+Oy2qCubLmWSE92HZW3068kii6kszjFXfhu1laEI/n0Mqv+AD/gkGRf9Bmzb5iaOSj",
+	"TrEvmp11naJlDJ2vDwZvjU5ZY/PCq5BgY7nix4Df4Shev/YHStKtTZGdTSFGpZRDpDYU3kndbcixVgW2",
+	"aVLWkZK3+7hjjn93haAvQgvOXUdJu4zYQdyjug0RYGpkt3W3nHCMbgpWErTpqax5iGbNjEFzFAdOvExi",
+	"+xtvr/gp7HsYNprCj+nFcxwlvQUfvoM2NnCOEQUoOz6hGjqwF0TXgX37EMMjPsJ87oE0L+AzAzwDBINq",
+	"0zFB+orKXRr9F8aosKezoXzsM8ilKVB5WzGv3AVGdyH7r7VphNl5vvpz4al/nvyZ+vTMxrub/lrc0FMU",
+	"D/Fvu7Ase0eZ88u3LE+5SE9WaYiEQEmOYf6SVvJnc9m05tyX2zhs/w6vimteFpHcFCd1wNdajImWylps",
+	"2eA7xEAfdnKJz5PS49MoRqkBlohW7ZvkbS34JwEwU418iVyhUfIEqep/6NDhNgLZs5V2mVRSwtkPV6g5",
+	"6N5GPKt9+z7ALnFuE1GLtZgUDbg2+Kvd2a76x0TsbudqEXIVaCGthFMbkXUI/pwFCHbiTuc4l7ZzlfPW",
+	"8wA1uozNsAU+U5l1g3b0LDeBms9HRWyPFebWWqHWaVkXe7poeSuRp4ulJoS60jxZ/rFNgS7i+duNZH5x",
+	"u/pYanzvLk1CxJhkX3csV5/bmg/h5vDya2wJ036BVJsYJlPIyZqw3qAzvlPEpqyL+JamX1qT10zD2Fy6",
+	"76l4Yw9FAABulq65K+TgzICygdivrLkaXBJrLjHgRDomuEIwd/i8C8GROIKXKzgU77W+jjP5ylrsWjuc",
+	"CNm5Mg5BtRrwagNkazztlHgvFnvQUY9ZBOd9knTLNW64GI8Yt1JgbdpbK5IlrlfjHDFrZgrc9pW1zce/",
+	"1n/+EW7Sbv0prFTDMCY7fXK7Sh+9LnufDE6gWh+DLvtzSrgIqBuvM3PSEGk0LzIpe/bjMnArxKoTXAbc",
+	"dRpOdR+Dg8M5xhFCnfn+oqyqOG2Hxmio65KHRrJOBPUXus8P5tpdDvZQpfvlyQA3LvPgAB7CgOez+eg3",
+	"6+0V/uMEieSu1mzf3JZs/AVtcTVd26y8Zf2zg911lTXuE9SI9jbJog2dbU+bayWw60/xUsfA2WZ7zjhf",
+	"2z7sT0Su5qsiVyJxcUQ22fe2ztdr3BKqTn4/OIYf47yLN8wrSmMoKEn72B9Enk77qB3hxvYHkbCZPmpH",
+	"tN/9QRS/lX4bWR7lMJwC575mM0f1ZOPFZFP/kTmnwR+SJS+dkfMZOYPTRk/LfQOKcg65C5aAb50EfGCy",
+	"1HCb88CD6BqTDo1w1eLN9bfAWPZlHuF4/9PN5TG/V9WpMAnINzXlq7NbJA2WifuDVWtiHTZavCzlpKHd",
+	"VzA5aejDKxg8iWCPp93MjPQSp3AXj7fM2dQVuqP4sRCvqCt4vPFqavPlM9sHug29wzeOCBNgtlbZYl1L",
+	"6qj1vubU99h4Va7/UiN+td3w6Xo6WwdunFeUcgEGNx6BE5E71PG6ReyBgx4ZDgZzYolEjAtr8Wl9br4V",
+	"T57Pdf9qzNTXbcWKaDcEHs6/RNYc107oo30PonKWlgIleFnnuIiI/vvieFxZF+ubmBXf3BKgjxjUWwLR",
+	"C1J0ac+TD5cEFV/2wcqh5aQc7xrZgpW95QIvvEUhKkc4/rL+TI/OqRPwZntByWXTxJwtRbCotx/4pSlW",
+	"zXsB10hgNpAob4MVEOLNo8nGCwM3mrluCwvmH2V1MgLujjHtUNcNErlyHS5PUbM35tnCwe0Qa5DAmTk7",
+	"bZkCtV2tdt/FvMzSzekie7PrVgf+zEdhd4Sfve1UVn3fhkTICRbL6NhZBQRMEKJUwtHIkyxsO+/vdkDg",
+	"ETz4i52paMAEihsVxa07PM3xGIAioLsG9ynPdYXeEv1TdrA3lTWnO3lljXTZIPE68UT1Jb6rAEo6Hxd1",
+	"5wvpSUiEBnF24MjrbfI7SgKbn83mcnvwru6BVG85s6dvSJMxAmeJ/wwRjIjvsO6e9zIPM6JIsypHsaXN",
+	"hxONR1NgnrN6MG0xjAAcp30/PlvyqR3sGLIDniG9SnaWHAlXTB8jFJuXpk192Y5Gx9e89E4a0gHHbtRe",
+	"dVvsk6SFDVEsAX07WYTF/Vf84rS7xzeTSIax8fYFKVDTopnYTVcSYSU2b17ExwqYmSwOJQmyHVsNNQQt",
+	"vIMSP+yG4q3ylKvFfIu1IN6PrcnItwNWpuvuvJ0DMMaxibghDn7xGVZizyDHphXWVzWlEOw4dXqvByZ4",
+	"kXA4myDLbMAOLs6RRM+O/ZJj4mHUroPvnMKIPHdO1gQxaJ2349zRWPsb29fmfNOwK/W73YvkSZLRvk3D",
+	"lvnSCHraMWH1Bd7NJ/TDRbq9oFv+rhugfEv+rdactfRb9dV7fxLjMMDLRN3sxLqgW+r3NQVmqkY7gY2J",
+	"OMeUPBBUcf/tOis3ZN+T1okggG60az8zOLr/CuXSXy3qmpMwp/eLbjop9X8gcJPTvfiygNDxC2X6C5EH",
+	"S98A1Jo97dnGi0ncdZemm3nSVcB5H9TfmAHbXdWXoTMrLVHOVUaPSG2hqUyk1pXdlRXR3kzeiw9fwXDB",
+	"3QaaLycpTt0hnWPnWeKvXrXeTQbWd2+/wLrFDmOeAkgJfMP66Q0ubLnkjZM7VzBnxXZnWWS352WdeF2q",
+	"SUTnGnb+BfcJd10Y4M4UFJVxkQx6EiNf9og9e5pFQWzgIMw74qGafKaAZ/MpW/Fg+NbB7QSuvev6ytey",
+	"/IMB2/wtoQUSitWgp5Re2TpGgHb9c2kzmgVV1n0IAqdddssIgn/iw9348P/3kNit6ENjtnHzef3HB1vQ",
+	"OW4d04KmFIn0yA6Gcfp/2Ncqfw90MZiLa0S+I708Y2oJzoVUWaOHvLJG0AEo6c6TNGY9qZ8sn29e1BVF",
+	"0FrRSYxkqYGeTDPyJ6HeDPXAcQljeg0xUnIzsG9RbMorLIw4H6hrw7UpjhNYrx962ujjssHL8LqTq8jA",
+	"rQiR2Wwh34a75opa+XNA3aDMPBSemreVPLxtWwiGEbmcHTAUDrNzvUM5cbvuMurU5EFxVS/sqmwp8SxY",
+	"EDi6HRqV0MReegJxReN1XFBEd54BoXDd1H+EKuc7gdkQ5707UYzILqT+90MwG+89uY5iy7B8IJADELGX",
+	"HjZmLsLtvbJGRBD9k9vO2LXEPLOsc2falZwWL5VKrwa08PmnGffPNL+Pyrhk1mSAlBHd1eNYjjYcMTSN",
+	"RtS6mGbSiLpJi7wThr+JNJgQwgwZN3F3KEnmT5Igs0vNqIVbuKU8jl1seB5QelVkHAM2C7WzooBIiN8R",
+	"Bkmad0YbN4HL3M9zVq0QvrMjLNkFC/Rw5O76fHBJ5g8NkgmyAHe46/B2fDd/quPLJTXxi24Jj+PA0O2a",
+	"fS4FEC5Lv2DvtCpTUdJXV3BJEMI0DPL4+05HNWZ908Ph18VrpObhRyUtS6LmeCVti7tEEO9sl4hNTn8j",
+	"Jr1e3c7mfEhl59tTurDWzkmhKJ/Pyt+HYNiu0epYNJ2oysG2Vqwn09irMk3b2LorUfGmEkryhbjakCD3",
+	"Sn+FG8yU8Qu/4qjTmC8U5djgCFvbrK3UGA7L26a1gzlA+/ed6yvg3pzoRH8/fstGvRG5h8svfgbFwv61",
+	"L6shB7vlhPUDsWo4w9uGoIW59LYCR6O7Q6Bo5EMUhIYbA1N4/3h8ODndbc/R2d+xTxQCZ0sOT5nDuUL+",
+	"nXNnMBxTiLbd8mdiVETcsSM7ETCnXVN84u9tKy95tyR0oCApygWlqEX65XdTdQi97Buvppr6I/CE6I/5",
+	"j5sGlIfB2MGqtUj8wNRx5hG0zfINqPzNV/KjjSwdpAnxM3NPcqWwZy5CtF7UbwSivLces1helb0OCUpW",
+	"9QZO1KEFCPeDBFhp6ldFYOEaRSFU1ryQMFLa75fbrCoJkxcMQM91L5z2iBIEVXXHfrGnhZI9J04dP/L5",
+	"iUPdR850H+060X3yzPETJzu/+K8zX3R295z8C+4Mh8MrI3et8T9gwsveTzsZCjXugrHCehkuNcs3GgsP",
+	"yB2ce2CS9J/FWQF3bdQF9WJLmcFs3gXrxJyottFWmPXL65uPIceA3sp9dTMjXdTdeMBdv9eQz3zwew2b",
+	"RnAUmzDElm849EDokx5n9Z/G8iLrb83OwmmxsZDGwrwiCjx2zq23sDn/VyevEKO8HGBaZc15peVUNAzj",
+	"3TbCWMrldhlg/H7iP4QauwEY/qA3cJLEhpO8P177Q5NVjTtKbp49KataIk6HqBNfeWlRuUhEVOPqMv5T",
+	"O2mu2lJfSA5p6kI/0PxVT8d+YfOQ4JIRK4j28fcOM7PCIhUksg01e4nQJEVzWfosNjFdr/ojxhvvJg6i",
+	"Xqxa8fr/DySN/uUCZJUO94qFBB39JO1FG8oZLurEz1rdbv9JL3s+xEWEr5iVcVaGm8zDaaaSxIGdOdPA",
+	"laMrYwgbOEHCK6uelvuyeakY3iRiN4UTJn+sNpViZuRZ4eNo/hN2aALVb0kFXMAF+L/Wu7s6Fj1r4MoD",
+	"xxfiN2eFohvQFaJKX8G9nNljrgrZ4iN1SpWLMdu42u2n7bIxAeglTJGWxO2ftnErR75YJ4Js8m42bhU6",
+	"47+AyviZjJxHe9DG2jV+F0Ebk+noNTffhGr8eD21nG/QVrDC5QedsO9JqZlgBDs9twSAxvLN4fzQIjXU",
+	"//zTGyhccYlVyzQmqBcRqkH9gp0gBGzOZddwjfGs0QdWdRGAcOUyHsQuNuFqFM0Vwxl3NUYAY3uJQD1i",
+	"3QnpougSYt8GKbH+fz//CmnG1fFx9RCHbiPGou1WTAg7v22hTybbEUhMuW+9ekWamW+s3w1kfqfh3xCG",
+	"eO+AgGeLpl8O5LvgC9Rpua9HSZ+TNXI9w3DCh7x5YXMO6wCDucWYxcbWPXgSUjoWmQXHF1BizQCimJO0",
+	"d2/cfE5sLtJ0nuQAQWOtpxN2N0eUVPNSQR1QNAy5a5Z1ypwsvQH3IQP2bvcwt/V7FdSKMYt6L5yXi2pW",
+	"yacQcEIKqfJ3KUTaZKbY5gz3IooXdZ8LlDyt4qbp2EtDm4uQWXCWIPXKVN0lymroEBVsmMH5Tm5L9Vd3",
+	"GXJ0GgeO5zAszXCesbM9aEINjnF6Yww14ui/xhzpY9hJvoJ6//5N4jspl/vk/N5vEin0TaIPm8OfXMCG",
+	"6vA3iW97UcgsEDWrK74GOb0Sbst4Bo/TCw2fCAvQuRKaYBQQQfDX6MyMVdP4vbFKsjFXscdtpV6dADoa",
+	"I/idEcI/zV9u139cth4sE4aBgOEVV2Ec+O97eH44+kkHA1pYZfBZbbyZJpnBtpSi5cMIm/ns6RrqBV5Q",
+	"e1E76u3PKX1SjtDG1XcDMFeEbek9QS31wbHqkxEmTgWmQxHeS34oAkL+x2hjTTwDSR3Kp6VC9pMhaRA+",
+	"D9LYaIwuCXdHbAedVmObPw6ToqRD77IRyK4TAVzm5qpeO8Z03zPpoLsAz0yt9XNzixBQkgHbi69LkGi7",
+	"gg/0bWvyNWkyAnOSfyjklIycOIhvLMF3KjWREuXkRdymvHl4qYSqDeXgB3gxEaNLH2TouspwuBQ2v+IA",
+	"AhN+bu2mtVdkpPV8n9XSA9l8P+oqKpqSVnIqStq6pFm+sbF+l9RWa9ueobe5/NSarglgvQbxj74yK8+2",
+	"pUk5BShQU2KVOmz/GmCMHOrq5JvOkReHvx3+vwMAXSaW/xf3AAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file